@@ -4,6 +4,7 @@ import (
 	"main/internal/database/mongo"
 	"main/internal/database/redis"
 	"main/internal/server"
+	"main/internal/server/service"
 	"main/pkg/config"
 	"os"
 )
@@ -12,8 +13,10 @@ func main() {
 	config.Load()
 	redis.InitRedis()
 	mongo.Init()
+	service.Init()
 
 	err := server.Start(os.Getenv("PORT"))
+	service.Shutdown()
 	if err != nil {
 		panic(err)
 	}
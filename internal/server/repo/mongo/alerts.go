@@ -0,0 +1,106 @@
+package mongo
+
+import (
+	"context"
+	"main/pkg/models"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type Alerts models.Alerts
+type AlertsImpl models.AlertService
+
+func (a *Alerts) CreateRule(rule *models.AlertRule) error {
+	rule.ID = primitive.NewObjectID()
+	rule.CreatedAt = time.Now()
+
+	_, err := a.Collection.InsertOne(context.Background(), rule)
+	return err
+}
+
+func (a *Alerts) ListRules(licenseID primitive.ObjectID) ([]models.AlertRule, error) {
+	return a.findRules(bson.M{"license_id": licenseID})
+}
+
+func (a *Alerts) DeleteRule(licenseID, id primitive.ObjectID) error {
+	filter := bson.M{
+		"_id":        id,
+		"license_id": licenseID,
+	}
+
+	result, err := a.Collection.DeleteOne(context.Background(), filter)
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+
+	return nil
+}
+
+func (a *Alerts) FindRulesForWallet(wallet string) ([]models.AlertRule, error) {
+	return a.findRules(bson.M{"wallet": wallet})
+}
+
+func (a *Alerts) ListAlertedWallets() ([]string, error) {
+	values, err := a.Collection.Distinct(context.Background(), "wallet", bson.M{})
+	if err != nil {
+		return nil, err
+	}
+
+	wallets := make([]string, 0, len(values))
+	for _, value := range values {
+		if wallet, ok := value.(string); ok {
+			wallets = append(wallets, wallet)
+		}
+	}
+
+	return wallets, nil
+}
+
+func (a *Alerts) UpdateRuleState(id primitive.ObjectID, state models.AlertState) error {
+	_, err := a.Collection.UpdateByID(context.Background(), id, bson.M{"$set": bson.M{"state": state}})
+	return err
+}
+
+func (a *Alerts) CreateEvent(event *models.AlertEvent) error {
+	event.ID = primitive.NewObjectID()
+
+	_, err := a.Events.InsertOne(context.Background(), event)
+	return err
+}
+
+func (a *Alerts) ListEvents(licenseID primitive.ObjectID, limit int64) ([]models.AlertEvent, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "fired_at", Value: -1}}).SetLimit(limit)
+
+	cursor, err := a.Events.Find(context.Background(), bson.M{"license_id": licenseID}, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	events := []models.AlertEvent{}
+	if err = cursor.All(context.Background(), &events); err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+func (a *Alerts) findRules(filter bson.M) ([]models.AlertRule, error) {
+	cursor, err := a.Collection.Find(context.Background(), filter)
+	if err != nil {
+		return nil, err
+	}
+
+	rules := []models.AlertRule{}
+	if err = cursor.All(context.Background(), &rules); err != nil {
+		return nil, err
+	}
+
+	return rules, nil
+}
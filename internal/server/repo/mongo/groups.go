@@ -0,0 +1,91 @@
+package mongo
+
+import (
+	"context"
+	"main/pkg/models"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type WalletGroups models.WalletGroups
+type WalletGroupsImpl models.WalletGroupService
+
+func (g *WalletGroups) CreateGroup(group *models.WalletGroup) error {
+	group.ID = primitive.NewObjectID()
+	group.CreatedAt = time.Now()
+	group.UpdatedAt = group.CreatedAt
+
+	_, err := g.Collection.InsertOne(context.Background(), group)
+	return err
+}
+
+func (g *WalletGroups) ListGroups(licenseID primitive.ObjectID) ([]models.WalletGroup, error) {
+	cursor, err := g.Collection.Find(context.Background(), bson.M{"license_id": licenseID})
+	if err != nil {
+		return nil, err
+	}
+
+	groups := []models.WalletGroup{}
+	if err = cursor.All(context.Background(), &groups); err != nil {
+		return nil, err
+	}
+
+	return groups, nil
+}
+
+func (g *WalletGroups) GetGroup(licenseID, id primitive.ObjectID) (*models.WalletGroup, error) {
+	var group models.WalletGroup
+	filter := bson.M{
+		"_id":        id,
+		"license_id": licenseID,
+	}
+
+	err := g.Collection.FindOne(context.Background(), filter).Decode(&group)
+	if err != nil {
+		return nil, err
+	}
+
+	return &group, nil
+}
+
+func (g *WalletGroups) UpdateGroup(group *models.WalletGroup) error {
+	filter := bson.M{
+		"_id":        group.ID,
+		"license_id": group.LicenseID,
+	}
+	update := bson.M{"$set": bson.M{
+		"name":       group.Name,
+		"members":    group.Members,
+		"updated_at": time.Now(),
+	}}
+
+	result, err := g.Collection.UpdateOne(context.Background(), filter, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+
+	return nil
+}
+
+func (g *WalletGroups) DeleteGroup(licenseID, id primitive.ObjectID) error {
+	filter := bson.M{
+		"_id":        id,
+		"license_id": licenseID,
+	}
+
+	result, err := g.Collection.DeleteOne(context.Background(), filter)
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+
+	return nil
+}
@@ -0,0 +1,106 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"main/pkg/models"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type BalanceHistory models.BalanceHistory
+type BalanceHistoryImpl models.BalanceHistoryService
+
+// EnsureCollection creates the time-series collection, or applies a changed retention to
+// an existing one. Retention is enforced by MongoDB through expireAfterSeconds.
+func (b *BalanceHistory) EnsureCollection(retention time.Duration) error {
+	database := b.Collection.Database()
+	expireAfter := int64(retention / time.Second)
+
+	opts := options.CreateCollection().
+		SetTimeSeriesOptions(options.TimeSeries().
+			SetTimeField("observed_at").
+			SetMetaField("wallet").
+			SetGranularity("seconds")).
+		SetExpireAfterSeconds(expireAfter)
+
+	err := database.CreateCollection(context.Background(), b.Collection.Name(), opts)
+	var commandErr mongo.CommandError
+	if errors.As(err, &commandErr) && commandErr.Name == "NamespaceExists" {
+		return database.RunCommand(context.Background(), bson.D{
+			{Key: "collMod", Value: b.Collection.Name()},
+			{Key: "expireAfterSeconds", Value: expireAfter},
+		}).Err()
+	}
+	return err
+}
+
+func (b *BalanceHistory) InsertPoints(points []models.BalancePoint) error {
+	documents := make([]interface{}, len(points))
+	for i := range points {
+		documents[i] = points[i]
+	}
+
+	_, err := b.Collection.InsertMany(context.Background(), documents, options.InsertMany().SetOrdered(false))
+	return err
+}
+
+func (b *BalanceHistory) ListPoints(wallet string, from, to time.Time, limit int64) ([]models.BalancePoint, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "observed_at", Value: 1}}).SetLimit(limit)
+
+	cursor, err := b.Collection.Find(context.Background(), historyFilter(wallet, from, to), opts)
+	if err != nil {
+		return nil, err
+	}
+
+	points := []models.BalancePoint{}
+	if err = cursor.All(context.Background(), &points); err != nil {
+		return nil, err
+	}
+
+	return points, nil
+}
+
+// ListBuckets downsamples the range into buckets of interval, aligned to the Unix epoch.
+func (b *BalanceHistory) ListBuckets(wallet string, from, to time.Time, interval time.Duration) ([]models.BalanceBucket, error) {
+	intervalMs := interval.Milliseconds()
+	bucketStart := bson.M{"$toDate": bson.M{"$subtract": bson.A{
+		bson.M{"$toLong": "$observed_at"},
+		bson.M{"$mod": bson.A{bson.M{"$toLong": "$observed_at"}, intervalMs}},
+	}}}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: historyFilter(wallet, from, to)}},
+		{{Key: "$sort", Value: bson.D{{Key: "observed_at", Value: 1}}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":     bucketStart,
+			"min":     bson.M{"$min": "$lamports"},
+			"max":     bson.M{"$max": "$lamports"},
+			"last":    bson.M{"$last": "$lamports"},
+			"samples": bson.M{"$sum": 1},
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "_id", Value: 1}}}},
+	}
+
+	cursor, err := b.Collection.Aggregate(context.Background(), pipeline)
+	if err != nil {
+		return nil, err
+	}
+
+	buckets := []models.BalanceBucket{}
+	if err = cursor.All(context.Background(), &buckets); err != nil {
+		return nil, err
+	}
+
+	return buckets, nil
+}
+
+func historyFilter(wallet string, from, to time.Time) bson.M {
+	return bson.M{
+		"wallet":      wallet,
+		"observed_at": bson.M{"$gte": from, "$lt": to},
+	}
+}
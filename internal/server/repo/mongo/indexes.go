@@ -0,0 +1,33 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ensureTTLIndex creates the TTL index that drops documents once key is older than
+// retention, or applies a changed retention to the existing index.
+func ensureTTLIndex(collection *mongo.Collection, key string, retention time.Duration) error {
+	expireAfter := int32(retention / time.Second)
+
+	_, err := collection.Indexes().CreateOne(context.Background(), mongo.IndexModel{
+		Keys:    bson.D{{Key: key, Value: 1}},
+		Options: options.Index().SetExpireAfterSeconds(expireAfter),
+	})
+	var commandErr mongo.CommandError
+	if errors.As(err, &commandErr) && commandErr.Name == "IndexOptionsConflict" {
+		return collection.Database().RunCommand(context.Background(), bson.D{
+			{Key: "collMod", Value: collection.Name()},
+			{Key: "index", Value: bson.D{
+				{Key: "keyPattern", Value: bson.D{{Key: key, Value: 1}}},
+				{Key: "expireAfterSeconds", Value: expireAfter},
+			}},
+		}).Err()
+	}
+	return err
+}
@@ -0,0 +1,53 @@
+package mongo
+
+import (
+	"context"
+	"main/pkg/models"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type LicensePayments models.LicensePayments
+type LicensePaymentsImpl models.LicensePaymentService
+
+// EnsureIndexes creates the unique signature index that guards against double crediting.
+func (l *LicensePayments) EnsureIndexes() error {
+	_, err := l.Collection.Indexes().CreateOne(context.Background(), mongo.IndexModel{
+		Keys:    bson.D{{Key: "signature", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	return err
+}
+
+func (l *LicensePayments) RecordAppliedPayment(payment *models.AppliedPayment) (*models.AppliedPayment, error) {
+	payment.ID = primitive.NewObjectID()
+	payment.RecordedAt = time.Now()
+
+	filter := bson.M{"signature": payment.Signature}
+	update := bson.M{"$setOnInsert": payment}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+
+	var stored models.AppliedPayment
+	err := l.Collection.FindOneAndUpdate(context.Background(), filter, update, opts).Decode(&stored)
+	if mongo.IsDuplicateKeyError(err) {
+		// a concurrent upsert of the same signature won, read what it stored
+		err = l.Collection.FindOne(context.Background(), filter).Decode(&stored)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &stored, nil
+}
+
+func (l *LicensePayments) MarkPaymentApplied(signature string) error {
+	_, err := l.Collection.UpdateOne(context.Background(),
+		bson.M{"signature": signature},
+		bson.M{"$set": bson.M{"applied_at": time.Now()}},
+	)
+	return err
+}
@@ -0,0 +1,35 @@
+package mongo
+
+import (
+	"context"
+	"main/pkg/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type LicenseKeyRotations models.LicenseKeyRotations
+type LicenseKeyRotationsImpl models.LicenseKeyRotationService
+
+func (r *LicenseKeyRotations) RecordRotation(rotation *models.KeyRotation) error {
+	rotation.ID = primitive.NewObjectID()
+
+	_, err := r.Collection.InsertOne(context.Background(), rotation)
+	return err
+}
+
+func (r *LicenseKeyRotations) ListRotations(licenseID primitive.ObjectID) ([]models.KeyRotation, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "rotated_at", Value: -1}})
+	cursor, err := r.Collection.Find(context.Background(), bson.M{"license_id": licenseID}, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	rotations := []models.KeyRotation{}
+	if err = cursor.All(context.Background(), &rotations); err != nil {
+		return nil, err
+	}
+
+	return rotations, nil
+}
@@ -2,35 +2,143 @@ package mongo
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"main/pkg/models"
+	"regexp"
 	"time"
 
-	"github.com/google/uuid"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type LicenseKey models.LicenseKey
 type LicenseKeyImpl models.LicenseKeyService
 
-func (l *LicenseKey) CreateLicense(name string, expiry *time.Time, limit *int64) (*models.License, error) {
-	key, err := uuid.NewUUID()
+const (
+	licenseKeyPrefix = "sk_"
+	// licenseKeyPrefixLength is how much of a key is kept in clear to tell keys apart.
+	licenseKeyPrefixLength = 11
+)
+
+// EnsureIndexes creates the unique key_hash index and the index on retired keys. Purchased
+// licenses have no key until their buyer claims it, so the unique index only covers
+// documents with a hash.
+func (l *LicenseKey) EnsureIndexes() error {
+	_, err := l.Collection.Indexes().CreateMany(context.Background(), []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "key_hash", Value: 1}},
+			Options: options.Index().
+				SetUnique(true).
+				SetPartialFilterExpression(bson.M{"key_hash": bson.M{"$exists": true}}),
+		},
+		{
+			Keys: bson.D{{Key: "retired_keys.key_hash", Value: 1}},
+		},
+	})
+	return err
+}
+
+// MigratePlaintextKeys replaces the plaintext key of licenses created before keys were
+// hashed with its digest and prefix. It returns the number of migrated licenses.
+func (l *LicenseKey) MigratePlaintextKeys() (int, error) {
+	cursor, err := l.Collection.Find(context.Background(), bson.M{"key": bson.M{"$exists": true}})
 	if err != nil {
-		return nil, err
+		return 0, err
+	}
+	defer cursor.Close(context.Background())
+
+	migrated := 0
+	for cursor.Next(context.Background()) {
+		var legacy struct {
+			ID  primitive.ObjectID `bson:"_id"`
+			Key string             `bson:"key"`
+		}
+		if err = cursor.Decode(&legacy); err != nil {
+			return migrated, err
+		}
+		if err = l.migratePlaintextKey(legacy.ID, legacy.Key); err != nil {
+			return migrated, err
+		}
+		migrated++
 	}
 
-	apiKey := &models.License{
-		ID:         primitive.NewObjectID(),
-		Key:        key.String(),
-		Name:       name,
-		CreatedAt:  time.Now(),
-		ExpiresAt:  expiry,
-		UsageCount: 0,
-		UsageLimit: limit,
+	return migrated, cursor.Err()
+}
+
+func (l *LicenseKey) migratePlaintextKey(id primitive.ObjectID, key string) error {
+	update := bson.M{
+		"$set":   bson.M{"key_hash": HashLicenseKey(key), "key_prefix": LicenseKeyPrefixOf(key)},
+		"$unset": bson.M{"key": ""},
 	}
 
-	_, err = l.Collection.InsertOne(context.Background(), apiKey)
+	_, err := l.Collection.UpdateOne(context.Background(), bson.M{"_id": id, "key": key}, update)
+	return err
+}
+
+func (l *LicenseKey) GrantMissingScopes(scopes []string) (int64, error) {
+	filter := bson.M{"scopes": bson.M{"$exists": false}}
+	update := bson.M{"$set": bson.M{"scopes": scopes}}
+
+	result, err := l.Collection.UpdateMany(context.Background(), filter, update)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.ModifiedCount, nil
+}
+
+func (l *LicenseKey) GrantScopesOnce(name string, scopes []string) (int64, error) {
+	err := l.Migrations.FindOne(context.Background(), bson.M{"_id": name}).Err()
+	if err == nil {
+		return 0, nil
+	}
+	if !errors.Is(err, mongo.ErrNoDocuments) {
+		return 0, err
+	}
+
+	// $addToSet keeps this safe to repeat if recording the migration fails
+	filter := bson.M{"scopes": bson.M{"$exists": true}}
+	update := bson.M{"$addToSet": bson.M{"scopes": bson.M{"$each": scopes}}}
+	result, err := l.Collection.UpdateMany(context.Background(), filter, update)
+	if err != nil {
+		return 0, err
+	}
+
+	_, err = l.Migrations.InsertOne(context.Background(), bson.M{"_id": name, "applied_at": time.Now()})
+	if err != nil && !mongo.IsDuplicateKeyError(err) {
+		return result.ModifiedCount, err
+	}
+
+	return result.ModifiedCount, nil
+}
+
+// CreateLicense stores a new active license. With issueKey the plaintext key is set on the
+// returned license, the only time it is available. Without it the license has no key
+// until IssueLicenseKey is called.
+func (l *LicenseKey) CreateLicense(apiKey *models.License, issueKey bool) (*models.License, error) {
+	if apiKey.ID.IsZero() {
+		apiKey.ID = primitive.NewObjectID()
+	}
+	apiKey.CreatedAt = time.Now()
+	apiKey.UsageCount = 0
+	apiKey.IsActive = true
+
+	if issueKey {
+		key, err := newLicenseKey()
+		if err != nil {
+			return nil, err
+		}
+		apiKey.Key = key
+		apiKey.KeyHash = HashLicenseKey(key)
+		apiKey.KeyPrefix = LicenseKeyPrefixOf(key)
+	}
+
+	_, err := l.Collection.InsertOne(context.Background(), apiKey)
 	if err != nil {
 		return nil, err
 	}
@@ -38,33 +146,336 @@ func (l *LicenseKey) CreateLicense(name string, expiry *time.Time, limit *int64)
 	return apiKey, nil
 }
 
-func (l *LicenseKey) ValidateLicense(key string) (*models.License, error) {
-	var apiKey models.License
+// IssueLicenseKey generates the key of a license created without one and returns it in
+// plaintext. It fails with models.ErrLicenseKeyIssued once the license has a key.
+func (l *LicenseKey) IssueLicenseKey(id primitive.ObjectID) (string, error) {
+	key, err := newLicenseKey()
+	if err != nil {
+		return "", err
+	}
+
 	filter := bson.M{
-		"key":       key,
-		"is_active": true,
+		"_id":      id,
+		"key_hash": bson.M{"$exists": false},
+		"key":      bson.M{"$exists": false},
 	}
+	update := bson.M{"$set": bson.M{"key_hash": HashLicenseKey(key), "key_prefix": LicenseKeyPrefixOf(key)}}
 
-	err := l.Collection.FindOne(context.Background(), filter).Decode(&apiKey)
+	result, err := l.Collection.UpdateOne(context.Background(), filter, update)
 	if err != nil {
-		return nil, err
+		return "", err
+	}
+	if result.MatchedCount == 0 {
+		return "", models.ErrLicenseKeyIssued
 	}
 
-	if apiKey.ExpiresAt != nil && time.Now().After(*apiKey.ExpiresAt) {
-		return nil, mongo.ErrNoDocuments
+	return key, nil
+}
+
+// RotateLicenseKey swaps in a new key in a single update. Retired keys past their grace
+// period are dropped at the same time, and with a zero grace the old key is not kept.
+func (l *LicenseKey) RotateLicenseKey(id primitive.ObjectID, grace time.Duration) (string, *models.License, error) {
+	key, err := newLicenseKey()
+	if err != nil {
+		return "", nil, err
+	}
+
+	now := time.Now()
+	retired := bson.A{}
+	if grace > 0 {
+		retired = append(retired, bson.M{
+			"key_hash":   "$key_hash",
+			"key_prefix": "$key_prefix",
+			"retired_at": now,
+			"expires_at": now.Add(grace),
+		})
+	}
+	filter := bson.M{
+		"_id":        id,
+		"key_hash":   bson.M{"$exists": true},
+		"revoked_at": bson.M{"$exists": false},
 	}
+	update := bson.A{bson.M{"$set": bson.M{
+		"key_hash":   HashLicenseKey(key),
+		"key_prefix": LicenseKeyPrefixOf(key),
+		"retired_keys": bson.M{"$concatArrays": bson.A{
+			bson.M{"$filter": bson.M{
+				"input": bson.M{"$ifNull": bson.A{"$retired_keys", bson.A{}}},
+				"cond":  bson.M{"$gt": bson.A{"$$this.expires_at", now}},
+			}},
+			retired,
+		}},
+	}}}
 
-	if apiKey.UsageLimit != nil && apiKey.UsageCount >= *apiKey.UsageLimit {
-		return nil, mongo.ErrNoDocuments
+	var previous models.License
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.Before)
+	if err = l.Collection.FindOneAndUpdate(context.Background(), filter, update, opts).Decode(&previous); err != nil {
+		return "", nil, err
 	}
 
-	return &apiKey, nil
+	return key, &previous, nil
 }
 
-func (l *LicenseKey) IncrementUsage(key string) error {
-	filter := bson.M{"key": key}
-	update := bson.M{"$inc": bson.M{"usage_count": 1}}
+// ChargeUsage adds the credits in one conditional update, so concurrent requests cannot
+// spend more than the usage limit between them.
+func (l *LicenseKey) ChargeUsage(id primitive.ObjectID, credits int64) (*models.License, error) {
+	filter := bson.M{
+		"_id": id,
+		"$or": bson.A{
+			bson.M{"usage_limit": nil},
+			bson.M{"$expr": bson.M{"$lte": bson.A{bson.M{"$add": bson.A{"$usage_count", credits}}, "$usage_limit"}}},
+		},
+	}
+	update := bson.M{"$inc": bson.M{"usage_count": credits}}
 
-	_, err := l.Collection.UpdateOne(context.Background(), filter, update)
+	var license models.License
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	err := l.Collection.FindOneAndUpdate(context.Background(), filter, update, opts).Decode(&license)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, models.ErrInsufficientCredits
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &license, nil
+}
+
+// AddUsage adds credits charged elsewhere, which already checked them against the limit.
+func (l *LicenseKey) AddUsage(id primitive.ObjectID, credits int64) error {
+	update := bson.M{"$inc": bson.M{"usage_count": credits}}
+
+	_, err := l.Collection.UpdateOne(context.Background(), bson.M{"_id": id}, update)
 	return err
 }
+
+// GetLicenseByKey looks the license up by the digest of the key, which is either the
+// current key or a retired key still in its grace period. A license still holding the key
+// in plaintext, written by an instance that predates hashing, is migrated when found.
+func (l *LicenseKey) GetLicenseByKey(key string) (*models.License, error) {
+	hash := HashLicenseKey(key)
+	filter := bson.M{"$or": bson.A{
+		bson.M{"key_hash": hash},
+		bson.M{"retired_keys": bson.M{"$elemMatch": bson.M{
+			"key_hash":   hash,
+			"expires_at": bson.M{"$gt": time.Now()},
+		}}},
+	}}
+
+	var license models.License
+	err := l.Collection.FindOne(context.Background(), filter).Decode(&license)
+	if err == nil {
+		return &license, nil
+	}
+	if !errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, err
+	}
+
+	if err = l.Collection.FindOne(context.Background(), bson.M{"key": key}).Decode(&license); err != nil {
+		return nil, err
+	}
+	if err = l.migratePlaintextKey(license.ID, key); err != nil {
+		return nil, err
+	}
+	license.KeyHash = HashLicenseKey(key)
+	license.KeyPrefix = LicenseKeyPrefixOf(key)
+
+	return &license, nil
+}
+
+func (l *LicenseKey) GetLicenseByID(id primitive.ObjectID) (*models.License, error) {
+	var license models.License
+	err := l.Collection.FindOne(context.Background(), bson.M{"_id": id}).Decode(&license)
+	if err != nil {
+		return nil, err
+	}
+
+	return &license, nil
+}
+
+func (l *LicenseKey) GetLicensesByID(ids []primitive.ObjectID) ([]models.License, error) {
+	cursor, err := l.Collection.Find(context.Background(), bson.M{"_id": bson.M{"$in": ids}})
+	if err != nil {
+		return nil, err
+	}
+
+	licenses := []models.License{}
+	if err = cursor.All(context.Background(), &licenses); err != nil {
+		return nil, err
+	}
+
+	return licenses, nil
+}
+
+// TopUpLicense raises the usage limit and pushes the expiry out from whichever is later,
+// now or the current expiry. Unlimited usage and licenses without expiry are left as is.
+func (l *LicenseKey) TopUpLicense(id primitive.ObjectID, signature string, usage int64, extension time.Duration) (*models.License, error) {
+	license, err := l.GetLicenseByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	set := bson.M{}
+	if license.ExpiresAt != nil {
+		base := time.Now()
+		if license.ExpiresAt.After(base) {
+			base = *license.ExpiresAt
+		}
+		set["expires_at"] = base.Add(extension)
+	}
+	update := bson.M{"$push": bson.M{"top_ups": signature}}
+	if len(set) > 0 {
+		update["$set"] = set
+	}
+	if license.UsageLimit != nil {
+		update["$inc"] = bson.M{"usage_limit": usage}
+	}
+
+	filter := bson.M{"_id": id, "top_ups": bson.M{"$ne": signature}}
+	if _, err = l.Collection.UpdateOne(context.Background(), filter, update); err != nil {
+		return nil, err
+	}
+
+	return l.GetLicenseByID(id)
+}
+
+func (l *LicenseKey) ListLicenses(filter models.LicenseFilter) ([]models.License, int64, error) {
+	query := bson.M{}
+	if filter.Active != nil {
+		query["is_active"] = *filter.Active
+	}
+	if filter.Name != "" {
+		query["name"] = bson.M{"$regex": regexp.QuoteMeta(filter.Name), "$options": "i"}
+	}
+	if filter.Expired != nil {
+		now := time.Now()
+		if *filter.Expired {
+			query["expires_at"] = bson.M{"$lte": now}
+		} else {
+			query["$or"] = bson.A{
+				bson.M{"expires_at": bson.M{"$exists": false}},
+				bson.M{"expires_at": bson.M{"$gt": now}},
+			}
+		}
+	}
+
+	total, err := l.Collection.CountDocuments(context.Background(), query)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}).
+		SetSkip(int64((filter.Page - 1) * filter.Limit)).
+		SetLimit(int64(filter.Limit))
+	cursor, err := l.Collection.Find(context.Background(), query, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	licenses := []models.License{}
+	if err = cursor.All(context.Background(), &licenses); err != nil {
+		return nil, 0, err
+	}
+
+	return licenses, total, nil
+}
+
+func (l *LicenseKey) UpdateLicense(id primitive.ObjectID, request models.UpdateLicenseRequest) (*models.License, error) {
+	set := bson.M{}
+	unset := bson.M{}
+	if request.Name != nil {
+		set["name"] = *request.Name
+	}
+	if request.ExpiresAt.Set {
+		if request.ExpiresAt.Value != nil {
+			set["expires_at"] = *request.ExpiresAt.Value
+		} else {
+			unset["expires_at"] = ""
+		}
+	}
+	if request.UsageLimit.Set {
+		if request.UsageLimit.Value != nil {
+			set["usage_limit"] = *request.UsageLimit.Value
+		} else {
+			unset["usage_limit"] = ""
+		}
+	}
+	if request.IsActive != nil {
+		set["is_active"] = *request.IsActive
+	}
+	if request.Scopes != nil {
+		set["scopes"] = *request.Scopes
+	}
+	if request.Plan != nil {
+		if *request.Plan != "" {
+			set["plan"] = *request.Plan
+		} else {
+			unset["plan"] = ""
+		}
+	}
+
+	update := bson.M{}
+	if len(set) > 0 {
+		update["$set"] = set
+	}
+	if len(unset) > 0 {
+		update["$unset"] = unset
+	}
+	if len(update) == 0 {
+		return l.GetLicenseByID(id)
+	}
+
+	// a revoked license only matches when the update leaves it inactive
+	filter := bson.M{"_id": id}
+	if request.IsActive != nil && *request.IsActive {
+		filter["revoked_at"] = bson.M{"$exists": false}
+	}
+
+	var license models.License
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	if err := l.Collection.FindOneAndUpdate(context.Background(), filter, update, opts).Decode(&license); err != nil {
+		return nil, err
+	}
+
+	return &license, nil
+}
+
+// RevokeLicense deactivates the license for good. Revoking it again keeps the original
+// revocation time.
+func (l *LicenseKey) RevokeLicense(id primitive.ObjectID) (*models.License, error) {
+	now := time.Now()
+	filter := bson.M{"_id": id}
+	update := bson.A{bson.M{"$set": bson.M{
+		"is_active":  false,
+		"revoked_at": bson.M{"$ifNull": bson.A{"$revoked_at", now}},
+	}}}
+
+	var license models.License
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	if err := l.Collection.FindOneAndUpdate(context.Background(), filter, update, opts).Decode(&license); err != nil {
+		return nil, err
+	}
+
+	return &license, nil
+}
+
+func newLicenseKey() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return licenseKeyPrefix + hex.EncodeToString(buf), nil
+}
+
+// HashLicenseKey returns the SHA-256 digest stored in place of the key. Keys carry 256
+// bits of randomness, so an unsalted fast hash is enough to make a leaked digest useless.
+func HashLicenseKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// LicenseKeyPrefixOf returns the part of the key kept in clear to tell keys apart.
+func LicenseKeyPrefixOf(key string) string {
+	return key[:min(len(key), licenseKeyPrefixLength)]
+}
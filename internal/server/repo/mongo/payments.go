@@ -0,0 +1,50 @@
+package mongo
+
+import (
+	"context"
+	"main/pkg/models"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type Payments models.Payments
+type PaymentsImpl models.PaymentService
+
+func (p *Payments) CreatePayment(payment *models.PaymentRequest) error {
+	payment.ID = primitive.NewObjectID()
+	payment.CreatedAt = time.Now()
+
+	_, err := p.Collection.InsertOne(context.Background(), payment)
+	return err
+}
+
+func (p *Payments) GetPayment(reference string) (*models.PaymentRequest, error) {
+	var payment models.PaymentRequest
+	err := p.Collection.FindOne(context.Background(), bson.M{"reference": reference}).Decode(&payment)
+	if err != nil {
+		return nil, err
+	}
+
+	return &payment, nil
+}
+
+func (p *Payments) UpdatePayment(payment *models.PaymentRequest) error {
+	_, err := p.Collection.ReplaceOne(context.Background(), bson.M{"_id": payment.ID}, payment)
+	return err
+}
+
+func (p *Payments) ListPendingPayments() ([]models.PaymentRequest, error) {
+	cursor, err := p.Collection.Find(context.Background(), bson.M{"status": models.PaymentStatusPending})
+	if err != nil {
+		return nil, err
+	}
+
+	var payments []models.PaymentRequest
+	if err = cursor.All(context.Background(), &payments); err != nil {
+		return nil, err
+	}
+
+	return payments, nil
+}
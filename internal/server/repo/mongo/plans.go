@@ -0,0 +1,79 @@
+package mongo
+
+import (
+	"context"
+	"main/pkg/models"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type Plans models.Plans
+type PlansImpl models.PlanService
+
+func (p *Plans) GetPlan(id string) (*models.Plan, error) {
+	var plan models.Plan
+	err := p.Collection.FindOne(context.Background(), bson.M{"_id": id}).Decode(&plan)
+	if err != nil {
+		return nil, err
+	}
+
+	return &plan, nil
+}
+
+func (p *Plans) ListPlans() ([]models.Plan, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "_id", Value: 1}})
+	cursor, err := p.Collection.Find(context.Background(), bson.M{}, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	plans := []models.Plan{}
+	if err = cursor.All(context.Background(), &plans); err != nil {
+		return nil, err
+	}
+
+	return plans, nil
+}
+
+func (p *Plans) SavePlan(plan *models.Plan) error {
+	plan.UpdatedAt = time.Now()
+
+	opts := options.Replace().SetUpsert(true)
+	_, err := p.Collection.ReplaceOne(context.Background(), bson.M{"_id": plan.ID}, plan, opts)
+	return err
+}
+
+func (p *Plans) CreatePlanIfMissing(plan *models.Plan) error {
+	plan.UpdatedAt = time.Now()
+
+	opts := options.Update().SetUpsert(true)
+	_, err := p.Collection.UpdateOne(context.Background(), bson.M{"_id": plan.ID}, bson.M{"$setOnInsert": plan}, opts)
+	return err
+}
+
+func (p *Plans) SetMissingWatchedWalletLimit(limit int) (int64, error) {
+	filter := bson.M{"max_watched_wallets": bson.M{"$exists": false}}
+	update := bson.M{"$set": bson.M{"max_watched_wallets": limit}}
+
+	result, err := p.Collection.UpdateMany(context.Background(), filter, update)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.ModifiedCount, nil
+}
+
+func (p *Plans) DeletePlan(id string) error {
+	result, err := p.Collection.DeleteOne(context.Background(), bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+
+	return nil
+}
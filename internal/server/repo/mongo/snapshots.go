@@ -0,0 +1,139 @@
+package mongo
+
+import (
+	"context"
+	"main/pkg/models"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type Snapshots models.Snapshots
+type SnapshotsImpl models.SnapshotService
+
+// EnsureIndexes creates the index the holder exports are read through, the index that
+// allows one pending job per license and the TTL indexes that drop jobs and holders once
+// they are older than retention.
+func (s *Snapshots) EnsureIndexes(retention time.Duration) error {
+	_, err := s.Holders.Indexes().CreateOne(context.Background(), mongo.IndexModel{
+		Keys: bson.D{{Key: "snapshot_id", Value: 1}, {Key: "owner", Value: 1}},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = s.Collection.Indexes().CreateOne(context.Background(), mongo.IndexModel{
+		Keys: bson.D{{Key: "license_id", Value: 1}},
+		Options: options.Index().SetUnique(true).
+			SetPartialFilterExpression(bson.M{"status": models.SnapshotStatusPending}),
+	})
+	if err != nil {
+		return err
+	}
+	if err = ensureTTLIndex(s.Collection, "created_at", retention); err != nil {
+		return err
+	}
+
+	return ensureTTLIndex(s.Holders, "created_at", retention)
+}
+
+func (s *Snapshots) CreateSnapshot(snapshot *models.Snapshot) error {
+	snapshot.ID = primitive.NewObjectID()
+	snapshot.CreatedAt = time.Now()
+
+	_, err := s.Collection.InsertOne(context.Background(), snapshot)
+	return err
+}
+
+func (s *Snapshots) GetSnapshot(licenseID, id primitive.ObjectID) (*models.Snapshot, error) {
+	var snapshot models.Snapshot
+	filter := bson.M{
+		"_id":        id,
+		"license_id": licenseID,
+	}
+
+	err := s.Collection.FindOne(context.Background(), filter).Decode(&snapshot)
+	if err != nil {
+		return nil, err
+	}
+
+	return &snapshot, nil
+}
+
+func (s *Snapshots) ListSnapshots(licenseID primitive.ObjectID) ([]models.Snapshot, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
+
+	cursor, err := s.Collection.Find(context.Background(), bson.M{"license_id": licenseID}, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshots := []models.Snapshot{}
+	if err = cursor.All(context.Background(), &snapshots); err != nil {
+		return nil, err
+	}
+
+	return snapshots, nil
+}
+
+func (s *Snapshots) UpdateSnapshot(snapshot *models.Snapshot) error {
+	_, err := s.Collection.ReplaceOne(context.Background(), bson.M{"_id": snapshot.ID}, snapshot)
+	return err
+}
+
+func (s *Snapshots) ListUnfinishedSnapshots() ([]models.Snapshot, error) {
+	filter := bson.M{"status": bson.M{"$in": bson.A{models.SnapshotStatusPending, models.SnapshotStatusRunning}}}
+
+	cursor, err := s.Collection.Find(context.Background(), filter)
+	if err != nil {
+		return nil, err
+	}
+
+	var snapshots []models.Snapshot
+	if err = cursor.All(context.Background(), &snapshots); err != nil {
+		return nil, err
+	}
+
+	return snapshots, nil
+}
+
+func (s *Snapshots) DeleteHolders(snapshotID primitive.ObjectID) error {
+	_, err := s.Holders.DeleteMany(context.Background(), bson.M{"snapshot_id": snapshotID})
+	return err
+}
+
+func (s *Snapshots) InsertHolders(holders []models.SnapshotHolder) error {
+	now := time.Now()
+	documents := make([]interface{}, len(holders))
+	for i := range holders {
+		holders[i].CreatedAt = now
+		documents[i] = holders[i]
+	}
+
+	_, err := s.Holders.InsertMany(context.Background(), documents)
+	return err
+}
+
+func (s *Snapshots) EachHolder(snapshotID primitive.ObjectID, fn func(holder models.SnapshotHolder) error) error {
+	opts := options.Find().SetSort(bson.D{{Key: "owner", Value: 1}})
+
+	cursor, err := s.Holders.Find(context.Background(), bson.M{"snapshot_id": snapshotID}, opts)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(context.Background())
+
+	for cursor.Next(context.Background()) {
+		var holder models.SnapshotHolder
+		if err = cursor.Decode(&holder); err != nil {
+			return err
+		}
+		if err = fn(holder); err != nil {
+			return err
+		}
+	}
+
+	return cursor.Err()
+}
@@ -0,0 +1,42 @@
+package mongo
+
+import (
+	"context"
+	"main/pkg/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type Tokens models.Tokens
+type TokensImpl models.TokenService
+
+func (t *Tokens) GetToken(mint string) (*models.TokenInfo, error) {
+	var token models.TokenInfo
+
+	err := t.Collection.FindOne(context.Background(), bson.M{"_id": mint}).Decode(&token)
+	if err != nil {
+		return nil, err
+	}
+
+	return &token, nil
+}
+
+func (t *Tokens) GetTokens(mints []string) ([]models.TokenInfo, error) {
+	cursor, err := t.Collection.Find(context.Background(), bson.M{"_id": bson.M{"$in": mints}})
+	if err != nil {
+		return nil, err
+	}
+
+	var tokens []models.TokenInfo
+	if err = cursor.All(context.Background(), &tokens); err != nil {
+		return nil, err
+	}
+
+	return tokens, nil
+}
+
+func (t *Tokens) SaveToken(token *models.TokenInfo) error {
+	_, err := t.Collection.ReplaceOne(context.Background(), bson.M{"_id": token.Mint}, token, options.Replace().SetUpsert(true))
+	return err
+}
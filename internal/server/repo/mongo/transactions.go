@@ -0,0 +1,80 @@
+package mongo
+
+import (
+	"context"
+	"main/pkg/models"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type Transactions models.Transactions
+type TransactionsImpl models.TransactionService
+
+func (t *Transactions) CreateTransaction(record *models.TransactionRecord) error {
+	record.ID = primitive.NewObjectID()
+	record.CreatedAt = time.Now()
+	record.UpdatedAt = record.CreatedAt
+
+	_, err := t.Collection.InsertOne(context.Background(), record)
+	return err
+}
+
+func (t *Transactions) GetTransaction(ownerID primitive.ObjectID, signature string) (*models.TransactionRecord, error) {
+	var record models.TransactionRecord
+	err := t.Collection.FindOne(context.Background(), bson.M{"signature": signature, "owner_id": ownerID}).Decode(&record)
+	if err != nil {
+		return nil, err
+	}
+
+	return &record, nil
+}
+
+func (t *Transactions) UpdateTransaction(record *models.TransactionRecord) error {
+	record.UpdatedAt = time.Now()
+
+	_, err := t.Collection.ReplaceOne(context.Background(), bson.M{"_id": record.ID}, record)
+	return err
+}
+
+func (t *Transactions) ExpireTransaction(record *models.TransactionRecord) (bool, error) {
+	record.UpdatedAt = time.Now()
+
+	filter := bson.M{"_id": record.ID, "status": bson.M{"$in": []models.TransactionStatus{
+		models.TransactionStatusPending,
+		models.TransactionStatusProcessed,
+		models.TransactionStatusConfirmed,
+	}}}
+	update := bson.M{"$set": bson.M{
+		"status":     record.Status,
+		"error":      record.Error,
+		"updated_at": record.UpdatedAt,
+	}}
+	result, err := t.Collection.UpdateOne(context.Background(), filter, update)
+	if err != nil {
+		return false, err
+	}
+
+	return result.ModifiedCount == 1, nil
+}
+
+func (t *Transactions) ListPendingTransactions() ([]models.TransactionRecord, error) {
+	filter := bson.M{"status": bson.M{"$in": []models.TransactionStatus{
+		models.TransactionStatusPending,
+		models.TransactionStatusProcessed,
+		models.TransactionStatusConfirmed,
+	}}}
+
+	cursor, err := t.Collection.Find(context.Background(), filter)
+	if err != nil {
+		return nil, err
+	}
+
+	var records []models.TransactionRecord
+	if err = cursor.All(context.Background(), &records); err != nil {
+		return nil, err
+	}
+
+	return records, nil
+}
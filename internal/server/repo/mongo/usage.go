@@ -0,0 +1,138 @@
+package mongo
+
+import (
+	"context"
+	"main/pkg/models"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type LicenseUsage models.LicenseUsage
+type LicenseUsageImpl models.LicenseUsageService
+
+// EnsureIndexes creates the bucket index and the TTL index that drops buckets once they
+// are older than retention.
+func (u *LicenseUsage) EnsureIndexes(retention time.Duration) error {
+	_, err := u.Collection.Indexes().CreateOne(context.Background(), mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "license_id", Value: 1},
+			{Key: "day", Value: 1},
+			{Key: "endpoint", Value: 1},
+		},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return err
+	}
+
+	return ensureTTLIndex(u.Collection, "day", retention)
+}
+
+// AddUsage upserts every bucket in a single unordered bulk write.
+func (u *LicenseUsage) AddUsage(buckets []models.UsageBucket) error {
+	writes := make([]mongo.WriteModel, len(buckets))
+	for i, bucket := range buckets {
+		writes[i] = mongo.NewUpdateOneModel().
+			SetFilter(bson.M{
+				"license_id": bucket.LicenseID,
+				"day":        bucket.Day,
+				"endpoint":   bucket.Endpoint,
+			}).
+			SetUpdate(bson.M{"$inc": bson.M{
+				"requests": bucket.Requests,
+				"errors":   bucket.Errors,
+				"credits":  bucket.Credits,
+			}}).
+			SetUpsert(true)
+	}
+
+	_, err := u.Collection.BulkWrite(context.Background(), writes, options.BulkWrite().SetOrdered(false))
+	return err
+}
+
+func (u *LicenseUsage) DailyUsage(licenseID primitive.ObjectID, from, to time.Time) ([]models.DailyUsage, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: usageFilter(licenseID, from, to)}},
+		{{Key: "$group", Value: bson.M{
+			"_id":      bson.M{"$dateToString": bson.M{"format": "%Y-%m-%d", "date": "$day"}},
+			"requests": bson.M{"$sum": "$requests"},
+			"errors":   bson.M{"$sum": "$errors"},
+			"credits":  bson.M{"$sum": "$credits"},
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "_id", Value: 1}}}},
+	}
+
+	cursor, err := u.Collection.Aggregate(context.Background(), pipeline)
+	if err != nil {
+		return nil, err
+	}
+
+	days := []models.DailyUsage{}
+	if err = cursor.All(context.Background(), &days); err != nil {
+		return nil, err
+	}
+
+	return days, nil
+}
+
+func (u *LicenseUsage) EndpointUsage(licenseID primitive.ObjectID, from, to time.Time) ([]models.EndpointUsage, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: usageFilter(licenseID, from, to)}},
+		{{Key: "$group", Value: bson.M{
+			"_id":      "$endpoint",
+			"requests": bson.M{"$sum": "$requests"},
+			"errors":   bson.M{"$sum": "$errors"},
+			"credits":  bson.M{"$sum": "$credits"},
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "credits", Value: -1}, {Key: "_id", Value: 1}}}},
+	}
+
+	cursor, err := u.Collection.Aggregate(context.Background(), pipeline)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoints := []models.EndpointUsage{}
+	if err = cursor.All(context.Background(), &endpoints); err != nil {
+		return nil, err
+	}
+
+	return endpoints, nil
+}
+
+func (u *LicenseUsage) LicenseTotals(from, to time.Time) ([]models.LicenseUsageTotal, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"day": bson.M{"$gte": from, "$lte": to}}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":      "$license_id",
+			"requests": bson.M{"$sum": "$requests"},
+			"errors":   bson.M{"$sum": "$errors"},
+			"credits":  bson.M{"$sum": "$credits"},
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "_id", Value: 1}}}},
+	}
+
+	cursor, err := u.Collection.Aggregate(context.Background(), pipeline)
+	if err != nil {
+		return nil, err
+	}
+
+	totals := []models.LicenseUsageTotal{}
+	if err = cursor.All(context.Background(), &totals); err != nil {
+		return nil, err
+	}
+
+	return totals, nil
+}
+
+// usageFilter matches the buckets of the days from up to and including to.
+func usageFilter(licenseID primitive.ObjectID, from, to time.Time) bson.M {
+	return bson.M{
+		"license_id": licenseID,
+		"day":        bson.M{"$gte": from, "$lte": to},
+	}
+}
@@ -0,0 +1,156 @@
+package mongo
+
+import (
+	"context"
+	"main/pkg/models"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type Watchlists models.Watchlists
+type WatchlistsImpl models.WatchlistService
+
+func (w *Watchlists) CreateWatchlist(watchlist *models.Watchlist) error {
+	watchlist.ID = primitive.NewObjectID()
+	watchlist.CreatedAt = time.Now()
+	watchlist.UpdatedAt = watchlist.CreatedAt
+
+	_, err := w.Collection.InsertOne(context.Background(), watchlist)
+	return err
+}
+
+func (w *Watchlists) ListWatchlists(licenseID primitive.ObjectID) ([]models.Watchlist, error) {
+	cursor, err := w.Collection.Find(context.Background(), bson.M{"license_id": licenseID})
+	if err != nil {
+		return nil, err
+	}
+
+	watchlists := []models.Watchlist{}
+	if err = cursor.All(context.Background(), &watchlists); err != nil {
+		return nil, err
+	}
+
+	return watchlists, nil
+}
+
+func (w *Watchlists) GetWatchlist(licenseID, id primitive.ObjectID) (*models.Watchlist, error) {
+	var watchlist models.Watchlist
+	filter := bson.M{
+		"_id":        id,
+		"license_id": licenseID,
+	}
+
+	err := w.Collection.FindOne(context.Background(), filter).Decode(&watchlist)
+	if err != nil {
+		return nil, err
+	}
+
+	return &watchlist, nil
+}
+
+func (w *Watchlists) UpdateWatchlist(watchlist *models.Watchlist) error {
+	filter := bson.M{
+		"_id":        watchlist.ID,
+		"license_id": watchlist.LicenseID,
+	}
+	update := bson.M{"$set": bson.M{
+		"name":       watchlist.Name,
+		"wallets":    watchlist.Wallets,
+		"updated_at": time.Now(),
+	}}
+
+	result, err := w.Collection.UpdateOne(context.Background(), filter, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+
+	return nil
+}
+
+func (w *Watchlists) DeleteWatchlist(licenseID, id primitive.ObjectID) error {
+	filter := bson.M{
+		"_id":        id,
+		"license_id": licenseID,
+	}
+
+	result, err := w.Collection.DeleteOne(context.Background(), filter)
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+
+	return nil
+}
+
+// ListWatchedWallets returns every distinct address across all watchlists.
+func (w *Watchlists) ListWatchedWallets() ([]string, error) {
+	values, err := w.Collection.Distinct(context.Background(), "wallets.address", bson.M{})
+	if err != nil {
+		return nil, err
+	}
+
+	wallets := make([]string, 0, len(values))
+	for _, value := range values {
+		if wallet, ok := value.(string); ok {
+			wallets = append(wallets, wallet)
+		}
+	}
+
+	return wallets, nil
+}
+
+func (w *Watchlists) LockWatchedWallets(licenseID, token primitive.ObjectID, lease time.Duration) (bool, error) {
+	now := time.Now()
+	// a held lease does not match, the upsert then collides with it on _id
+	filter := bson.M{
+		"_id":          licenseID,
+		"locked_until": bson.M{"$lte": now},
+	}
+	update := bson.M{"$set": bson.M{
+		"token":        token,
+		"locked_until": now.Add(lease),
+	}}
+
+	_, err := w.Locks.UpdateOne(context.Background(), filter, update, options.Update().SetUpsert(true))
+	if mongo.IsDuplicateKeyError(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (w *Watchlists) UnlockWatchedWallets(licenseID, token primitive.ObjectID) error {
+	filter := bson.M{
+		"_id":   licenseID,
+		"token": token,
+	}
+
+	_, err := w.Locks.DeleteOne(context.Background(), filter)
+	return err
+}
+
+func (w *Watchlists) FindWatchlistsWithWallet(wallet string) ([]models.Watchlist, error) {
+	cursor, err := w.Collection.Find(context.Background(), bson.M{"wallets.address": wallet})
+	if err != nil {
+		return nil, err
+	}
+
+	var watchlists []models.Watchlist
+	if err = cursor.All(context.Background(), &watchlists); err != nil {
+		return nil, err
+	}
+
+	return watchlists, nil
+}
@@ -0,0 +1,133 @@
+package mongo
+
+import (
+	"context"
+	"main/pkg/models"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type Webhooks models.Webhooks
+type WebhooksImpl models.WebhookService
+
+type WebhookDeliveries models.WebhookDeliveries
+type WebhookDeliveriesImpl models.WebhookDeliveryService
+
+func (w *Webhooks) CreateWebhook(webhook *models.Webhook) error {
+	webhook.ID = primitive.NewObjectID()
+	webhook.CreatedAt = time.Now()
+
+	_, err := w.Collection.InsertOne(context.Background(), webhook)
+	return err
+}
+
+func (w *Webhooks) ListWebhooks(licenseID primitive.ObjectID) ([]models.Webhook, error) {
+	return w.find(bson.M{"license_id": licenseID})
+}
+
+func (w *Webhooks) GetWebhook(licenseID, id primitive.ObjectID) (*models.Webhook, error) {
+	var webhook models.Webhook
+	filter := bson.M{
+		"_id":        id,
+		"license_id": licenseID,
+	}
+
+	err := w.Collection.FindOne(context.Background(), filter).Decode(&webhook)
+	if err != nil {
+		return nil, err
+	}
+
+	return &webhook, nil
+}
+
+func (w *Webhooks) DeleteWebhook(licenseID, id primitive.ObjectID) error {
+	filter := bson.M{
+		"_id":        id,
+		"license_id": licenseID,
+	}
+
+	result, err := w.Collection.DeleteOne(context.Background(), filter)
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+
+	return nil
+}
+
+func (w *Webhooks) ListWebhooksForWatchlists(watchlistIDs []primitive.ObjectID) ([]models.Webhook, error) {
+	return w.find(bson.M{"watchlist_id": bson.M{"$in": watchlistIDs}})
+}
+
+func (w *Webhooks) find(filter bson.M) ([]models.Webhook, error) {
+	cursor, err := w.Collection.Find(context.Background(), filter)
+	if err != nil {
+		return nil, err
+	}
+
+	webhooks := []models.Webhook{}
+	if err = cursor.All(context.Background(), &webhooks); err != nil {
+		return nil, err
+	}
+
+	return webhooks, nil
+}
+
+func (d *WebhookDeliveries) CreateDelivery(delivery *models.WebhookDelivery) error {
+	delivery.ID = primitive.NewObjectID()
+	delivery.CreatedAt = time.Now()
+
+	_, err := d.Collection.InsertOne(context.Background(), delivery)
+	return err
+}
+
+func (d *WebhookDeliveries) UpdateDelivery(delivery *models.WebhookDelivery) error {
+	_, err := d.Collection.ReplaceOne(context.Background(), bson.M{"_id": delivery.ID}, delivery)
+	return err
+}
+
+func (d *WebhookDeliveries) ListDueDeliveries(now time.Time) ([]models.WebhookDelivery, error) {
+	filter := bson.M{
+		"status":          models.DeliveryStatusPending,
+		"next_attempt_at": bson.M{"$lte": now},
+	}
+
+	cursor, err := d.Collection.Find(context.Background(), filter)
+	if err != nil {
+		return nil, err
+	}
+
+	var deliveries []models.WebhookDelivery
+	if err = cursor.All(context.Background(), &deliveries); err != nil {
+		return nil, err
+	}
+
+	return deliveries, nil
+}
+
+func (d *WebhookDeliveries) ListDeliveries(webhookID primitive.ObjectID, limit int64) ([]models.WebhookDelivery, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}).SetLimit(limit)
+
+	cursor, err := d.Collection.Find(context.Background(), bson.M{"webhook_id": webhookID}, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	deliveries := []models.WebhookDelivery{}
+	if err = cursor.All(context.Background(), &deliveries); err != nil {
+		return nil, err
+	}
+
+	return deliveries, nil
+}
+
+func (d *WebhookDeliveries) AddDeadLetter(delivery *models.WebhookDelivery) error {
+	_, err := d.DeadLetters.InsertOne(context.Background(), delivery)
+	return err
+}
@@ -5,45 +5,74 @@ import (
 	"main/internal/database/redis"
 	"main/pkg/models"
 	"time"
+
+	goredis "github.com/redis/go-redis/v9"
 )
 
 type Cache models.Cache
 type CacheService models.CacheImpl
 
+// releaseConcurrencyScript only decrements a count that still exists, so a release after
+// the TTL dropped the key cannot leave a negative count behind, and DECR keeps the TTL.
+var releaseConcurrencyScript = goredis.NewScript(`
+if redis.call("EXISTS", KEYS[1]) == 0 then
+	return 0
+end
+local count = redis.call("DECR", KEYS[1])
+if count <= 0 then
+	redis.call("DEL", KEYS[1])
+end
+return count
+`)
+
 const (
-	IpRequestCountPrefix = "ip_request_count:"
-	WalletPrefix         = "wallet:"
+	RateLimitPrefix    = "rate_limit:"
+	ConcurrencyPrefix  = "concurrency:"
+	WalletPrefix       = "wallet:"
+	PriorityFeesPrefix = "priority_fees:"
+	WalletGroupPrefix  = "wallet_group:"
 )
 
-func (c *Cache) GetIpRequestCount(ip string) (int, error) {
+// AcquireConcurrency counts a request in flight and returns the number in flight. The
+// TTL, refreshed on every request, clears counts left behind by an instance that died
+// before releasing them.
+func (c *Cache) AcquireConcurrency(id string, ttl time.Duration) (int64, error) {
 	ctx := context.Background()
-	key := IpRequestCountPrefix + ip
+	key := ConcurrencyPrefix + id
 
-	val, err := redis.Client.Get(ctx, key).Int()
-	if err != nil {
+	pipe := redis.Client.TxPipeline()
+	count := pipe.Incr(ctx, key)
+	pipe.Expire(ctx, key, ttl)
+	if _, err := pipe.Exec(ctx); err != nil {
 		return 0, err
 	}
 
-	return val, nil
+	return count.Val(), nil
 }
 
-func (c *Cache) IncrementIpRequestCount(ip string) error {
+func (c *Cache) ReleaseConcurrency(id string) error {
 	ctx := context.Background()
-	// will return 0 if key does not exist
-	count, _ := c.GetIpRequestCount(ip)
-	key := IpRequestCountPrefix + ip
-	// set expiry to 10 minutes on first increment
-	if count == 0 {
-		return redis.Client.Set(ctx, key, 1, 10*time.Minute).Err()
-	}
-	return redis.Client.Set(ctx, key, count+1, 0).Err()
+	key := ConcurrencyPrefix + id
+
+	return releaseConcurrencyScript.Run(ctx, redis.Client, []string{key}).Err()
 }
 
 func (c *Cache) SetWallet(wallet, balance string) error {
+	return c.SetWalletWithTTL(wallet, balance, 10*time.Second)
+}
+
+func (c *Cache) SetWalletWithTTL(wallet, balance string, ttl time.Duration) error {
 	ctx := context.Background()
 	key := WalletPrefix + wallet
-	
-	return redis.Client.Set(ctx, key, balance, 10*time.Second).Err()
+
+	return redis.Client.Set(ctx, key, balance, ttl).Err()
+}
+
+func (c *Cache) DeleteWallet(wallet string) error {
+	ctx := context.Background()
+	key := WalletPrefix + wallet
+
+	return redis.Client.Del(ctx, key).Err()
 }
 
 func (c *Cache) GetWallet(wallet string) (string, error) {
@@ -57,3 +86,48 @@ func (c *Cache) GetWallet(wallet string) (string, error) {
 
 	return val, nil
 }
+
+func (c *Cache) SetPriorityFees(accounts, estimate string) error {
+	ctx := context.Background()
+	key := PriorityFeesPrefix + accounts
+
+	return redis.Client.Set(ctx, key, estimate, 5*time.Second).Err()
+}
+
+func (c *Cache) GetPriorityFees(accounts string) (string, error) {
+	ctx := context.Background()
+	key := PriorityFeesPrefix + accounts
+
+	val, err := redis.Client.Get(ctx, key).Result()
+	if err != nil {
+		return "", err
+	}
+
+	return val, nil
+}
+
+func (c *Cache) SetWalletGroup(id, balance string, ttl time.Duration) error {
+	ctx := context.Background()
+	key := WalletGroupPrefix + id
+
+	return redis.Client.Set(ctx, key, balance, ttl).Err()
+}
+
+func (c *Cache) GetWalletGroup(id string) (string, error) {
+	ctx := context.Background()
+	key := WalletGroupPrefix + id
+
+	val, err := redis.Client.Get(ctx, key).Result()
+	if err != nil {
+		return "", err
+	}
+
+	return val, nil
+}
+
+func (c *Cache) DeleteWalletGroup(id string) error {
+	ctx := context.Background()
+	key := WalletGroupPrefix + id
+
+	return redis.Client.Del(ctx, key).Err()
+}
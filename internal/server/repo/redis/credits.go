@@ -0,0 +1,128 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"main/internal/database/redis"
+	"strconv"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+)
+
+const (
+	CreditsPrefix          = "credits:"
+	UnflushedCreditsPrefix = "credits_unflushed:"
+	// UnflushedCreditsSet holds the IDs of licenses with unflushed credits.
+	UnflushedCreditsSet = "credits_unflushed_licenses"
+
+	// creditsTTL drops the spent credits of idle licenses, they are seeded from Mongo again.
+	creditsTTL = time.Hour
+)
+
+var (
+	// KEYS[1] spent credits, KEYS[2] credits not yet flushed to Mongo, KEYS[3] UnflushedCreditsSet
+	// ARGV credits, usage count in Mongo, usage limit or -1, TTL in milliseconds, license ID
+	// Returns {charged, spent}.
+	chargeCreditsScript = goredis.NewScript(`
+local credits = tonumber(ARGV[1])
+local limit = tonumber(ARGV[3])
+-- credits charged in Mongo while Redis was unreachable only show in the usage count
+local spent = math.max(
+	tonumber(redis.call('GET', KEYS[1]) or '0'),
+	tonumber(ARGV[2]) + tonumber(redis.call('GET', KEYS[2]) or '0'))
+
+if limit >= 0 and spent + credits > limit then
+	return {0, spent}
+end
+
+spent = spent + credits
+redis.call('SET', KEYS[1], spent, 'PX', ARGV[4])
+redis.call('INCRBY', KEYS[2], credits)
+redis.call('SADD', KEYS[3], ARGV[5])
+return {1, spent}
+`)
+
+	// KEYS[1] UnflushedCreditsSet
+	// ARGV UnflushedCreditsPrefix
+	// Returns the license IDs alternating with their unflushed credits.
+	takeUnflushedCreditsScript = goredis.NewScript(`
+local ids = redis.call('SMEMBERS', KEYS[1])
+redis.call('DEL', KEYS[1])
+local result = {}
+for _, id in ipairs(ids) do
+	local credits = redis.call('GETDEL', ARGV[1] .. id)
+	if credits then
+		table.insert(result, id)
+		table.insert(result, credits)
+	end
+end
+return result
+`)
+)
+
+// ChargeCredits adds the credits to the license's spent credits unless that would exceed
+// usageLimit, -1 for unlimited. The spent credits are never below usageCount, the count
+// in Mongo, plus the credits not flushed to it yet, so credits charged in Mongo while
+// Redis was unreachable are not spent twice. It returns the credits spent after the
+// charge, or before the refused one.
+func (c *Cache) ChargeCredits(id string, credits, usageCount, usageLimit int64) (int64, bool, error) {
+	ctx := context.Background()
+	keys := []string{CreditsPrefix + id, UnflushedCreditsPrefix + id, UnflushedCreditsSet}
+
+	values, err := chargeCreditsScript.Run(ctx, redis.Client, keys, credits, usageCount, usageLimit, creditsTTL.Milliseconds(), id).Int64Slice()
+	if err != nil {
+		return 0, false, err
+	}
+
+	return values[1], values[0] == 1, nil
+}
+
+// TakeUnflushedCredits removes and returns the credits charged since the last flush, by
+// license ID. Instances flushing at the same time never take the same credits.
+func (c *Cache) TakeUnflushedCredits() (map[string]int64, error) {
+	ctx := context.Background()
+
+	values, err := takeUnflushedCreditsScript.Run(ctx, redis.Client, []string{UnflushedCreditsSet}, UnflushedCreditsPrefix).StringSlice()
+	if err != nil {
+		return nil, err
+	}
+
+	credits := make(map[string]int64, len(values)/2)
+	for i := 0; i+1 < len(values); i += 2 {
+		amount, err := strconv.ParseInt(values[i+1], 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		credits[values[i]] = amount
+	}
+	return credits, nil
+}
+
+// RestoreUnflushedCredits puts back credits that could not be flushed.
+func (c *Cache) RestoreUnflushedCredits(id string, credits int64) error {
+	ctx := context.Background()
+
+	pipe := redis.Client.TxPipeline()
+	pipe.IncrBy(ctx, UnflushedCreditsPrefix+id, credits)
+	pipe.SAdd(ctx, UnflushedCreditsSet, id)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// GetSpentCredits returns the license's spent credits, at least usageCount plus the
+// credits not flushed yet, so usageCount has to be current.
+func (c *Cache) GetSpentCredits(id string, usageCount int64) (int64, error) {
+	ctx := context.Background()
+
+	pipe := redis.Client.Pipeline()
+	spent := pipe.Get(ctx, CreditsPrefix+id)
+	unflushed := pipe.Get(ctx, UnflushedCreditsPrefix+id)
+	if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, goredis.Nil) {
+		return 0, err
+	}
+
+	counted, _ := spent.Int64()
+	pending, _ := unflushed.Int64()
+	return max(counted, usageCount+pending), nil
+}
@@ -0,0 +1,69 @@
+package redis
+
+import (
+	"context"
+	"main/internal/database/redis"
+	"time"
+)
+
+const (
+	LicenseKeyPrefix = "license_key:"
+	LicensePrefix    = "license:"
+	// LicenseInvalidationChannel carries the ID of every license that changed.
+	LicenseInvalidationChannel = "license_invalidations"
+)
+
+// SetLicenseID caches which license a key digest belongs to, an empty id caches that the
+// key is unknown.
+func (c *Cache) SetLicenseID(keyHash, id string, ttl time.Duration) error {
+	ctx := context.Background()
+	key := LicenseKeyPrefix + keyHash
+
+	return redis.Client.Set(ctx, key, id, ttl).Err()
+}
+
+func (c *Cache) GetLicenseID(keyHash string) (string, error) {
+	ctx := context.Background()
+	key := LicenseKeyPrefix + keyHash
+
+	return redis.Client.Get(ctx, key).Result()
+}
+
+func (c *Cache) SetLicense(id string, license []byte, ttl time.Duration) error {
+	ctx := context.Background()
+	key := LicensePrefix + id
+
+	return redis.Client.Set(ctx, key, license, ttl).Err()
+}
+
+func (c *Cache) GetLicense(id string) ([]byte, error) {
+	ctx := context.Background()
+	key := LicensePrefix + id
+
+	return redis.Client.Get(ctx, key).Bytes()
+}
+
+func (c *Cache) DeleteLicense(id string) error {
+	ctx := context.Background()
+	key := LicensePrefix + id
+
+	return redis.Client.Del(ctx, key).Err()
+}
+
+func (c *Cache) PublishLicenseInvalidation(id string) error {
+	ctx := context.Background()
+
+	return redis.Client.Publish(ctx, LicenseInvalidationChannel, id).Err()
+}
+
+// SubscribeLicenseInvalidations calls handler with the ID of every invalidated license.
+// The subscription reconnects on its own, invalidations published while it is down are
+// missed and left to the cache TTL.
+func (c *Cache) SubscribeLicenseInvalidations(handler func(id string)) {
+	subscription := redis.Client.Subscribe(context.Background(), LicenseInvalidationChannel)
+	go func() {
+		for message := range subscription.Channel() {
+			handler(message.Payload)
+		}
+	}()
+}
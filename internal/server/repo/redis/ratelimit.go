@@ -0,0 +1,145 @@
+package redis
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"main/internal/database/redis"
+	"main/pkg/ratelimit"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+)
+
+// rateLimitScript applies several limits at once. It reads the clock with TIME, so
+// instances with skewed clocks share one view of every window, and only counts the
+// request when every limit has room. Each limit takes four ARGV entries:
+//   - sliding_window, limit, window in microseconds, unique member for this request,
+//     on a sorted set of request times in microseconds
+//   - token_bucket, bucket size, refill rate in tokens per microsecond, unused, on a hash
+//     with the tokens left and the time they were counted at in microseconds
+//
+// It returns {allowed, remaining, reset_ms, retry_after_ms} for every limit in turn.
+var rateLimitScript = goredis.NewScript(`
+local time = redis.call('TIME')
+local now = tonumber(time[1]) * 1000000 + tonumber(time[2])
+
+local state = {}
+local admitted = true
+for i, key in ipairs(KEYS) do
+	local base = (i - 1) * 4
+	if ARGV[base + 1] == 'token_bucket' then
+		local capacity = tonumber(ARGV[base + 2])
+		local rate = tonumber(ARGV[base + 3])
+		local stored = redis.call('HMGET', key, 'tokens', 'updated')
+		local tokens = tonumber(stored[1])
+		local updated = tonumber(stored[2])
+		if tokens == nil or updated == nil then
+			tokens = capacity
+			updated = now
+		end
+		state[i] = math.min(capacity, tokens + math.max(0, now - updated) * rate)
+		if state[i] < 1 then
+			admitted = false
+		end
+	else
+		redis.call('ZREMRANGEBYSCORE', key, '-inf', now - tonumber(ARGV[base + 3]))
+		state[i] = redis.call('ZCARD', key)
+		if state[i] >= tonumber(ARGV[base + 2]) then
+			admitted = false
+		end
+	end
+end
+
+local results = {}
+for i, key in ipairs(KEYS) do
+	local base = (i - 1) * 4
+	local allowed = 0
+	local remaining = 0
+	local reset = 0
+	local retry = 0
+	if ARGV[base + 1] == 'token_bucket' then
+		local capacity = tonumber(ARGV[base + 2])
+		local rate = tonumber(ARGV[base + 3])
+		local tokens = state[i]
+		if tokens >= 1 then
+			allowed = 1
+			if admitted then
+				tokens = tokens - 1
+			end
+		else
+			retry = math.ceil((1 - tokens) / rate / 1000)
+		end
+		remaining = math.floor(tokens)
+		reset = math.ceil((capacity - tokens) / rate / 1000)
+		redis.call('HSET', key, 'tokens', tostring(tokens), 'updated', now)
+		redis.call('PEXPIRE', key, math.max(1, reset))
+	else
+		local limit = tonumber(ARGV[base + 2])
+		local window = tonumber(ARGV[base + 3])
+		local count = state[i]
+		if count < limit then
+			allowed = 1
+			if admitted then
+				redis.call('ZADD', key, now, ARGV[base + 4])
+				count = count + 1
+			end
+		end
+		redis.call('PEXPIRE', key, math.ceil(window / 1000))
+		remaining = limit - count
+		local newest = redis.call('ZRANGE', key, -1, -1, 'WITHSCORES')
+		if newest[2] then
+			reset = math.ceil((tonumber(newest[2]) + window - now) / 1000)
+		end
+		if allowed == 0 then
+			local oldest = redis.call('ZRANGE', key, 0, 0, 'WITHSCORES')
+			retry = math.ceil((tonumber(oldest[2]) + window - now) / 1000)
+		end
+	end
+	table.insert(results, allowed)
+	table.insert(results, remaining)
+	table.insert(results, reset)
+	table.insert(results, retry)
+end
+return results
+`)
+
+// AllowRates applies the limits to the keys atomically in a single Lua script.
+func (c *Cache) AllowRates(keys []string, limits []ratelimit.Limit) ([]ratelimit.Result, error) {
+	ctx := context.Background()
+
+	scriptKeys := make([]string, len(limits))
+	args := make([]interface{}, 0, 4*len(limits))
+	for i, limit := range limits {
+		scriptKeys[i] = RateLimitPrefix + string(limit.Algorithm) + ":" + keys[i]
+		if limit.Algorithm == ratelimit.TokenBucket {
+			rate := float64(limit.Limit) / float64(limit.Window.Microseconds())
+			args = append(args, string(limit.Algorithm), limit.Capacity(), rate, "")
+			continue
+		}
+
+		member := make([]byte, 8)
+		if _, err := rand.Read(member); err != nil {
+			return nil, err
+		}
+		args = append(args, string(limit.Algorithm), limit.Limit, limit.Window.Microseconds(), hex.EncodeToString(member))
+	}
+
+	values, err := rateLimitScript.Run(ctx, redis.Client, scriptKeys, args...).Int64Slice()
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]ratelimit.Result, len(limits))
+	for i, limit := range limits {
+		result := values[4*i : 4*i+4]
+		results[i] = ratelimit.Result{
+			Allowed:    result[0] == 1,
+			Limit:      limit.Capacity(),
+			Remaining:  result[1],
+			Reset:      time.Duration(result[2]) * time.Millisecond,
+			RetryAfter: time.Duration(result[3]) * time.Millisecond,
+		}
+	}
+	return results, nil
+}
@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"errors"
+	"main/internal/server/rest/middleware"
+	"main/internal/server/service"
+	"main/pkg/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+func GetAccount(c *gin.Context) {
+	result, err := service.GetAccount(middleware.GetLicense(c), middleware.GetPlan(c), middleware.GetRateLimits(c))
+	if err != nil {
+		c.JSON(500, models.GenericResponse[any]{
+			Object:  nil,
+			Error:   "Failed to load account",
+			Success: false,
+		})
+		return
+	}
+
+	c.JSON(200, models.GenericResponse[*models.Account]{
+		Object:  result,
+		Error:   "",
+		Success: true,
+	})
+}
+
+func GetAccountUsage(c *gin.Context) {
+	result, err := service.GetUsageReport(middleware.GetLicense(c).ID, c.Query("from"), c.Query("to"))
+	if errors.Is(err, service.ErrInvalidUsageRange) {
+		c.JSON(400, models.GenericResponse[any]{
+			Object:  nil,
+			Error:   err.Error(),
+			Success: false,
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(500, models.GenericResponse[any]{
+			Object:  nil,
+			Error:   "Failed to load usage",
+			Success: false,
+		})
+		return
+	}
+
+	c.JSON(200, models.GenericResponse[*models.UsageReport]{
+		Object:  result,
+		Error:   "",
+		Success: true,
+	})
+}
@@ -0,0 +1,355 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"main/internal/server/service"
+	"main/pkg/models"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func CreateLicense(c *gin.Context) {
+	var request models.CreateLicenseRequest
+	if err := c.BindJSON(&request); err != nil {
+		c.JSON(400, models.GenericResponse[any]{
+			Object:  nil,
+			Error:   "Invalid request body",
+			Success: false,
+		})
+		return
+	}
+
+	license, err := service.CreateLicense(request)
+	if err != nil {
+		c.JSON(400, models.GenericResponse[any]{
+			Object:  nil,
+			Error:   err.Error(),
+			Success: false,
+		})
+		return
+	}
+
+	c.JSON(201, models.GenericResponse[*models.License]{
+		Object:  license,
+		Error:   "",
+		Success: true,
+	})
+}
+
+func ListLicenses(c *gin.Context) {
+	var filter models.LicenseFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		c.JSON(400, models.GenericResponse[any]{
+			Object:  nil,
+			Error:   "Invalid query parameters",
+			Success: false,
+		})
+		return
+	}
+
+	result, err := service.ListLicenses(filter)
+	if err != nil {
+		c.JSON(400, models.GenericResponse[any]{
+			Object:  nil,
+			Error:   err.Error(),
+			Success: false,
+		})
+		return
+	}
+
+	c.JSON(200, models.GenericResponse[*models.LicenseList]{
+		Object:  result,
+		Error:   "",
+		Success: true,
+	})
+}
+
+func GetLicense(c *gin.Context) {
+	id, ok := licenseID(c)
+	if !ok {
+		return
+	}
+
+	license, err := service.GetLicense(id)
+	if err != nil {
+		licenseNotFound(c)
+		return
+	}
+
+	c.JSON(200, models.GenericResponse[*models.License]{
+		Object:  license,
+		Error:   "",
+		Success: true,
+	})
+}
+
+func UpdateLicense(c *gin.Context) {
+	id, ok := licenseID(c)
+	if !ok {
+		return
+	}
+
+	var request models.UpdateLicenseRequest
+	if err := c.BindJSON(&request); err != nil {
+		c.JSON(400, models.GenericResponse[any]{
+			Object:  nil,
+			Error:   "Invalid request body",
+			Success: false,
+		})
+		return
+	}
+
+	license, err := service.UpdateLicense(id, request)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		licenseNotFound(c)
+		return
+	}
+	if errors.Is(err, service.ErrLicenseRevoked) {
+		c.JSON(409, models.GenericResponse[any]{
+			Object:  nil,
+			Error:   "A revoked license cannot be reactivated",
+			Success: false,
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(400, models.GenericResponse[any]{
+			Object:  nil,
+			Error:   err.Error(),
+			Success: false,
+		})
+		return
+	}
+
+	c.JSON(200, models.GenericResponse[*models.License]{
+		Object:  license,
+		Error:   "",
+		Success: true,
+	})
+}
+
+func RevokeLicense(c *gin.Context) {
+	id, ok := licenseID(c)
+	if !ok {
+		return
+	}
+
+	license, err := service.RevokeLicense(id)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		licenseNotFound(c)
+		return
+	}
+	if err != nil {
+		c.JSON(500, models.GenericResponse[any]{
+			Object:  nil,
+			Error:   "Failed to revoke license",
+			Success: false,
+		})
+		return
+	}
+
+	c.JSON(200, models.GenericResponse[*models.License]{
+		Object:  license,
+		Error:   "",
+		Success: true,
+	})
+}
+
+func licenseID(c *gin.Context) (primitive.ObjectID, bool) {
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		licenseNotFound(c)
+		return primitive.NilObjectID, false
+	}
+
+	return id, true
+}
+
+func licenseNotFound(c *gin.Context) {
+	c.JSON(404, models.GenericResponse[any]{
+		Object:  nil,
+		Error:   "License not found",
+		Success: false,
+	})
+}
+
+func RotateLicenseKey(c *gin.Context) {
+	id, ok := licenseID(c)
+	if !ok {
+		return
+	}
+	request, ok := bindRotateLicenseKeyRequest(c)
+	if !ok {
+		return
+	}
+
+	rotation, err := service.RotateLicenseKey(id, request, models.KeyRotatedByAdmin)
+	respondKeyRotation(c, rotation, err)
+}
+
+func ListKeyRotations(c *gin.Context) {
+	id, ok := licenseID(c)
+	if !ok {
+		return
+	}
+
+	result, err := service.ListKeyRotations(id)
+	if err != nil {
+		c.JSON(500, models.GenericResponse[any]{
+			Object:  nil,
+			Error:   "Failed to list key rotations",
+			Success: false,
+		})
+		return
+	}
+
+	c.JSON(200, models.GenericResponse[[]models.KeyRotation]{
+		Object:  result,
+		Error:   "",
+		Success: true,
+	})
+}
+
+// bindRotateLicenseKeyRequest reads the optional rotation body, an empty body uses the
+// default grace period.
+func bindRotateLicenseKeyRequest(c *gin.Context) (models.RotateLicenseKeyRequest, bool) {
+	var request models.RotateLicenseKeyRequest
+	if err := c.ShouldBindJSON(&request); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(400, models.GenericResponse[any]{
+			Object:  nil,
+			Error:   "Invalid request body",
+			Success: false,
+		})
+		return request, false
+	}
+
+	return request, true
+}
+
+func respondKeyRotation(c *gin.Context, rotation *models.KeyRotation, err error) {
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		licenseNotFound(c)
+		return
+	}
+	if errors.Is(err, service.ErrLicenseRevoked) {
+		c.JSON(409, models.GenericResponse[any]{
+			Object:  nil,
+			Error:   "A revoked license cannot rotate its key",
+			Success: false,
+		})
+		return
+	}
+	if errors.Is(err, service.ErrLicenseUnclaimed) {
+		c.JSON(409, models.GenericResponse[any]{
+			Object:  nil,
+			Error:   "The license key has not been claimed by its buyer yet",
+			Success: false,
+		})
+		return
+	}
+	if errors.Is(err, service.ErrRetiredKey) {
+		c.JSON(403, models.GenericResponse[any]{
+			Object:  nil,
+			Error:   err.Error(),
+			Success: false,
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(400, models.GenericResponse[any]{
+			Object:  nil,
+			Error:   err.Error(),
+			Success: false,
+		})
+		return
+	}
+
+	c.JSON(201, models.GenericResponse[*models.KeyRotation]{
+		Object:  rotation,
+		Error:   "",
+		Success: true,
+	})
+}
+
+func ListPlans(c *gin.Context) {
+	result, err := service.ListPlans()
+	if err != nil {
+		c.JSON(500, models.GenericResponse[any]{
+			Object:  nil,
+			Error:   "Failed to list plans",
+			Success: false,
+		})
+		return
+	}
+
+	c.JSON(200, models.GenericResponse[[]models.Plan]{
+		Object:  result,
+		Error:   "",
+		Success: true,
+	})
+}
+
+func SavePlan(c *gin.Context) {
+	var request models.Plan
+	if err := c.BindJSON(&request); err != nil {
+		c.JSON(400, models.GenericResponse[any]{
+			Object:  nil,
+			Error:   "Invalid request body",
+			Success: false,
+		})
+		return
+	}
+
+	plan, err := service.SavePlan(c.Param("id"), request)
+	if err != nil {
+		c.JSON(400, models.GenericResponse[any]{
+			Object:  nil,
+			Error:   err.Error(),
+			Success: false,
+		})
+		return
+	}
+
+	c.JSON(200, models.GenericResponse[*models.Plan]{
+		Object:  plan,
+		Error:   "",
+		Success: true,
+	})
+}
+
+func DeletePlan(c *gin.Context) {
+	err := service.DeletePlan(c.Param("id"))
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		c.JSON(404, models.GenericResponse[any]{
+			Object:  nil,
+			Error:   "Plan not found",
+			Success: false,
+		})
+		return
+	}
+	if errors.Is(err, service.ErrDefaultPlan) {
+		c.JSON(409, models.GenericResponse[any]{
+			Object:  nil,
+			Error:   err.Error(),
+			Success: false,
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(500, models.GenericResponse[any]{
+			Object:  nil,
+			Error:   "Failed to delete plan",
+			Success: false,
+		})
+		return
+	}
+
+	c.JSON(200, models.GenericResponse[any]{
+		Object:  nil,
+		Error:   "",
+		Success: true,
+	})
+}
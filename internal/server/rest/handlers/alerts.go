@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"errors"
+	"main/internal/server/rest/middleware"
+	"main/internal/server/service"
+	"main/pkg/models"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func CreateAlertRule(c *gin.Context) {
+	var request models.CreateAlertRuleRequest
+	if err := c.BindJSON(&request); err != nil {
+		c.JSON(400, models.GenericResponse[any]{
+			Object:  nil,
+			Error:   "Invalid request body",
+			Success: false,
+		})
+		return
+	}
+
+	rule, err := service.CreateAlertRule(middleware.GetLicense(c).ID, middleware.GetPlan(c), request)
+	if errors.Is(err, service.ErrWatchedWalletsBusy) {
+		c.JSON(409, models.GenericResponse[any]{
+			Object:  nil,
+			Error:   err.Error(),
+			Success: false,
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(400, models.GenericResponse[any]{
+			Object:  nil,
+			Error:   err.Error(),
+			Success: false,
+		})
+		return
+	}
+
+	c.JSON(201, models.GenericResponse[*models.AlertRule]{
+		Object:  rule,
+		Error:   "",
+		Success: true,
+	})
+}
+
+func ListAlertRules(c *gin.Context) {
+	result, err := service.ListAlertRules(middleware.GetLicense(c).ID)
+	if err != nil {
+		c.JSON(500, models.GenericResponse[any]{
+			Object:  nil,
+			Error:   "Failed to list alert rules",
+			Success: false,
+		})
+		return
+	}
+
+	c.JSON(200, models.GenericResponse[[]models.AlertRule]{
+		Object:  result,
+		Error:   "",
+		Success: true,
+	})
+}
+
+func DeleteAlertRule(c *gin.Context) {
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		alertRuleNotFound(c)
+		return
+	}
+
+	err = service.DeleteAlertRule(middleware.GetLicense(c).ID, id)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		alertRuleNotFound(c)
+		return
+	}
+	if err != nil {
+		c.JSON(500, models.GenericResponse[any]{
+			Object:  nil,
+			Error:   "Failed to delete alert rule",
+			Success: false,
+		})
+		return
+	}
+
+	c.JSON(200, models.GenericResponse[any]{
+		Object:  nil,
+		Error:   "",
+		Success: true,
+	})
+}
+
+func ListAlertEvents(c *gin.Context) {
+	result, err := service.ListAlertEvents(middleware.GetLicense(c).ID)
+	if err != nil {
+		c.JSON(500, models.GenericResponse[any]{
+			Object:  nil,
+			Error:   "Failed to list alerts",
+			Success: false,
+		})
+		return
+	}
+
+	c.JSON(200, models.GenericResponse[[]models.AlertEvent]{
+		Object:  result,
+		Error:   "",
+		Success: true,
+	})
+}
+
+func alertRuleNotFound(c *gin.Context) {
+	c.JSON(404, models.GenericResponse[any]{
+		Object:  nil,
+		Error:   "Alert rule not found",
+		Success: false,
+	})
+}
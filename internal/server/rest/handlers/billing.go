@@ -0,0 +1,146 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"errors"
+	"main/internal/server/service"
+	"main/pkg/models"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func GetUsageStatement(c *gin.Context) {
+	id, ok := licenseID(c)
+	if !ok {
+		return
+	}
+	format, ok := statementFormat(c)
+	if !ok {
+		return
+	}
+
+	statement, err := service.GetUsageStatement(id, c.Query("month"))
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		licenseNotFound(c)
+		return
+	}
+	if err != nil {
+		statementError(c, err)
+		return
+	}
+
+	if format == "json" {
+		c.JSON(200, models.GenericResponse[*models.UsageStatement]{
+			Object:  statement,
+			Error:   "",
+			Success: true,
+		})
+		return
+	}
+
+	filename := statement.LicenseID.Hex() + "-" + statement.Month + ".csv"
+	c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+	c.Header("Content-Type", "text/csv")
+	writer := csv.NewWriter(c.Writer)
+	_ = writer.Write([]string{"month", "endpoint", "requests", "errors", "credits", "currency", "unit_price", "amount"})
+	for _, endpoint := range statement.Endpoints {
+		_ = writer.Write([]string{
+			statement.Month,
+			endpoint.Endpoint,
+			strconv.FormatInt(endpoint.Requests, 10),
+			strconv.FormatInt(endpoint.Errors, 10),
+			strconv.FormatInt(endpoint.Credits, 10),
+			"",
+			"",
+			"",
+		})
+	}
+	// the last row carries the totals and the price of the month
+	_ = writer.Write([]string{
+		statement.Month,
+		"total",
+		strconv.FormatInt(statement.Requests, 10),
+		strconv.FormatInt(statement.Errors, 10),
+		strconv.FormatInt(statement.Credits, 10),
+		statement.Currency,
+		statement.UnitPrice,
+		statement.Amount,
+	})
+	writer.Flush()
+}
+
+func ListUsageStatements(c *gin.Context) {
+	format, ok := statementFormat(c)
+	if !ok {
+		return
+	}
+
+	statements, err := service.ListUsageStatements(c.Query("month"))
+	if err != nil {
+		statementError(c, err)
+		return
+	}
+
+	if format == "json" {
+		c.JSON(200, models.GenericResponse[[]models.UsageStatement]{
+			Object:  statements,
+			Error:   "",
+			Success: true,
+		})
+		return
+	}
+
+	filename := "statements.csv"
+	if len(statements) > 0 {
+		filename = "statements-" + statements[0].Month + ".csv"
+	}
+	c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+	c.Header("Content-Type", "text/csv")
+	writer := csv.NewWriter(c.Writer)
+	_ = writer.Write([]string{"license_id", "license_name", "plan", "month", "requests", "errors", "credits", "currency", "amount"})
+	for _, statement := range statements {
+		_ = writer.Write([]string{
+			statement.LicenseID.Hex(),
+			statement.LicenseName,
+			statement.Plan,
+			statement.Month,
+			strconv.FormatInt(statement.Requests, 10),
+			strconv.FormatInt(statement.Errors, 10),
+			strconv.FormatInt(statement.Credits, 10),
+			statement.Currency,
+			statement.Amount,
+		})
+	}
+	writer.Flush()
+}
+
+func statementFormat(c *gin.Context) (string, bool) {
+	format := c.DefaultQuery("format", "json")
+	if format != "json" && format != "csv" {
+		c.JSON(400, models.GenericResponse[any]{
+			Object:  nil,
+			Error:   "format must be json or csv",
+			Success: false,
+		})
+		return "", false
+	}
+	return format, true
+}
+
+func statementError(c *gin.Context, err error) {
+	if errors.Is(err, service.ErrInvalidMonth) {
+		c.JSON(400, models.GenericResponse[any]{
+			Object:  nil,
+			Error:   err.Error(),
+			Success: false,
+		})
+		return
+	}
+	c.JSON(500, models.GenericResponse[any]{
+		Object:  nil,
+		Error:   "Failed to build usage statement",
+		Success: false,
+	})
+}
@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"main/internal/server/service"
+	"main/pkg/models"
+	"main/pkg/solana"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetPriorityFees accepts either a base64 encoded transaction, whose writable accounts
+// scope the estimate, or a comma separated list of accounts.
+func GetPriorityFees(c *gin.Context) {
+	var accounts []string
+	if tx := c.Query("transaction"); tx != "" {
+		writable, err := solana.WritableAccounts(tx)
+		if err != nil {
+			c.JSON(400, models.GenericResponse[any]{
+				Object:  nil,
+				Error:   "Invalid transaction",
+				Success: false,
+			})
+			return
+		}
+		accounts = writable
+	} else if list := c.Query("accounts"); list != "" {
+		accounts = strings.Split(list, ",")
+	}
+	if err := service.ValidatePriorityFeeAccounts(accounts); err != nil {
+		c.JSON(400, models.GenericResponse[any]{
+			Object:  nil,
+			Error:   err.Error(),
+			Success: false,
+		})
+		return
+	}
+
+	estimate, err := service.EstimatePriorityFees(accounts)
+	if err != nil {
+		c.JSON(502, models.GenericResponse[any]{
+			Object:  nil,
+			Error:   "Failed to fetch prioritization fees",
+			Success: false,
+		})
+		return
+	}
+
+	c.JSON(200, models.GenericResponse[*models.PriorityFeeEstimate]{
+		Object:  estimate,
+		Error:   "",
+		Success: true,
+	})
+}
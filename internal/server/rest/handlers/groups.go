@@ -0,0 +1,194 @@
+package handlers
+
+import (
+	"errors"
+	"main/internal/server/rest/middleware"
+	"main/internal/server/service"
+	"main/pkg/models"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func CreateWalletGroup(c *gin.Context) {
+	var request models.WalletGroupRequest
+	if err := c.BindJSON(&request); err != nil {
+		c.JSON(400, models.GenericResponse[any]{
+			Object:  nil,
+			Error:   "Invalid request body",
+			Success: false,
+		})
+		return
+	}
+
+	group, err := service.CreateWalletGroup(middleware.GetLicense(c).ID, request)
+	if err != nil {
+		c.JSON(400, models.GenericResponse[any]{
+			Object:  nil,
+			Error:   err.Error(),
+			Success: false,
+		})
+		return
+	}
+
+	c.JSON(201, models.GenericResponse[*models.WalletGroup]{
+		Object:  group,
+		Error:   "",
+		Success: true,
+	})
+}
+
+func ListWalletGroups(c *gin.Context) {
+	result, err := service.ListWalletGroups(middleware.GetLicense(c).ID)
+	if err != nil {
+		c.JSON(500, models.GenericResponse[any]{
+			Object:  nil,
+			Error:   "Failed to list wallet groups",
+			Success: false,
+		})
+		return
+	}
+
+	c.JSON(200, models.GenericResponse[[]models.WalletGroup]{
+		Object:  result,
+		Error:   "",
+		Success: true,
+	})
+}
+
+func GetWalletGroup(c *gin.Context) {
+	group, ok := loadWalletGroup(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(200, models.GenericResponse[*models.WalletGroup]{
+		Object:  group,
+		Error:   "",
+		Success: true,
+	})
+}
+
+func UpdateWalletGroup(c *gin.Context) {
+	id, ok := walletGroupID(c)
+	if !ok {
+		return
+	}
+
+	var request models.WalletGroupRequest
+	if err := c.BindJSON(&request); err != nil {
+		c.JSON(400, models.GenericResponse[any]{
+			Object:  nil,
+			Error:   "Invalid request body",
+			Success: false,
+		})
+		return
+	}
+
+	group, err := service.UpdateWalletGroup(middleware.GetLicense(c).ID, id, request)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		walletGroupNotFound(c)
+		return
+	}
+	if err != nil {
+		c.JSON(400, models.GenericResponse[any]{
+			Object:  nil,
+			Error:   err.Error(),
+			Success: false,
+		})
+		return
+	}
+
+	c.JSON(200, models.GenericResponse[*models.WalletGroup]{
+		Object:  group,
+		Error:   "",
+		Success: true,
+	})
+}
+
+func DeleteWalletGroup(c *gin.Context) {
+	id, ok := walletGroupID(c)
+	if !ok {
+		return
+	}
+
+	err := service.DeleteWalletGroup(middleware.GetLicense(c).ID, id)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		walletGroupNotFound(c)
+		return
+	}
+	if err != nil {
+		c.JSON(500, models.GenericResponse[any]{
+			Object:  nil,
+			Error:   "Failed to delete wallet group",
+			Success: false,
+		})
+		return
+	}
+
+	c.JSON(200, models.GenericResponse[any]{
+		Object:  nil,
+		Error:   "",
+		Success: true,
+	})
+}
+
+func GetWalletGroupBalance(c *gin.Context) {
+	group, ok := loadWalletGroup(c)
+	if !ok {
+		return
+	}
+	if !checkWalletsPerRequest(c, len(group.Members)) || !middleware.ChargeCredits(c, models.OperationWallet, len(group.Members)) {
+		return
+	}
+
+	balance, err := service.GetWalletGroupBalance(group)
+	if err != nil {
+		c.JSON(502, models.GenericResponse[any]{
+			Object:  nil,
+			Error:   "Failed to fetch group balances: " + err.Error(),
+			Success: false,
+		})
+		return
+	}
+
+	c.JSON(200, models.GenericResponse[*models.WalletGroupBalance]{
+		Object:  balance,
+		Error:   "",
+		Success: true,
+	})
+}
+
+func loadWalletGroup(c *gin.Context) (*models.WalletGroup, bool) {
+	id, ok := walletGroupID(c)
+	if !ok {
+		return nil, false
+	}
+
+	group, err := service.GetWalletGroup(middleware.GetLicense(c).ID, id)
+	if err != nil {
+		walletGroupNotFound(c)
+		return nil, false
+	}
+
+	return group, true
+}
+
+func walletGroupID(c *gin.Context) (primitive.ObjectID, bool) {
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		walletGroupNotFound(c)
+		return primitive.NilObjectID, false
+	}
+
+	return id, true
+}
+
+func walletGroupNotFound(c *gin.Context) {
+	c.JSON(404, models.GenericResponse[any]{
+		Object:  nil,
+		Error:   "Wallet group not found",
+		Success: false,
+	})
+}
@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"errors"
+	"main/internal/server/rest/middleware"
+	"main/internal/server/service"
+	"main/pkg/models"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func PurchaseLicense(c *gin.Context) {
+	var request models.PurchaseLicenseRequest
+	if err := c.BindJSON(&request); err != nil {
+		c.JSON(400, models.GenericResponse[any]{
+			Object:  nil,
+			Error:   "Invalid request body",
+			Success: false,
+		})
+		return
+	}
+
+	purchase, err := service.PurchaseLicense(request)
+	if err != nil {
+		c.JSON(400, models.GenericResponse[any]{
+			Object:  nil,
+			Error:   err.Error(),
+			Success: false,
+		})
+		return
+	}
+
+	c.JSON(201, models.GenericResponse[*models.LicensePurchase]{
+		Object:  purchase,
+		Error:   "",
+		Success: true,
+	})
+}
+
+func TopUpLicense(c *gin.Context) {
+	var request models.TopUpLicenseRequest
+	if err := c.BindJSON(&request); err != nil || request.LicenseKey == "" {
+		c.JSON(400, models.GenericResponse[any]{
+			Object:  nil,
+			Error:   "Invalid request body",
+			Success: false,
+		})
+		return
+	}
+
+	purchase, err := service.TopUpLicense(request)
+	if err != nil {
+		c.JSON(400, models.GenericResponse[any]{
+			Object:  nil,
+			Error:   err.Error(),
+			Success: false,
+		})
+		return
+	}
+
+	c.JSON(201, models.GenericResponse[*models.LicensePurchase]{
+		Object:  purchase,
+		Error:   "",
+		Success: true,
+	})
+}
+
+func GetLicensePurchase(c *gin.Context) {
+	purchase, err := service.GetLicensePurchase(c.Param("reference"), c.Query("claim_token"))
+	if errors.Is(err, models.ErrLicenseKeyIssued) {
+		c.JSON(409, models.GenericResponse[any]{
+			Object:  nil,
+			Error:   "The license key of this purchase was already claimed",
+			Success: false,
+		})
+		return
+	}
+	if err != nil {
+		status := 500
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			status = 404
+		}
+		c.JSON(status, models.GenericResponse[any]{
+			Object:  nil,
+			Error:   "Purchase not found",
+			Success: false,
+		})
+		return
+	}
+
+	c.JSON(200, models.GenericResponse[*models.LicensePurchase]{
+		Object:  purchase,
+		Error:   "",
+		Success: true,
+	})
+}
+
+func RotateOwnLicenseKey(c *gin.Context) {
+	request, ok := bindRotateLicenseKeyRequest(c)
+	if !ok {
+		return
+	}
+
+	rotation, err := service.RotateOwnLicenseKey(middleware.GetLicense(c), c.GetHeader("x-api-key"), request)
+	respondKeyRotation(c, rotation, err)
+}
+
+func ListOwnKeyRotations(c *gin.Context) {
+	result, err := service.ListKeyRotations(middleware.GetLicense(c).ID)
+	if err != nil {
+		c.JSON(500, models.GenericResponse[any]{
+			Object:  nil,
+			Error:   "Failed to list key rotations",
+			Success: false,
+		})
+		return
+	}
+
+	c.JSON(200, models.GenericResponse[[]models.KeyRotation]{
+		Object:  result,
+		Error:   "",
+		Success: true,
+	})
+}
@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"errors"
+	"main/internal/server/rest/middleware"
+	"main/internal/server/service"
+	"main/pkg/models"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func CreatePaymentRequest(c *gin.Context) {
+	var request models.CreatePaymentRequest
+	err := c.BindJSON(&request)
+	if err != nil || request.Recipient == "" || request.Amount == "" {
+		c.JSON(400, models.GenericResponse[any]{
+			Object:  nil,
+			Error:   "Invalid request body",
+			Success: false,
+		})
+		return
+	}
+
+	payment, err := service.CreatePaymentRequest(middleware.GetLicense(c).ID, request)
+	if err != nil {
+		c.JSON(400, models.GenericResponse[any]{
+			Object:  nil,
+			Error:   err.Error(),
+			Success: false,
+		})
+		return
+	}
+
+	c.JSON(201, models.GenericResponse[*models.PaymentRequest]{
+		Object:  payment,
+		Error:   "",
+		Success: true,
+	})
+}
+
+func GetPaymentRequest(c *gin.Context) {
+	payment, err := service.GetPaymentRequest(middleware.GetLicense(c).ID, c.Param("reference"))
+	if err != nil {
+		status := 500
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			status = 404
+		}
+		c.JSON(status, models.GenericResponse[any]{
+			Object:  nil,
+			Error:   "Payment request not found",
+			Success: false,
+		})
+		return
+	}
+
+	c.JSON(200, models.GenericResponse[*models.PaymentRequest]{
+		Object:  payment,
+		Error:   "",
+		Success: true,
+	})
+}
@@ -0,0 +1,177 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"log"
+	"main/internal/server/rest/middleware"
+	"main/internal/server/service"
+	"main/pkg/models"
+	"main/pkg/solana"
+	"strconv"
+
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func CreateSnapshot(c *gin.Context) {
+	var request models.CreateSnapshotRequest
+	if err := c.BindJSON(&request); err != nil {
+		c.JSON(400, models.GenericResponse[any]{
+			Object:  nil,
+			Error:   "Invalid request body",
+			Success: false,
+		})
+		return
+	}
+
+	snapshot, err := service.CreateSnapshot(middleware.GetLicense(c).ID, request)
+	if errors.Is(err, solana.ErrNotAMint) || errors.Is(err, rpc.ErrNotFound) {
+		c.JSON(404, models.GenericResponse[any]{
+			Object:  nil,
+			Error:   "Token mint not found",
+			Success: false,
+		})
+		return
+	}
+	if errors.Is(err, service.ErrSnapshotPending) {
+		c.JSON(409, models.GenericResponse[any]{
+			Object:  nil,
+			Error:   "A snapshot of this license is already pending, try again once it runs",
+			Success: false,
+		})
+		return
+	}
+	if errors.Is(err, service.ErrInvalidSnapshot) {
+		c.JSON(400, models.GenericResponse[any]{
+			Object:  nil,
+			Error:   err.Error(),
+			Success: false,
+		})
+		return
+	}
+	if errors.Is(err, service.ErrTokenUnavailable) {
+		c.JSON(502, models.GenericResponse[any]{
+			Object:  nil,
+			Error:   "Failed to read the token mint",
+			Success: false,
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(500, models.GenericResponse[any]{
+			Object:  nil,
+			Error:   "Failed to create snapshot",
+			Success: false,
+		})
+		return
+	}
+
+	c.JSON(202, models.GenericResponse[*models.Snapshot]{
+		Object:  snapshot,
+		Error:   "",
+		Success: true,
+	})
+}
+
+func ListSnapshots(c *gin.Context) {
+	result, err := service.ListSnapshots(middleware.GetLicense(c).ID)
+	if err != nil {
+		c.JSON(500, models.GenericResponse[any]{
+			Object:  nil,
+			Error:   "Failed to list snapshots",
+			Success: false,
+		})
+		return
+	}
+
+	c.JSON(200, models.GenericResponse[[]models.Snapshot]{
+		Object:  result,
+		Error:   "",
+		Success: true,
+	})
+}
+
+func GetSnapshot(c *gin.Context) {
+	snapshot, ok := loadSnapshot(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(200, models.GenericResponse[*models.Snapshot]{
+		Object:  snapshot,
+		Error:   "",
+		Success: true,
+	})
+}
+
+// ExportSnapshot streams the holders of a completed snapshot as CSV (default) or NDJSON.
+func ExportSnapshot(c *gin.Context) {
+	snapshot, ok := loadSnapshot(c)
+	if !ok {
+		return
+	}
+
+	format := c.DefaultQuery("format", "csv")
+	if format != "csv" && format != "ndjson" {
+		c.JSON(400, models.GenericResponse[any]{
+			Object:  nil,
+			Error:   "format must be csv or ndjson",
+			Success: false,
+		})
+		return
+	}
+	if snapshot.Status != models.SnapshotStatusCompleted {
+		c.JSON(409, models.GenericResponse[any]{
+			Object:  nil,
+			Error:   "Snapshot is " + string(snapshot.Status),
+			Success: false,
+		})
+		return
+	}
+
+	filename := snapshot.Mint + "-" + strconv.FormatUint(snapshot.Slot, 10) + "." + format
+	c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+	c.Header("X-Snapshot-Slot", strconv.FormatUint(snapshot.Slot, 10))
+	c.Header("X-Snapshot-Digest", snapshot.Digest)
+
+	var err error
+	if format == "csv" {
+		c.Header("Content-Type", "text/csv")
+		writer := csv.NewWriter(c.Writer)
+		_ = writer.Write([]string{"owner", "amount", "ui_amount", "accounts"})
+		err = service.ExportSnapshot(snapshot, func(holder models.SnapshotHolder) error {
+			return writer.Write([]string{holder.Owner, holder.Amount, holder.UiAmount, strconv.Itoa(holder.Accounts)})
+		})
+		writer.Flush()
+	} else {
+		c.Header("Content-Type", "application/x-ndjson")
+		encoder := json.NewEncoder(c.Writer)
+		err = service.ExportSnapshot(snapshot, func(holder models.SnapshotHolder) error {
+			return encoder.Encode(holder)
+		})
+	}
+	// the status is already sent, a failed export shows up as a truncated file
+	if err != nil {
+		log.Println("Error exporting snapshot " + snapshot.ID.Hex() + ": " + err.Error())
+	}
+}
+
+func loadSnapshot(c *gin.Context) (*models.Snapshot, bool) {
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err == nil {
+		var snapshot *models.Snapshot
+		if snapshot, err = service.GetSnapshot(middleware.GetLicense(c).ID, id); err == nil {
+			return snapshot, true
+		}
+	}
+
+	c.JSON(404, models.GenericResponse[any]{
+		Object:  nil,
+		Error:   "Snapshot not found",
+		Success: false,
+	})
+	return nil, false
+}
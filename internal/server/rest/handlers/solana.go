@@ -1,6 +1,8 @@
 package handlers
 
 import (
+	"fmt"
+	"main/internal/server/rest/middleware"
 	"main/pkg/models"
 	"main/pkg/queue"
 	"sync"
@@ -19,12 +21,49 @@ func GetSolanaBalance(c *gin.Context) {
 		})
 		return
 	}
+	if !checkWalletsPerRequest(c, len(request.Wallets)) {
+		return
+	}
+	if !middleware.ChargeCredits(c, models.OperationWallet, len(request.Wallets)) {
+		return
+	}
+
+	c.JSON(200, models.GenericResponse[[]models.WalletBalance]{
+		Object:  fetchWalletBalances(request.Wallets),
+		Error:   "",
+		Success: true,
+	})
+}
+
+// checkWalletsPerRequest rejects requests that read more wallets than the plan allows.
+func checkWalletsPerRequest(c *gin.Context, wallets int) bool {
+	if err := walletsPerRequestError(c, wallets); err != "" {
+		c.JSON(400, models.GenericResponse[any]{
+			Object:  nil,
+			Error:   err,
+			Success: false,
+		})
+		return false
+	}
+	return true
+}
+
+func walletsPerRequestError(c *gin.Context, wallets int) string {
+	plan := middleware.GetPlan(c)
+	if plan == nil || plan.MaxWalletsPerRequest <= 0 || wallets <= plan.MaxWalletsPerRequest {
+		return ""
+	}
+	return fmt.Sprintf("The %s plan allows at most %d wallets per request", plan.Name, plan.MaxWalletsPerRequest)
+}
 
-	var result []models.WalletBalance
+// fetchWalletBalances resolves every wallet through the queue concurrently. Results
+// keep the order of the input.
+func fetchWalletBalances(wallets []string) []models.WalletBalance {
+	result := make([]models.WalletBalance, len(wallets))
 	wg := sync.WaitGroup{}
-	wg.Add(len(request.Wallets))
-	for _, wallet := range request.Wallets {
-		go func(wallet string) {
+	wg.Add(len(wallets))
+	for i, wallet := range wallets {
+		go func(i int, wallet string) {
 			defer wg.Done()
 			waitChan := queue.AddWalletToQueue(wallet)
 			res := <-waitChan
@@ -41,14 +80,10 @@ func GetSolanaBalance(c *gin.Context) {
 			} else {
 				bal.Cache = "miss"
 			}
-			result = append(result, bal)
-		}(wallet)
+			result[i] = bal
+		}(i, wallet)
 	}
 	wg.Wait()
 
-	c.JSON(200, models.GenericResponse[[]models.WalletBalance]{
-		Object:  result,
-		Error:   "",
-		Success: true,
-	})
+	return result
 }
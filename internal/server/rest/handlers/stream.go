@@ -0,0 +1,154 @@
+package handlers
+
+import (
+	"log"
+	"main/internal/server/rest/middleware"
+	"main/pkg/models"
+	"main/pkg/solana"
+	"main/pkg/stream"
+	"net/http"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	maxStreamWallets = 500
+	// maxStreamMessageSize bounds client messages, a subscribe of maxStreamWallets fits easily.
+	maxStreamMessageSize = 64 << 10
+)
+
+var upgrader = websocket.Upgrader{
+	// API keys, not cookies, authenticate the stream, so any origin may connect
+	CheckOrigin: func(r *http.Request) bool { return true },
+}
+
+func StreamBalances(c *gin.Context) {
+	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		log.Println("Error upgrading websocket: " + err.Error())
+		return
+	}
+	defer conn.Close()
+	conn.SetReadLimit(maxStreamMessageSize)
+
+	updates := make(chan stream.BalanceUpdate, 256)
+	replies := make(chan models.StreamMessage, 16)
+	done := make(chan struct{})
+	writerDone := make(chan struct{})
+	// subscribed is shared with the writer, which drops wallets the upstream rejected
+	var subscribedMutex sync.Mutex
+	subscribed := make(map[string]bool)
+	defer func() {
+		close(done)
+		subscribedMutex.Lock()
+		defer subscribedMutex.Unlock()
+		for wallet := range subscribed {
+			stream.Unsubscribe(wallet, updates)
+		}
+	}()
+
+	// gorilla connections allow a single writer, so all outgoing messages go through here
+	go func() {
+		defer close(writerDone)
+		for {
+			var msg models.StreamMessage
+			select {
+			case <-done:
+				return
+			case msg = <-replies:
+			case update := <-updates:
+				if update.Error != "" {
+					subscribedMutex.Lock()
+					delete(subscribed, update.Wallet)
+					subscribedMutex.Unlock()
+					msg = models.StreamMessage{Type: "error", Wallet: update.Wallet, Error: update.Error}
+					break
+				}
+				msg = models.StreamMessage{
+					Type:     "balance",
+					Wallet:   update.Wallet,
+					Lamports: update.Lamports,
+					Balance:  solana.LamportsToSol(update.Lamports),
+					Slot:     update.Slot,
+				}
+			}
+			if err := conn.WriteJSON(msg); err != nil {
+				return
+			}
+		}
+	}()
+
+	reply := func(msg models.StreamMessage) {
+		select {
+		case replies <- msg:
+		case <-writerDone:
+		}
+	}
+
+	for {
+		var request models.StreamRequest
+		if err := conn.ReadJSON(&request); err != nil {
+			return
+		}
+
+		switch request.Action {
+		case "subscribe":
+			subscribedMutex.Lock()
+			count := len(subscribed)
+			// every wallet newly subscribed costs a wallet lookup, charged up front
+			fresh := make(map[string]bool)
+			for _, wallet := range request.Wallets {
+				if !subscribed[wallet] {
+					fresh[wallet] = true
+				}
+			}
+			subscribedMutex.Unlock()
+
+			if count+len(request.Wallets) > maxStreamWallets {
+				reply(models.StreamMessage{Type: "error", Error: "Too many wallets on this connection"})
+				continue
+			}
+			if err := walletsPerRequestError(c, len(request.Wallets)); err != "" {
+				reply(models.StreamMessage{Type: "error", Error: err})
+				continue
+			}
+			if err := middleware.ChargeStreamCredits(c, models.OperationWallet, len(fresh)); err != nil {
+				reply(models.StreamMessage{Type: "error", Error: err.Error()})
+				continue
+			}
+			var added []string
+			var failed []models.StreamMessage
+			subscribedMutex.Lock()
+			for _, wallet := range request.Wallets {
+				if subscribed[wallet] {
+					continue
+				}
+				if err := stream.Subscribe(wallet, updates); err != nil {
+					failed = append(failed, models.StreamMessage{Type: "error", Wallet: wallet, Error: err.Error()})
+					continue
+				}
+				subscribed[wallet] = true
+				added = append(added, wallet)
+			}
+			subscribedMutex.Unlock()
+			for _, msg := range failed {
+				reply(msg)
+			}
+			reply(models.StreamMessage{Type: "subscribed", Wallets: added})
+		case "unsubscribe":
+			subscribedMutex.Lock()
+			for _, wallet := range request.Wallets {
+				if subscribed[wallet] {
+					stream.Unsubscribe(wallet, updates)
+					delete(subscribed, wallet)
+				}
+			}
+			subscribedMutex.Unlock()
+			reply(models.StreamMessage{Type: "unsubscribed", Wallets: request.Wallets})
+		default:
+			reply(models.StreamMessage{Type: "error", Error: "Unknown action"})
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"errors"
+	"main/internal/server/service"
+	"main/pkg/models"
+	"main/pkg/solana"
+
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/gin-gonic/gin"
+)
+
+func GetTokenInfo(c *gin.Context) {
+	mint := c.Param("mint")
+	if !solana.IsValidAddress(mint) {
+		c.JSON(400, models.GenericResponse[any]{
+			Object:  nil,
+			Error:   "Invalid mint address",
+			Success: false,
+		})
+		return
+	}
+
+	token, err := service.GetTokenInfo(mint)
+	if errors.Is(err, solana.ErrNotAMint) || errors.Is(err, rpc.ErrNotFound) {
+		c.JSON(404, models.GenericResponse[any]{
+			Object:  nil,
+			Error:   "Token mint not found",
+			Success: false,
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(502, models.GenericResponse[any]{
+			Object:  nil,
+			Error:   "Failed to fetch token: " + err.Error(),
+			Success: false,
+		})
+		return
+	}
+
+	c.JSON(200, models.GenericResponse[*models.TokenInfo]{
+		Object:  token,
+		Error:   "",
+		Success: true,
+	})
+}
@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"errors"
+	"main/internal/server/rest/middleware"
+	"main/internal/server/service"
+	"main/pkg/models"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func SendTransaction(c *gin.Context) {
+	var request models.SendTransactionRequest
+	err := c.BindJSON(&request)
+	if err != nil || request.Transaction == "" {
+		c.JSON(400, models.GenericResponse[any]{
+			Object:  nil,
+			Error:   "Invalid request body",
+			Success: false,
+		})
+		return
+	}
+
+	record, err := service.SendTransaction(middleware.GetLicense(c).ID, request)
+	if err != nil {
+		c.JSON(400, models.GenericResponse[any]{
+			Object:  nil,
+			Error:   err.Error(),
+			Success: false,
+		})
+		return
+	}
+
+	c.JSON(202, models.GenericResponse[*models.TransactionRecord]{
+		Object:  record,
+		Error:   "",
+		Success: true,
+	})
+}
+
+func GetTransactionStatus(c *gin.Context) {
+	record, err := service.GetTransactionStatus(middleware.GetLicense(c).ID, c.Param("signature"))
+	if err != nil {
+		status := 500
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			status = 404
+		}
+		c.JSON(status, models.GenericResponse[any]{
+			Object:  nil,
+			Error:   "Transaction not found",
+			Success: false,
+		})
+		return
+	}
+
+	c.JSON(200, models.GenericResponse[*models.TransactionRecord]{
+		Object:  record,
+		Error:   "",
+		Success: true,
+	})
+}
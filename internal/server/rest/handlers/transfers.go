@@ -0,0 +1,37 @@
+package handlers
+
+import (
+	"main/internal/server/service"
+	"main/pkg/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+func BuildTransfer(c *gin.Context) {
+	var request models.BuildTransferRequest
+	err := c.BindJSON(&request)
+	if err != nil || request.From == "" || request.To == "" || request.Amount == "" {
+		c.JSON(400, models.GenericResponse[any]{
+			Object:  nil,
+			Error:   "Invalid request body",
+			Success: false,
+		})
+		return
+	}
+
+	result, err := service.BuildTransfer(request)
+	if err != nil {
+		c.JSON(400, models.GenericResponse[any]{
+			Object:  nil,
+			Error:   err.Error(),
+			Success: false,
+		})
+		return
+	}
+
+	c.JSON(200, models.GenericResponse[*models.BuildTransferResponse]{
+		Object:  result,
+		Error:   "",
+		Success: true,
+	})
+}
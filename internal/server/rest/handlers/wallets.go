@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"main/internal/server/service"
+	"main/pkg/models"
+	"main/pkg/solana"
+
+	"github.com/gin-gonic/gin"
+)
+
+func GetBalanceHistory(c *gin.Context) {
+	result, err := service.GetBalanceHistory(c.Param("address"), c.Query("from"), c.Query("to"), c.Query("interval"))
+	if err != nil {
+		c.JSON(400, models.GenericResponse[any]{
+			Object:  nil,
+			Error:   err.Error(),
+			Success: false,
+		})
+		return
+	}
+
+	c.JSON(200, models.GenericResponse[*models.BalanceHistoryResponse]{
+		Object:  result,
+		Error:   "",
+		Success: true,
+	})
+}
+
+func GetPortfolio(c *gin.Context) {
+	address := c.Param("address")
+	if !solana.IsValidAddress(address) {
+		c.JSON(400, models.GenericResponse[any]{
+			Object:  nil,
+			Error:   "Invalid wallet address",
+			Success: false,
+		})
+		return
+	}
+
+	portfolio, err := service.GetPortfolio(address)
+	if err != nil {
+		c.JSON(502, models.GenericResponse[any]{
+			Object:  nil,
+			Error:   "Failed to value portfolio: " + err.Error(),
+			Success: false,
+		})
+		return
+	}
+
+	c.JSON(200, models.GenericResponse[*models.Portfolio]{
+		Object:  portfolio,
+		Error:   "",
+		Success: true,
+	})
+}
@@ -0,0 +1,214 @@
+package handlers
+
+import (
+	"errors"
+	"main/internal/server/rest/middleware"
+	"main/internal/server/service"
+	"main/pkg/models"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func CreateWatchlist(c *gin.Context) {
+	var request models.WatchlistRequest
+	if err := c.BindJSON(&request); err != nil {
+		c.JSON(400, models.GenericResponse[any]{
+			Object:  nil,
+			Error:   "Invalid request body",
+			Success: false,
+		})
+		return
+	}
+
+	watchlist, err := service.CreateWatchlist(middleware.GetLicense(c).ID, middleware.GetPlan(c), request)
+	if errors.Is(err, service.ErrWatchedWalletsBusy) {
+		c.JSON(409, models.GenericResponse[any]{
+			Object:  nil,
+			Error:   err.Error(),
+			Success: false,
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(400, models.GenericResponse[any]{
+			Object:  nil,
+			Error:   err.Error(),
+			Success: false,
+		})
+		return
+	}
+
+	c.JSON(201, models.GenericResponse[*models.Watchlist]{
+		Object:  watchlist,
+		Error:   "",
+		Success: true,
+	})
+}
+
+func ListWatchlists(c *gin.Context) {
+	result, err := service.ListWatchlists(middleware.GetLicense(c).ID)
+	if err != nil {
+		c.JSON(500, models.GenericResponse[any]{
+			Object:  nil,
+			Error:   "Failed to list watchlists",
+			Success: false,
+		})
+		return
+	}
+
+	c.JSON(200, models.GenericResponse[[]models.Watchlist]{
+		Object:  result,
+		Error:   "",
+		Success: true,
+	})
+}
+
+func GetWatchlist(c *gin.Context) {
+	watchlist, ok := loadWatchlist(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(200, models.GenericResponse[*models.Watchlist]{
+		Object:  watchlist,
+		Error:   "",
+		Success: true,
+	})
+}
+
+func UpdateWatchlist(c *gin.Context) {
+	id, ok := watchlistID(c)
+	if !ok {
+		return
+	}
+
+	var request models.WatchlistRequest
+	if err := c.BindJSON(&request); err != nil {
+		c.JSON(400, models.GenericResponse[any]{
+			Object:  nil,
+			Error:   "Invalid request body",
+			Success: false,
+		})
+		return
+	}
+
+	watchlist, err := service.UpdateWatchlist(middleware.GetLicense(c).ID, id, middleware.GetPlan(c), request)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		watchlistNotFound(c)
+		return
+	}
+	if errors.Is(err, service.ErrWatchedWalletsBusy) {
+		c.JSON(409, models.GenericResponse[any]{
+			Object:  nil,
+			Error:   err.Error(),
+			Success: false,
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(400, models.GenericResponse[any]{
+			Object:  nil,
+			Error:   err.Error(),
+			Success: false,
+		})
+		return
+	}
+
+	c.JSON(200, models.GenericResponse[*models.Watchlist]{
+		Object:  watchlist,
+		Error:   "",
+		Success: true,
+	})
+}
+
+func DeleteWatchlist(c *gin.Context) {
+	id, ok := watchlistID(c)
+	if !ok {
+		return
+	}
+
+	err := service.DeleteWatchlist(middleware.GetLicense(c).ID, id)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		watchlistNotFound(c)
+		return
+	}
+	if err != nil {
+		c.JSON(500, models.GenericResponse[any]{
+			Object:  nil,
+			Error:   "Failed to delete watchlist",
+			Success: false,
+		})
+		return
+	}
+
+	c.JSON(200, models.GenericResponse[any]{
+		Object:  nil,
+		Error:   "",
+		Success: true,
+	})
+}
+
+func GetWatchlistBalances(c *gin.Context) {
+	watchlist, ok := loadWatchlist(c)
+	if !ok {
+		return
+	}
+	if !checkWalletsPerRequest(c, len(watchlist.Wallets)) || !middleware.ChargeCredits(c, models.OperationWallet, len(watchlist.Wallets)) {
+		return
+	}
+
+	addresses := make([]string, len(watchlist.Wallets))
+	for i, wallet := range watchlist.Wallets {
+		addresses[i] = wallet.Address
+	}
+
+	balances := fetchWalletBalances(addresses)
+	result := make([]models.WatchlistBalance, len(balances))
+	for i, balance := range balances {
+		result[i] = models.WatchlistBalance{
+			WalletBalance: balance,
+			Label:         watchlist.Wallets[i].Label,
+		}
+	}
+
+	c.JSON(200, models.GenericResponse[[]models.WatchlistBalance]{
+		Object:  result,
+		Error:   "",
+		Success: true,
+	})
+}
+
+func loadWatchlist(c *gin.Context) (*models.Watchlist, bool) {
+	id, ok := watchlistID(c)
+	if !ok {
+		return nil, false
+	}
+
+	watchlist, err := service.GetWatchlist(middleware.GetLicense(c).ID, id)
+	if err != nil {
+		watchlistNotFound(c)
+		return nil, false
+	}
+
+	return watchlist, true
+}
+
+func watchlistID(c *gin.Context) (primitive.ObjectID, bool) {
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		watchlistNotFound(c)
+		return primitive.NilObjectID, false
+	}
+
+	return id, true
+}
+
+func watchlistNotFound(c *gin.Context) {
+	c.JSON(404, models.GenericResponse[any]{
+		Object:  nil,
+		Error:   "Watchlist not found",
+		Success: false,
+	})
+}
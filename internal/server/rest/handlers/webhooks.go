@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"errors"
+	"main/internal/server/rest/middleware"
+	"main/internal/server/service"
+	"main/pkg/models"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func CreateWebhook(c *gin.Context) {
+	var request models.CreateWebhookRequest
+	if err := c.BindJSON(&request); err != nil {
+		c.JSON(400, models.GenericResponse[any]{
+			Object:  nil,
+			Error:   "Invalid request body",
+			Success: false,
+		})
+		return
+	}
+
+	webhook, err := service.CreateWebhook(middleware.GetLicense(c).ID, request)
+	if err != nil {
+		c.JSON(400, models.GenericResponse[any]{
+			Object:  nil,
+			Error:   err.Error(),
+			Success: false,
+		})
+		return
+	}
+
+	c.JSON(201, models.GenericResponse[*models.Webhook]{
+		Object:  webhook,
+		Error:   "",
+		Success: true,
+	})
+}
+
+func ListWebhooks(c *gin.Context) {
+	result, err := service.ListWebhooks(middleware.GetLicense(c).ID)
+	if err != nil {
+		c.JSON(500, models.GenericResponse[any]{
+			Object:  nil,
+			Error:   "Failed to list webhooks",
+			Success: false,
+		})
+		return
+	}
+
+	c.JSON(200, models.GenericResponse[[]models.Webhook]{
+		Object:  result,
+		Error:   "",
+		Success: true,
+	})
+}
+
+func DeleteWebhook(c *gin.Context) {
+	id, ok := webhookID(c)
+	if !ok {
+		return
+	}
+
+	err := service.DeleteWebhook(middleware.GetLicense(c).ID, id)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		webhookNotFound(c)
+		return
+	}
+	if err != nil {
+		c.JSON(500, models.GenericResponse[any]{
+			Object:  nil,
+			Error:   "Failed to delete webhook",
+			Success: false,
+		})
+		return
+	}
+
+	c.JSON(200, models.GenericResponse[any]{
+		Object:  nil,
+		Error:   "",
+		Success: true,
+	})
+}
+
+func ListWebhookDeliveries(c *gin.Context) {
+	id, ok := webhookID(c)
+	if !ok {
+		return
+	}
+
+	result, err := service.ListWebhookDeliveries(middleware.GetLicense(c).ID, id)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		webhookNotFound(c)
+		return
+	}
+	if err != nil {
+		c.JSON(500, models.GenericResponse[any]{
+			Object:  nil,
+			Error:   "Failed to list webhook deliveries",
+			Success: false,
+		})
+		return
+	}
+
+	c.JSON(200, models.GenericResponse[[]models.WebhookDelivery]{
+		Object:  result,
+		Error:   "",
+		Success: true,
+	})
+}
+
+func webhookID(c *gin.Context) (primitive.ObjectID, bool) {
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		webhookNotFound(c)
+		return primitive.NilObjectID, false
+	}
+
+	return id, true
+}
+
+func webhookNotFound(c *gin.Context) {
+	c.JSON(404, models.GenericResponse[any]{
+		Object:  nil,
+		Error:   "Webhook not found",
+		Success: false,
+	})
+}
@@ -0,0 +1,86 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"errors"
+	"log"
+	"main/pkg/config"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	AdminRoleContextKey = "admin_role"
+
+	// AdminRoleAdmin may read and change licenses.
+	AdminRoleAdmin = "admin"
+	// AdminRoleViewer may only read licenses and their usage.
+	AdminRoleViewer = "viewer"
+)
+
+// AuthenticateAdmin checks the x-admin-key header against ADMIN_API_KEYS. Admin keys are
+// a separate credential from license API keys, a license key never grants admin access.
+func AuthenticateAdmin(c *gin.Context) {
+	adminKey := c.GetHeader("x-admin-key")
+	if adminKey == "" {
+		if err := c.AbortWithError(401, gin.Error{
+			Err:  errors.New("missing admin key"),
+			Type: gin.ErrorTypePublic,
+			Meta: "Missing admin key",
+		}); err != nil {
+			log.Println("Error aborting request: " + err.Error())
+		}
+		return
+	}
+
+	role := adminRole(adminKey)
+	if role == "" {
+		if err := c.AbortWithError(401, gin.Error{
+			Err:  errors.New("invalid admin key"),
+			Type: gin.ErrorTypePublic,
+			Meta: "Invalid admin key",
+		}); err != nil {
+			log.Println("Error aborting request: " + err.Error())
+		}
+		return
+	}
+
+	c.Set(AdminRoleContextKey, role)
+	c.Next()
+}
+
+// RequireAdminRole rejects admins whose role does not allow the route. The admin role
+// includes everything the viewer role may do.
+func RequireAdminRole(role string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		granted := c.GetString(AdminRoleContextKey)
+		if granted == role || granted == AdminRoleAdmin {
+			c.Next()
+			return
+		}
+
+		if err := c.AbortWithError(403, gin.Error{
+			Err:  errors.New("admin role not allowed"),
+			Type: gin.ErrorTypePublic,
+			Meta: "Admin role not allowed",
+		}); err != nil {
+			log.Println("Error aborting request: " + err.Error())
+		}
+	}
+}
+
+// adminRole compares the key against every configured key in constant time and returns
+// its role, or an empty string when no key with a known role matches.
+func adminRole(adminKey string) string {
+	role := ""
+	for key, keyRole := range config.Config.AdminApiKeys {
+		if subtle.ConstantTimeCompare([]byte(key), []byte(adminKey)) == 1 {
+			role = keyRole
+		}
+	}
+	if role != AdminRoleAdmin && role != AdminRoleViewer {
+		return ""
+	}
+
+	return role
+}
@@ -0,0 +1,71 @@
+package middleware
+
+import (
+	"main/pkg/config"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupAdminTestRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	config.Config = &config.Structure{
+		AdminApiKeys: map[string]string{
+			"admin-key":   AdminRoleAdmin,
+			"viewer-key":  AdminRoleViewer,
+			"unknown-key": "owner",
+		},
+	}
+
+	router := gin.New()
+	admin := router.Group("/admin", AuthenticateAdmin)
+	admin.GET("/test", RequireAdminRole(AdminRoleViewer), func(c *gin.Context) {
+		c.JSON(200, gin.H{"message": "success"})
+	})
+	admin.POST("/test", RequireAdminRole(AdminRoleAdmin), func(c *gin.Context) {
+		c.JSON(200, gin.H{"message": "success"})
+	})
+
+	return router
+}
+
+func TestAuthenticateAdmin(t *testing.T) {
+	router := setupAdminTestRouter()
+
+	tests := []struct {
+		name     string
+		method   string
+		adminKey string
+		apiKey   string
+		expected int
+	}{
+		{"missing key", "GET", "", "", http.StatusUnauthorized},
+		{"license key is not an admin key", "GET", "", "admin-key", http.StatusUnauthorized},
+		{"invalid key", "GET", "wrong-key", "", http.StatusUnauthorized},
+		{"unknown role", "GET", "unknown-key", "", http.StatusUnauthorized},
+		{"viewer reads", "GET", "viewer-key", "", http.StatusOK},
+		{"viewer writes", "POST", "viewer-key", "", http.StatusForbidden},
+		{"admin reads", "GET", "admin-key", "", http.StatusOK},
+		{"admin writes", "POST", "admin-key", "", http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(tt.method, "/admin/test", nil)
+			if tt.adminKey != "" {
+				req.Header.Set("x-admin-key", tt.adminKey)
+			}
+			if tt.apiKey != "" {
+				req.Header.Set("x-api-key", tt.apiKey)
+			}
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expected, w.Code)
+		})
+	}
+}
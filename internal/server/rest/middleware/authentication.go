@@ -4,11 +4,36 @@ import (
 	"errors"
 	"log"
 	"main/internal/server/service"
+	"main/pkg/models"
+	"main/pkg/ratelimit"
+	"sync"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
+const (
+	LicenseContextKey    = "license"
+	PlanContextKey       = "plan"
+	RateLimitsContextKey = "rate_limits"
+	releaseContextKey    = "release_concurrency"
+)
+
+// Authenticate validates the API key and applies the rate limits: the per-IP limit first,
+// so invalid keys cannot be tried at will, then the limits and concurrency of the
+// license's plan. The license and its plan are put into the context, credits are charged
+// by the routes, see Charge.
 func Authenticate(c *gin.Context) {
+	status, err := service.CheckIpRateLimit(c.ClientIP())
+	if err != nil {
+		abortRateLimitError(c, err)
+		return
+	}
+	if !status.Allowed {
+		abortRateLimited(c, status, "Too many requests from this IP, please try again later.")
+		return
+	}
+
 	apiKey := c.GetHeader("x-api-key")
 	if apiKey == "" {
 		if err := c.AbortWithError(401, gin.Error{
@@ -21,7 +46,7 @@ func Authenticate(c *gin.Context) {
 		return
 	}
 
-	_, err := service.ValidateLicense(apiKey)
+	license, err := service.ValidateLicense(apiKey)
 	if err != nil {
 		if abortErr := c.AbortWithError(401, gin.Error{
 			Err:  err,
@@ -33,37 +58,70 @@ func Authenticate(c *gin.Context) {
 		return
 	}
 
-	go func() {
-		if err = service.IncrementLicenseUsage(apiKey); err != nil {
-			log.Println("Error incrementing license usage: " + err.Error())
-		}
-	}()
-
-	count, err := service.GetIpRequestCount(c.ClientIP())
+	plan := service.GetLicensePlan(license)
+	// the slot is taken first, a request refused for concurrency spends no rate limit budget
+	release, ok, err := service.AcquireConcurrencySlot(license, plan)
 	if err != nil {
-		if err = c.AbortWithError(429, err); err != nil {
-			log.Println("Error aborting request: " + err.Error())
-		}
+		abortRateLimitError(c, err)
 		return
 	}
-
-	if count >= 10 {
-		if err = c.AbortWithError(429, gin.Error{
-			Err:  err,
-			Type: gin.ErrorTypePublic,
-			Meta: "Too many requests from this IP, please try again later.",
-		}); err != nil {
-			log.Println("Error aborting request: " + err.Error())
-		}
+	if !ok {
+		abortRateLimited(c, ratelimit.Result{RetryAfter: time.Second}, "Too many concurrent requests, please try again later.")
 		return
 	}
+	release = sync.OnceFunc(release)
+	defer release()
 
-	if err = service.IncrementIpRequestCount(c.ClientIP()); err != nil {
-		if err = c.AbortWithError(500, err); err != nil {
-			log.Println("Error aborting request: " + err.Error())
-		}
+	status, budgets, err := service.CheckLicenseRateLimit(license, plan)
+	if err != nil {
+		abortRateLimitError(c, err)
+		return
+	}
+	setRateLimitHeaders(c, status)
+	if !status.Allowed {
+		abortRateLimited(c, status, "Rate limit of the "+plan.Name+" plan exceeded, please try again later.")
 		return
 	}
 
+	c.Set(releaseContextKey, release)
+	c.Set(LicenseContextKey, license)
+	c.Set(PlanContextKey, plan)
+	c.Set(RateLimitsContextKey, budgets)
+	c.Next()
+}
+
+// ReleaseConcurrency frees the concurrency slot of the request before it is done, for
+// long-lived connections that must not hold a slot while they are open.
+func ReleaseConcurrency(c *gin.Context) {
+	if value, exists := c.Get(releaseContextKey); exists {
+		value.(func())()
+	}
 	c.Next()
 }
+
+// GetLicense returns the license of the authenticated caller, or nil outside of Authenticate.
+func GetLicense(c *gin.Context) *models.License {
+	value, exists := c.Get(LicenseContextKey)
+	if !exists {
+		return nil
+	}
+	license, _ := value.(*models.License)
+	return license
+}
+
+// GetPlan returns the plan of the authenticated caller, or nil outside of Authenticate.
+func GetPlan(c *gin.Context) *models.Plan {
+	value, exists := c.Get(PlanContextKey)
+	if !exists {
+		return nil
+	}
+	plan, _ := value.(*models.Plan)
+	return plan
+}
+
+// GetRateLimits returns what is left of each of the caller's rate limits after this request.
+func GetRateLimits(c *gin.Context) []models.RateLimitBudget {
+	value, _ := c.Get(RateLimitsContextKey)
+	budgets, _ := value.([]models.RateLimitBudget)
+	return budgets
+}
@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"errors"
+	"log"
+	"main/internal/server/service"
+	"main/pkg/models"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+const CreditsContextKey = "credits"
+
+// Charge charges the license for one operation before the handler runs. It has to run
+// after Authenticate and the route's scope check, so refused requests cost nothing.
+func Charge(operation string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if ChargeCredits(c, operation, 1) {
+			c.Next()
+		}
+	}
+}
+
+// ChargeCredits charges the license for quantity operations, for handlers whose cost
+// depends on the request. It reports false once it has aborted the request, with a 402
+// when the license's credits do not cover the cost.
+func ChargeCredits(c *gin.Context, operation string, quantity int) bool {
+	err := ChargeStreamCredits(c, operation, quantity)
+	if err == nil {
+		return true
+	}
+
+	var creditErr *models.CreditError
+	if errors.As(err, &creditErr) {
+		c.AbortWithStatusJSON(402, models.GenericResponse[models.CreditError]{
+			Object:  *creditErr,
+			Error:   "Insufficient credits: this request costs " + formatCredits(creditErr.Cost) + ", " + formatCredits(creditErr.Remaining) + " left",
+			Success: false,
+		})
+		return false
+	}
+
+	if err = c.AbortWithError(500, err); err != nil {
+		log.Println("Error aborting request: " + err.Error())
+	}
+	return false
+}
+
+// ChargeStreamCredits charges the license for quantity operations without answering the
+// request, for websocket handlers that can no longer send a status.
+func ChargeStreamCredits(c *gin.Context, operation string, quantity int) error {
+	if err := service.ChargeCredits(GetLicense(c), operation, quantity); err != nil {
+		return err
+	}
+	c.Set(CreditsContextKey, GetCharged(c)+service.CreditCost(operation, quantity))
+	return nil
+}
+
+// GetCharged returns the credits the request has been charged so far.
+func GetCharged(c *gin.Context) int64 {
+	return c.GetInt64(CreditsContextKey)
+}
+
+func formatCredits(credits int64) string {
+	if credits == 1 {
+		return "1 credit"
+	}
+	return strconv.FormatInt(credits, 10) + " credits"
+}
@@ -0,0 +1,71 @@
+package middleware
+
+import (
+	"errors"
+	"log"
+	"main/internal/server/service"
+	"main/pkg/ratelimit"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LimitRoute holds every license to a limit of its own on the route, on top of its plan.
+// It has to run after Authenticate.
+func LimitRoute(route string, limit ratelimit.Limit) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		status, err := service.CheckRouteRateLimit(route, GetLicense(c), limit)
+		if err != nil {
+			abortRateLimitError(c, err)
+			return
+		}
+		if !status.Allowed {
+			setRateLimitHeaders(c, status)
+			abortRateLimited(c, status, "Rate limit of this endpoint exceeded, please try again later.")
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// setRateLimitHeaders reports the deciding limit in the RateLimit-Limit, RateLimit-Remaining
+// and RateLimit-Reset headers of the IETF draft. Unlimited plans get no headers.
+func setRateLimitHeaders(c *gin.Context, status ratelimit.Result) {
+	if status.Limit == 0 {
+		return
+	}
+	c.Header("RateLimit-Limit", strconv.FormatInt(status.Limit, 10))
+	c.Header("RateLimit-Remaining", strconv.FormatInt(status.Remaining, 10))
+	c.Header("RateLimit-Reset", strconv.FormatInt(ceilSeconds(status.Reset), 10))
+}
+
+func abortRateLimited(c *gin.Context, status ratelimit.Result, message string) {
+	c.Header("Retry-After", strconv.FormatInt(ceilSeconds(status.RetryAfter), 10))
+	if err := c.AbortWithError(429, gin.Error{
+		Err:  errors.New("rate limited"),
+		Type: gin.ErrorTypePublic,
+		Meta: message,
+	}); err != nil {
+		log.Println("Error aborting request: " + err.Error())
+	}
+}
+
+// abortRateLimitError answers 503 when the closed fallback policy rejects requests while
+// Redis is unreachable, and 500 for anything else.
+func abortRateLimitError(c *gin.Context, err error) {
+	status := 500
+	if errors.Is(err, ratelimit.ErrUnavailable) {
+		status = 503
+		c.Header("Retry-After", "1")
+	}
+	if err = c.AbortWithError(status, err); err != nil {
+		log.Println("Error aborting request: " + err.Error())
+	}
+}
+
+// ceilSeconds rounds up, so clients never retry before the limit has freed up.
+func ceilSeconds(duration time.Duration) int64 {
+	return int64((duration + time.Second - 1) / time.Second)
+}
@@ -0,0 +1,31 @@
+package middleware
+
+import (
+	"main/pkg/config"
+	"main/pkg/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequireScope rejects licenses without the scope with a 403 naming it. It has to run
+// after Authenticate has put the license into the context.
+func RequireScope(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		license := GetLicense(c)
+		if license != nil && license.HasScope(scope) {
+			c.Next()
+			return
+		}
+
+		c.AbortWithStatusJSON(403, models.GenericResponse[models.ScopeError]{
+			Object:  models.ScopeError{MissingScope: scope},
+			Error:   "License is missing the " + scope + " scope",
+			Success: false,
+		})
+	}
+}
+
+// RequireNetworkScope rejects licenses that are not allowed on the network this instance serves.
+func RequireNetworkScope(c *gin.Context) {
+	RequireScope(models.NetworkScope(config.Config.SolanaNetwork))(c)
+}
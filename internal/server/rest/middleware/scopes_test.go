@@ -0,0 +1,81 @@
+package middleware
+
+import (
+	"encoding/json"
+	"main/pkg/config"
+	"main/pkg/models"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupScopeTestRouter(scopes []string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	config.Config = &config.Structure{SolanaNetwork: "devnet"}
+
+	router := gin.New()
+	api := router.Group("/api", func(c *gin.Context) {
+		c.Set(LicenseContextKey, &models.License{Scopes: scopes})
+	}, RequireNetworkScope)
+	api.GET("/balance", RequireScope(models.ScopeBalanceRead), func(c *gin.Context) {
+		c.JSON(200, gin.H{"message": "success"})
+	})
+	api.POST("/send", RequireScope(models.ScopeTxSend), func(c *gin.Context) {
+		c.JSON(200, gin.H{"message": "success"})
+	})
+
+	return router
+}
+
+func TestRequireScope(t *testing.T) {
+	readOnly := []string{models.ScopeBalanceRead, models.NetworkScope("devnet")}
+
+	tests := []struct {
+		name     string
+		scopes   []string
+		method   string
+		path     string
+		expected int
+		missing  string
+	}{
+		{"granted scope", readOnly, "GET", "/api/balance", http.StatusOK, ""},
+		{"missing scope", readOnly, "POST", "/api/send", http.StatusForbidden, models.ScopeTxSend},
+		{"other network", []string{models.ScopeBalanceRead, models.NetworkScope("mainnet")}, "GET", "/api/balance", http.StatusForbidden, "network:devnet"},
+		{"no scopes", nil, "GET", "/api/balance", http.StatusForbidden, "network:devnet"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := setupScopeTestRouter(tt.scopes)
+			req, _ := http.NewRequest(tt.method, tt.path, nil)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expected, w.Code)
+			if tt.missing != "" {
+				var response models.GenericResponse[models.ScopeError]
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+				assert.Equal(t, tt.missing, response.Object.MissingScope)
+			}
+		})
+	}
+}
+
+func TestRequireScopeWithoutLicense(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/balance", RequireScope(models.ScopeBalanceRead), func(c *gin.Context) {
+		c.JSON(200, gin.H{"message": "success"})
+	})
+
+	req, _ := http.NewRequest("GET", "/balance", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
@@ -0,0 +1,20 @@
+package middleware
+
+import (
+	"main/internal/server/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MeterUsage records every authenticated request in the license's usage, by endpoint and
+// with the credits it was charged. Responses with a 4xx or 5xx status count as errors.
+// It has to run right after Authenticate.
+func MeterUsage(c *gin.Context) {
+	c.Next()
+
+	license := GetLicense(c)
+	if license == nil {
+		return
+	}
+	service.RecordUsage(license.ID, c.Request.Method+" "+c.FullPath(), GetCharged(c), c.Writer.Status() >= 400)
+}
@@ -0,0 +1,17 @@
+package routers
+
+import (
+	"main/internal/server/rest/handlers"
+
+	"github.com/gin-gonic/gin"
+)
+
+// setupAccountRoutes registers the caller's view of their own license. It needs no scope
+// and costs no credits, so holders can always check what they have left.
+func setupAccountRoutes(app *gin.Engine, apiAuth *gin.RouterGroup) {
+	me := apiAuth.Group("/me")
+	{
+		me.GET("", handlers.GetAccount)
+		me.GET("/usage", handlers.GetAccountUsage)
+	}
+}
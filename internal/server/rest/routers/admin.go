@@ -0,0 +1,37 @@
+package routers
+
+import (
+	"main/internal/server/rest/handlers"
+	"main/internal/server/rest/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// setupAdminRoutes registers license and plan administration and usage statements. It sits outside /api and is
+// authenticated with admin keys instead of license keys.
+func setupAdminRoutes(app *gin.Engine) {
+	admin := app.Group("/admin", middleware.AuthenticateAdmin)
+	viewer := middleware.RequireAdminRole(middleware.AdminRoleViewer)
+	editor := middleware.RequireAdminRole(middleware.AdminRoleAdmin)
+
+	licenses := admin.Group("/licenses")
+	{
+		licenses.POST("", editor, handlers.CreateLicense)
+		licenses.GET("", viewer, handlers.ListLicenses)
+		licenses.GET("/:id", viewer, handlers.GetLicense)
+		licenses.PATCH("/:id", editor, handlers.UpdateLicense)
+		licenses.DELETE("/:id", editor, handlers.RevokeLicense)
+		licenses.POST("/:id/rotate", editor, handlers.RotateLicenseKey)
+		licenses.GET("/:id/rotations", viewer, handlers.ListKeyRotations)
+		licenses.GET("/:id/statement", viewer, handlers.GetUsageStatement)
+	}
+
+	plans := admin.Group("/plans")
+	{
+		plans.GET("", viewer, handlers.ListPlans)
+		plans.PUT("/:id", editor, handlers.SavePlan)
+		plans.DELETE("/:id", editor, handlers.DeletePlan)
+	}
+
+	admin.GET("/usage/statements", viewer, handlers.ListUsageStatements)
+}
@@ -0,0 +1,22 @@
+package routers
+
+import (
+	"main/internal/server/rest/handlers"
+	"main/internal/server/rest/middleware"
+	"main/pkg/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+func setupAlertRoutes(app *gin.Engine, apiAuth *gin.RouterGroup) {
+	alerts := apiAuth.Group("/alerts")
+	read := middleware.RequireScope(models.ScopeAlertsRead)
+	write := middleware.RequireScope(models.ScopeAlertsWrite)
+	charge := middleware.Charge(models.OperationRequest)
+	{
+		alerts.GET("", read, charge, handlers.ListAlertEvents)
+		alerts.POST("/rules", write, charge, handlers.CreateAlertRule)
+		alerts.GET("/rules", read, charge, handlers.ListAlertRules)
+		alerts.DELETE("/rules/:id", write, charge, handlers.DeleteAlertRule)
+	}
+}
@@ -0,0 +1,16 @@
+package routers
+
+import (
+	"main/internal/server/rest/handlers"
+	"main/internal/server/rest/middleware"
+	"main/pkg/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+func setupFeeRoutes(app *gin.Engine, apiAuth *gin.RouterGroup) {
+	fees := apiAuth.Group("/fees", middleware.RequireScope(models.ScopeTxRead), middleware.Charge(models.OperationRequest))
+	{
+		fees.GET("/priority", handlers.GetPriorityFees)
+	}
+}
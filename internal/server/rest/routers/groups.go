@@ -0,0 +1,24 @@
+package routers
+
+import (
+	"main/internal/server/rest/handlers"
+	"main/internal/server/rest/middleware"
+	"main/pkg/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+func setupWalletGroupRoutes(app *gin.Engine, apiAuth *gin.RouterGroup) {
+	groups := apiAuth.Group("/groups")
+	read := middleware.RequireScope(models.ScopeWatchlistsRead)
+	write := middleware.RequireScope(models.ScopeWatchlistsWrite)
+	charge := middleware.Charge(models.OperationRequest)
+	{
+		groups.POST("", write, charge, handlers.CreateWalletGroup)
+		groups.GET("", read, charge, handlers.ListWalletGroups)
+		groups.GET("/:id", read, charge, handlers.GetWalletGroup)
+		groups.PUT("/:id", write, charge, handlers.UpdateWalletGroup)
+		groups.DELETE("/:id", write, charge, handlers.DeleteWalletGroup)
+		groups.GET("/:id/balances", read, handlers.GetWalletGroupBalance)
+	}
+}
@@ -0,0 +1,27 @@
+package routers
+
+import (
+	"main/internal/server/rest/handlers"
+	"main/internal/server/rest/middleware"
+	"main/pkg/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// setupLicenseRoutes registers the self-serve purchase flow and key rotation. The purchase
+// flow is public, as buyers of a new license do not have an API key yet and expired
+// licenses must still be able to top up.
+func setupLicenseRoutes(app *gin.Engine, apiAuth *gin.RouterGroup) {
+	licenses := app.Group("/licenses")
+	{
+		licenses.POST("/purchase", handlers.PurchaseLicense)
+		licenses.POST("/top-up", handlers.TopUpLicense)
+		licenses.GET("/purchases/:reference", handlers.GetLicensePurchase)
+	}
+
+	license := apiAuth.Group("/license")
+	{
+		license.POST("/rotate", middleware.RequireScope(models.ScopeLicenseWrite), handlers.RotateOwnLicenseKey)
+		license.GET("/rotations", middleware.RequireScope(models.ScopeLicenseRead), handlers.ListOwnKeyRotations)
+	}
+}
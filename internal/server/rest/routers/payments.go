@@ -0,0 +1,18 @@
+package routers
+
+import (
+	"main/internal/server/rest/handlers"
+	"main/internal/server/rest/middleware"
+	"main/pkg/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+func setupPaymentRoutes(app *gin.Engine, apiAuth *gin.RouterGroup) {
+	payments := apiAuth.Group("/payments")
+	charge := middleware.Charge(models.OperationRequest)
+	{
+		payments.POST("", middleware.RequireScope(models.ScopePaymentsWrite), charge, handlers.CreatePaymentRequest)
+		payments.GET("/:reference", middleware.RequireScope(models.ScopePaymentsRead), charge, handlers.GetPaymentRequest)
+	}
+}
@@ -7,7 +7,7 @@ import (
 )
 
 func SetupRoutes(app *gin.Engine) {
-	apiAuth := app.Group("/api", middleware.Authenticate)
+	apiAuth := app.Group("/api", middleware.Authenticate, middleware.MeterUsage, middleware.RequireNetworkScope)
 
 	app.GET("/health", func(c *gin.Context) {
 		c.JSON(200, gin.H{
@@ -17,4 +17,18 @@ func SetupRoutes(app *gin.Engine) {
 		})
 	})
 	setupSolanaRoutes(app, apiAuth)
+	setupTransactionRoutes(app, apiAuth)
+	setupFeeRoutes(app, apiAuth)
+	setupPaymentRoutes(app, apiAuth)
+	setupLicenseRoutes(app, apiAuth)
+	setupAccountRoutes(app, apiAuth)
+	setupStreamRoutes(app, apiAuth)
+	setupWatchlistRoutes(app, apiAuth)
+	setupWebhookRoutes(app, apiAuth)
+	setupAlertRoutes(app, apiAuth)
+	setupWalletRoutes(app, apiAuth)
+	setupWalletGroupRoutes(app, apiAuth)
+	setupTokenRoutes(app, apiAuth)
+	setupSnapshotRoutes(app, apiAuth)
+	setupAdminRoutes(app)
 }
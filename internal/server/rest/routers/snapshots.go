@@ -0,0 +1,26 @@
+package routers
+
+import (
+	"main/internal/server/rest/handlers"
+	"main/internal/server/rest/middleware"
+	"main/pkg/models"
+	"main/pkg/ratelimit"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// snapshotLimit bounds snapshot jobs per license, each one scans every account of a mint.
+var snapshotLimit = ratelimit.Limit{Algorithm: ratelimit.SlidingWindow, Limit: 10, Window: time.Hour}
+
+func setupSnapshotRoutes(app *gin.Engine, apiAuth *gin.RouterGroup) {
+	snapshots := apiAuth.Group("/snapshots")
+	read := middleware.RequireScope(models.ScopeTokensRead)
+	charge := middleware.Charge(models.OperationRequest)
+	{
+		snapshots.POST("", middleware.RequireScope(models.ScopeSnapshotsWrite), middleware.LimitRoute("snapshots", snapshotLimit), charge, handlers.CreateSnapshot)
+		snapshots.GET("", read, charge, handlers.ListSnapshots)
+		snapshots.GET("/:id", read, charge, handlers.GetSnapshot)
+		snapshots.GET("/:id/export", read, charge, handlers.ExportSnapshot)
+	}
+}
@@ -2,12 +2,14 @@ package routers
 
 import (
 	"main/internal/server/rest/handlers"
+	"main/internal/server/rest/middleware"
+	"main/pkg/models"
 
 	"github.com/gin-gonic/gin"
 )
 
 func setupSolanaRoutes(app *gin.Engine, apiAuth *gin.RouterGroup) {
-	solana := apiAuth.Group("")
+	solana := apiAuth.Group("", middleware.RequireScope(models.ScopeBalanceRead))
 	{
 		solana.POST("/get-balance", handlers.GetSolanaBalance)
 	}
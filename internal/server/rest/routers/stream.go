@@ -0,0 +1,16 @@
+package routers
+
+import (
+	"main/internal/server/rest/handlers"
+	"main/internal/server/rest/middleware"
+	"main/pkg/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+func setupStreamRoutes(app *gin.Engine, apiAuth *gin.RouterGroup) {
+	stream := apiAuth.Group("/stream", middleware.RequireScope(models.ScopeBalanceRead), middleware.Charge(models.OperationRequest), middleware.ReleaseConcurrency)
+	{
+		stream.GET("/balances", handlers.StreamBalances)
+	}
+}
@@ -0,0 +1,16 @@
+package routers
+
+import (
+	"main/internal/server/rest/handlers"
+	"main/internal/server/rest/middleware"
+	"main/pkg/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+func setupTokenRoutes(app *gin.Engine, apiAuth *gin.RouterGroup) {
+	tokens := apiAuth.Group("/tokens", middleware.RequireScope(models.ScopeTokensRead), middleware.Charge(models.OperationTokenLookup))
+	{
+		tokens.GET("/:mint", handlers.GetTokenInfo)
+	}
+}
@@ -0,0 +1,20 @@
+package routers
+
+import (
+	"main/internal/server/rest/handlers"
+	"main/internal/server/rest/middleware"
+	"main/pkg/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+func setupTransactionRoutes(app *gin.Engine, apiAuth *gin.RouterGroup) {
+	transactions := apiAuth.Group("/transactions")
+	read := middleware.RequireScope(models.ScopeTxRead)
+	charge := middleware.Charge(models.OperationRequest)
+	{
+		transactions.POST("/send", middleware.RequireScope(models.ScopeTxSend), charge, handlers.SendTransaction)
+		transactions.POST("/build/transfer", read, charge, handlers.BuildTransfer)
+		transactions.GET("/:signature", read, middleware.Charge(models.OperationTransactionFetch), handlers.GetTransactionStatus)
+	}
+}
@@ -0,0 +1,17 @@
+package routers
+
+import (
+	"main/internal/server/rest/handlers"
+	"main/internal/server/rest/middleware"
+	"main/pkg/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+func setupWalletRoutes(app *gin.Engine, apiAuth *gin.RouterGroup) {
+	wallets := apiAuth.Group("/wallets", middleware.RequireScope(models.ScopeBalanceRead))
+	{
+		wallets.GET("/:address/history", middleware.Charge(models.OperationRequest), handlers.GetBalanceHistory)
+		wallets.GET("/:address/portfolio", middleware.Charge(models.OperationWallet), handlers.GetPortfolio)
+	}
+}
@@ -0,0 +1,24 @@
+package routers
+
+import (
+	"main/internal/server/rest/handlers"
+	"main/internal/server/rest/middleware"
+	"main/pkg/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+func setupWatchlistRoutes(app *gin.Engine, apiAuth *gin.RouterGroup) {
+	watchlists := apiAuth.Group("/watchlists")
+	read := middleware.RequireScope(models.ScopeWatchlistsRead)
+	write := middleware.RequireScope(models.ScopeWatchlistsWrite)
+	charge := middleware.Charge(models.OperationRequest)
+	{
+		watchlists.POST("", write, charge, handlers.CreateWatchlist)
+		watchlists.GET("", read, charge, handlers.ListWatchlists)
+		watchlists.GET("/:id", read, charge, handlers.GetWatchlist)
+		watchlists.PUT("/:id", write, charge, handlers.UpdateWatchlist)
+		watchlists.DELETE("/:id", write, charge, handlers.DeleteWatchlist)
+		watchlists.GET("/:id/balances", read, handlers.GetWatchlistBalances)
+	}
+}
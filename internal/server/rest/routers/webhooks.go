@@ -0,0 +1,22 @@
+package routers
+
+import (
+	"main/internal/server/rest/handlers"
+	"main/internal/server/rest/middleware"
+	"main/pkg/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+func setupWebhookRoutes(app *gin.Engine, apiAuth *gin.RouterGroup) {
+	webhooks := apiAuth.Group("/webhooks")
+	read := middleware.RequireScope(models.ScopeWebhooksRead)
+	write := middleware.RequireScope(models.ScopeWebhooksWrite)
+	charge := middleware.Charge(models.OperationRequest)
+	{
+		webhooks.POST("", write, charge, handlers.CreateWebhook)
+		webhooks.GET("", read, charge, handlers.ListWebhooks)
+		webhooks.DELETE("/:id", write, charge, handlers.DeleteWebhook)
+		webhooks.GET("/:id/deliveries", read, charge, handlers.ListWebhookDeliveries)
+	}
+}
@@ -1,22 +1,52 @@
 package server
 
 import (
+	"context"
+	"errors"
 	"log"
 	"main/internal/server/rest/routers"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
+// shutdownTimeout is how long requests in flight may take to finish on shutdown.
+const shutdownTimeout = 10 * time.Second
+
+// Start serves the API until the listener fails or the process is told to stop, in
+// which case it returns once the requests in flight are done.
 func Start(port string) error {
 	app := gin.Default()
 	gin.SetMode(gin.DebugMode)
 
 	routers.SetupRoutes(app)
 
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	server := &http.Server{Addr: ":" + port, Handler: app}
+	errs := make(chan error, 1)
+	go func() {
+		errs <- server.ListenAndServe()
+	}()
+
 	log.Println("Starting server on port " + port)
-	if err := app.Run(":" + port); err != nil {
+	select {
+	case err := <-errs:
 		log.Println("Error starting server: " + err.Error())
 		return err
+	case <-ctx.Done():
+	}
+
+	log.Println("Shutting down server")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
 	}
 
 	return nil
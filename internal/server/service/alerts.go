@@ -0,0 +1,326 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"main/internal/database/mongo"
+	mongo2 "main/internal/server/repo/mongo"
+	"main/pkg/config"
+	"main/pkg/models"
+	"main/pkg/solana"
+	"reflect"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	maxAlertWindow     = 7 * 24 * time.Hour
+	alertEventLogLimit = 100
+)
+
+var (
+	alerts       *mongo2.Alerts
+	alertService mongo2.AlertsImpl
+)
+
+// alertLocks serializes evaluation per wallet. Observers run concurrently and the rule
+// state is read and written back as a whole, but rules belong to a single wallet, so
+// observations of different wallets do not wait for each other.
+var alertLocks = struct {
+	sync.Mutex
+	wallets map[string]*walletAlertLock
+}{wallets: make(map[string]*walletAlertLock)}
+
+type walletAlertLock struct {
+	sync.Mutex
+	// holders counts the evaluations holding or waiting for the lock, it is dropped at zero.
+	holders int
+}
+
+func initAlerts() {
+	alerts = &mongo2.Alerts{
+		Collection: mongo.Database.Collection("alert_rules"),
+		Events:     mongo.Database.Collection("alert_events"),
+	}
+	alertService = mongo2.AlertsImpl(alerts)
+
+	addBalanceObserver(evaluateAlerts)
+}
+
+// CreateAlertRule counts the rule's wallet against the plan's watched wallets, as wallets
+// with rules are refreshed like watched wallets.
+func CreateAlertRule(licenseID primitive.ObjectID, plan *models.Plan, request models.CreateAlertRuleRequest) (*models.AlertRule, error) {
+	if alertService == nil {
+		return nil, fmt.Errorf("alert service not initialized")
+	}
+	if watchlistService == nil {
+		return nil, fmt.Errorf("watchlist service not initialized")
+	}
+
+	rule, err := newAlertRule(licenseID, request)
+	if err != nil {
+		return nil, err
+	}
+	err = withWatchedWalletLock(licenseID, func() error {
+		if err := checkWatchedWalletLimit(licenseID, plan, primitive.NilObjectID, []string{rule.Wallet}); err != nil {
+			return err
+		}
+		return alertService.CreateRule(rule)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return rule, nil
+}
+
+func ListAlertRules(licenseID primitive.ObjectID) ([]models.AlertRule, error) {
+	if alertService == nil {
+		return nil, fmt.Errorf("alert service not initialized")
+	}
+
+	return alertService.ListRules(licenseID)
+}
+
+func DeleteAlertRule(licenseID, id primitive.ObjectID) error {
+	if alertService == nil {
+		return fmt.Errorf("alert service not initialized")
+	}
+
+	return alertService.DeleteRule(licenseID, id)
+}
+
+// ListAlertEvents returns the most recent alerts fired for the license.
+func ListAlertEvents(licenseID primitive.ObjectID) ([]models.AlertEvent, error) {
+	if alertService == nil {
+		return nil, fmt.Errorf("alert service not initialized")
+	}
+
+	return alertService.ListEvents(licenseID, alertEventLogLimit)
+}
+
+// alertedWallets lists the wallets with alert rules, which the watchlist refresher keeps
+// observing even when nobody queries them.
+func alertedWallets() []string {
+	if alertService == nil {
+		return nil
+	}
+
+	wallets, err := alertService.ListAlertedWallets()
+	if err != nil {
+		log.Println("Error listing alerted wallets:", err)
+		return nil
+	}
+	return wallets
+}
+
+func newAlertRule(licenseID primitive.ObjectID, request models.CreateAlertRuleRequest) (*models.AlertRule, error) {
+	if request.Name == "" {
+		return nil, errors.New("name is required")
+	}
+	if !solana.IsValidAddress(request.Wallet) {
+		return nil, errors.New("invalid wallet address")
+	}
+	if request.CallbackUrl != "" {
+		if err := validateOutboundUrl(request.CallbackUrl); err != nil {
+			return nil, err
+		}
+	}
+	if request.CooldownSeconds < 0 {
+		return nil, errors.New("cooldown_seconds must not be negative")
+	}
+
+	rule := &models.AlertRule{
+		LicenseID:       licenseID,
+		Name:            request.Name,
+		Wallet:          request.Wallet,
+		Type:            request.Type,
+		CooldownSeconds: request.CooldownSeconds,
+		CallbackUrl:     request.CallbackUrl,
+	}
+	if rule.CooldownSeconds == 0 {
+		rule.CooldownSeconds = int64(config.Config.AlertDefaultCooldown / time.Second)
+	}
+
+	switch request.Type {
+	case models.AlertBalanceBelow, models.AlertOutgoingTransfer:
+		threshold, err := solana.ParseAmount(request.Threshold, 9)
+		if err != nil || threshold == 0 {
+			return nil, errors.New("threshold must be a positive SOL amount")
+		}
+		rule.ThresholdLamports = threshold
+	case models.AlertBalanceDrop:
+		if request.DropPercent <= 0 || request.DropPercent > 100 {
+			return nil, errors.New("drop_percent must be between 0 and 100")
+		}
+		window := time.Duration(request.WindowSeconds) * time.Second
+		if window <= 0 || window > maxAlertWindow {
+			return nil, fmt.Errorf("window_seconds must be between 1 and %d", int64(maxAlertWindow/time.Second))
+		}
+		rule.DropPercent = request.DropPercent
+		rule.WindowSeconds = request.WindowSeconds
+	default:
+		return nil, errors.New("type must be balance_below, balance_drop or outgoing_transfer")
+	}
+
+	return rule, nil
+}
+
+func evaluateAlerts(observation models.BalanceObservation) {
+	unlock := lockWalletAlerts(observation.Wallet)
+	defer unlock()
+
+	rules, err := alertService.FindRulesForWallet(observation.Wallet)
+	if err != nil {
+		log.Println("Error finding alert rules for " + observation.Wallet + ": " + err.Error())
+		return
+	}
+
+	for i := range rules {
+		rule := &rules[i]
+		before := rule.State
+		before.Peaks = append([]models.AlertPeak(nil), rule.State.Peaks...)
+
+		fired, message := evaluateAlertRule(rule, observation)
+		if !reflect.DeepEqual(before, rule.State) {
+			if err = alertService.UpdateRuleState(rule.ID, rule.State); err != nil {
+				log.Println("Error saving alert state " + rule.ID.Hex() + ": " + err.Error())
+				continue
+			}
+		}
+		if fired {
+			fireAlert(rule, observation, message)
+		}
+	}
+}
+
+func lockWalletAlerts(wallet string) func() {
+	alertLocks.Lock()
+	lock, ok := alertLocks.wallets[wallet]
+	if !ok {
+		lock = &walletAlertLock{}
+		alertLocks.wallets[wallet] = lock
+	}
+	lock.holders++
+	alertLocks.Unlock()
+
+	lock.Lock()
+	return func() {
+		lock.Unlock()
+
+		alertLocks.Lock()
+		defer alertLocks.Unlock()
+		lock.holders--
+		if lock.holders == 0 {
+			delete(alertLocks.wallets, wallet)
+		}
+	}
+}
+
+// evaluateAlertRule advances the rule state with the observation and reports whether an
+// alert should be sent. A rule fires once when its condition starts to hold, not again
+// while it keeps holding, and never twice within its cooldown.
+func evaluateAlertRule(rule *models.AlertRule, observation models.BalanceObservation) (bool, string) {
+	state := &rule.State
+	if state.LastSlot != 0 && observation.Slot <= state.LastSlot {
+		return false, ""
+	}
+	state.LastSlot = observation.Slot
+
+	holds, message := alertCondition(rule, observation)
+	if !holds {
+		state.Firing = false
+		return false, ""
+	}
+	if state.Firing {
+		return false, ""
+	}
+
+	// within the cooldown the rule stays armed, so a condition that still holds
+	// afterwards is reported then
+	cooldown := time.Duration(rule.CooldownSeconds) * time.Second
+	if state.LastFiredAt != nil && observation.ObservedAt.Sub(*state.LastFiredAt) < cooldown {
+		return false, ""
+	}
+
+	firedAt := observation.ObservedAt
+	state.Firing = true
+	state.LastFiredAt = &firedAt
+	return true, message
+}
+
+func alertCondition(rule *models.AlertRule, observation models.BalanceObservation) (bool, string) {
+	switch rule.Type {
+	case models.AlertBalanceBelow:
+		if observation.Lamports < rule.ThresholdLamports {
+			return true, fmt.Sprintf("balance %s SOL is below %s SOL",
+				solana.LamportsToSol(observation.Lamports), solana.LamportsToSol(rule.ThresholdLamports))
+		}
+	case models.AlertOutgoingTransfer:
+		// balances are sampled, so this is the net decrease between two observations
+		if observation.Previous != nil && *observation.Previous > observation.Lamports &&
+			*observation.Previous-observation.Lamports > rule.ThresholdLamports {
+			return true, fmt.Sprintf("outgoing transfer of %s SOL exceeds %s SOL",
+				solana.LamportsToSol(*observation.Previous-observation.Lamports), solana.LamportsToSol(rule.ThresholdLamports))
+		}
+	case models.AlertBalanceDrop:
+		window := time.Duration(rule.WindowSeconds) * time.Second
+		rule.State.Peaks = updatePeaks(rule.State.Peaks, observation.Lamports, observation.ObservedAt, window)
+		peak := rule.State.Peaks[0].Lamports
+		if peak == 0 || peak <= observation.Lamports {
+			return false, ""
+		}
+		drop := float64(peak-observation.Lamports) / float64(peak) * 100
+		if drop > rule.DropPercent {
+			return true, fmt.Sprintf("balance dropped %.2f%% from %s SOL to %s SOL within %s",
+				drop, solana.LamportsToSol(peak), solana.LamportsToSol(observation.Lamports), window)
+		}
+	}
+	return false, ""
+}
+
+// updatePeaks keeps a monotonic queue of the balances that can still be the maximum of
+// the window. The last balance held until now, so its time is moved forward before the
+// new one is added.
+func updatePeaks(peaks []models.AlertPeak, lamports uint64, now time.Time, window time.Duration) []models.AlertPeak {
+	if len(peaks) > 0 {
+		peaks[len(peaks)-1].SeenAt = now
+	}
+	for len(peaks) > 0 && peaks[len(peaks)-1].Lamports <= lamports {
+		peaks = peaks[:len(peaks)-1]
+	}
+	peaks = append(peaks, models.AlertPeak{Lamports: lamports, SeenAt: now})
+
+	cutoff := now.Add(-window)
+	for len(peaks) > 1 && peaks[0].SeenAt.Before(cutoff) {
+		peaks = peaks[1:]
+	}
+	return peaks
+}
+
+func fireAlert(rule *models.AlertRule, observation models.BalanceObservation, message string) {
+	event := &models.AlertEvent{
+		RuleID:    rule.ID,
+		LicenseID: rule.LicenseID,
+		RuleName:  rule.Name,
+		Type:      rule.Type,
+		Wallet:    observation.Wallet,
+		Lamports:  observation.Lamports,
+		Slot:      observation.Slot,
+		Message:   message,
+		FiredAt:   observation.ObservedAt,
+	}
+	if observation.Previous != nil {
+		event.Previous = *observation.Previous
+	}
+
+	if err := alertService.CreateEvent(event); err != nil {
+		log.Println("Error saving alert event for rule " + rule.ID.Hex() + ": " + err.Error())
+	}
+	if rule.CallbackUrl != "" {
+		go sendCallback(rule.CallbackUrl, event)
+	}
+}
@@ -0,0 +1,89 @@
+package service
+
+import (
+	"main/pkg/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func observe(lamports, slot uint64, previous *uint64, at time.Time) models.BalanceObservation {
+	return models.BalanceObservation{
+		Wallet:     "wallet",
+		Lamports:   lamports,
+		Previous:   previous,
+		Slot:       slot,
+		ObservedAt: at,
+	}
+}
+
+func TestEvaluateAlertRuleDedupeAndCooldown(t *testing.T) {
+	start := time.Now()
+	rule := &models.AlertRule{Type: models.AlertBalanceBelow, ThresholdLamports: 100, CooldownSeconds: 60}
+
+	fired, _ := evaluateAlertRule(rule, observe(50, 1, nil, start))
+	assert.True(t, fired)
+
+	fired, _ = evaluateAlertRule(rule, observe(40, 2, nil, start.Add(time.Second)))
+	assert.False(t, fired, "still firing")
+
+	fired, _ = evaluateAlertRule(rule, observe(40, 2, nil, start.Add(2*time.Second)))
+	assert.False(t, fired, "same slot")
+
+	evaluateAlertRule(rule, observe(150, 3, nil, start.Add(3*time.Second)))
+	fired, _ = evaluateAlertRule(rule, observe(50, 4, nil, start.Add(4*time.Second)))
+	assert.False(t, fired, "flapping within the cooldown")
+
+	fired, _ = evaluateAlertRule(rule, observe(50, 5, nil, start.Add(61*time.Second)))
+	assert.True(t, fired, "still below after the cooldown")
+}
+
+func TestEvaluateAlertRuleOutgoingTransfer(t *testing.T) {
+	rule := &models.AlertRule{Type: models.AlertOutgoingTransfer, ThresholdLamports: 100}
+	previous := uint64(1000)
+
+	fired, _ := evaluateAlertRule(rule, observe(950, 1, &previous, time.Now()))
+	assert.False(t, fired)
+
+	fired, message := evaluateAlertRule(rule, observe(800, 2, &previous, time.Now()))
+	assert.True(t, fired)
+	assert.Contains(t, message, "0.000000200")
+}
+
+func TestEvaluateAlertRuleBalanceDrop(t *testing.T) {
+	start := time.Now()
+	rule := &models.AlertRule{Type: models.AlertBalanceDrop, DropPercent: 20, WindowSeconds: 3600}
+
+	evaluateAlertRule(rule, observe(100, 1, nil, start))
+	evaluateAlertRule(rule, observe(100, 2, nil, start.Add(50*time.Minute)))
+	fired, _ := evaluateAlertRule(rule, observe(85, 3, nil, start.Add(55*time.Minute)))
+	assert.False(t, fired, "15% drop")
+
+	fired, _ = evaluateAlertRule(rule, observe(70, 4, nil, start.Add(100*time.Minute)))
+	assert.True(t, fired, "the 100 balance was held until 55 minutes")
+
+	rule = &models.AlertRule{Type: models.AlertBalanceDrop, DropPercent: 20, WindowSeconds: 3600}
+	evaluateAlertRule(rule, observe(100, 1, nil, start))
+	evaluateAlertRule(rule, observe(90, 2, nil, start.Add(time.Minute)))
+	fired, _ = evaluateAlertRule(rule, observe(75, 3, nil, start.Add(3*time.Hour)))
+	assert.False(t, fired, "the 100 peak left the window")
+}
+
+func TestLockWalletAlerts(t *testing.T) {
+	unlockA := lockWalletAlerts("a")
+
+	done := make(chan struct{})
+	go func() {
+		lockWalletAlerts("b")()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("another wallet waited for the lock")
+	}
+
+	unlockA()
+	assert.Empty(t, alertLocks.wallets, "unused locks are dropped")
+}
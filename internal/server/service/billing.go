@@ -0,0 +1,149 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"main/pkg/config"
+	"main/pkg/models"
+	"math/big"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	mongodriver "go.mongodb.org/mongo-driver/mongo"
+)
+
+const statementMonthLayout = "2006-01"
+
+var ErrInvalidMonth = errors.New("invalid month, expected YYYY-MM")
+
+// GetUsageStatement returns the license's statement of the month, formatted as 2006-01
+// and defaulting to the previous month, broken down by endpoint.
+func GetUsageStatement(licenseID primitive.ObjectID, month string) (*models.UsageStatement, error) {
+	if licenseKeyService == nil || licenseUsageService == nil {
+		return nil, fmt.Errorf("license usage service not initialized")
+	}
+	from, to, err := parseStatementMonth(month, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	license, err := licenseKeyService.GetLicenseByID(licenseID)
+	if err != nil && !errors.Is(err, mongodriver.ErrNoDocuments) {
+		return nil, err
+	}
+	endpoints, err := licenseUsageService.EndpointUsage(licenseID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	if license == nil {
+		// buckets outlive deleted licenses, they are still billed as in ListUsageStatements
+		if len(endpoints) == 0 {
+			return nil, mongodriver.ErrNoDocuments
+		}
+		license = &models.License{ID: licenseID}
+	}
+
+	statement := newUsageStatement(license, from)
+	statement.Endpoints = endpoints
+	for _, endpoint := range endpoints {
+		statement.Requests += endpoint.Requests
+		statement.Errors += endpoint.Errors
+		statement.Credits += endpoint.Credits
+	}
+	if err = priceStatement(statement); err != nil {
+		return nil, err
+	}
+
+	return statement, nil
+}
+
+// ListUsageStatements returns the statement of the month of every license that used the
+// API in it, without the breakdown by endpoint.
+func ListUsageStatements(month string) ([]models.UsageStatement, error) {
+	if licenseKeyService == nil || licenseUsageService == nil {
+		return nil, fmt.Errorf("license usage service not initialized")
+	}
+	from, to, err := parseStatementMonth(month, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	totals, err := licenseUsageService.LicenseTotals(from, to)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]primitive.ObjectID, len(totals))
+	for i, total := range totals {
+		ids[i] = total.LicenseID
+	}
+	licenses, err := licenseKeyService.GetLicensesByID(ids)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[primitive.ObjectID]*models.License, len(licenses))
+	for i := range licenses {
+		byID[licenses[i].ID] = &licenses[i]
+	}
+
+	statements := make([]models.UsageStatement, 0, len(totals))
+	for _, total := range totals {
+		license, ok := byID[total.LicenseID]
+		if !ok {
+			// buckets outlive deleted licenses, they are still billed
+			license = &models.License{ID: total.LicenseID}
+		}
+		statement := newUsageStatement(license, from)
+		statement.Requests = total.Requests
+		statement.Errors = total.Errors
+		statement.Credits = total.Credits
+		if err = priceStatement(statement); err != nil {
+			return nil, err
+		}
+		statements = append(statements, *statement)
+	}
+
+	return statements, nil
+}
+
+func newUsageStatement(license *models.License, month time.Time) *models.UsageStatement {
+	return &models.UsageStatement{
+		LicenseID:   license.ID,
+		LicenseName: license.Name,
+		Plan:        GetLicensePlan(license).ID,
+		Month:       month.Format(statementMonthLayout),
+	}
+}
+
+// priceStatement turns the statement into an invoice when BILLING_CREDIT_PRICE is set.
+// The amount is rounded to cents.
+func priceStatement(statement *models.UsageStatement) error {
+	if config.Config.BillingCreditPrice == "" {
+		return nil
+	}
+	price, ok := new(big.Rat).SetString(config.Config.BillingCreditPrice)
+	if !ok || price.Sign() < 0 {
+		return errors.New("invalid BILLING_CREDIT_PRICE: " + config.Config.BillingCreditPrice)
+	}
+
+	amount := new(big.Rat).Mul(price, new(big.Rat).SetInt64(statement.Credits))
+	statement.Currency = config.Config.BillingCurrency
+	statement.UnitPrice = config.Config.BillingCreditPrice
+	statement.Amount = amount.FloatString(2)
+	return nil
+}
+
+// parseStatementMonth returns the first and the last day of the month, the range of its
+// daily usage buckets. An empty month is the month before now.
+func parseStatementMonth(month string, now time.Time) (time.Time, time.Time, error) {
+	current := now.UTC()
+	first := time.Date(current.Year(), current.Month(), 1, 0, 0, 0, 0, time.UTC).AddDate(0, -1, 0)
+	if month != "" {
+		var err error
+		if first, err = time.Parse(statementMonthLayout, month); err != nil {
+			return time.Time{}, time.Time{}, ErrInvalidMonth
+		}
+	}
+
+	last := first.AddDate(0, 1, -1)
+	return first, last, nil
+}
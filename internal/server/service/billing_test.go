@@ -0,0 +1,47 @@
+package service
+
+import (
+	"main/pkg/config"
+	"main/pkg/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseStatementMonth(t *testing.T) {
+	now := time.Date(2024, 1, 10, 12, 0, 0, 0, time.UTC)
+
+	from, to, err := parseStatementMonth("", now)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2023, 12, 1, 0, 0, 0, 0, time.UTC), from, "defaults to the previous month")
+	assert.Equal(t, time.Date(2023, 12, 31, 0, 0, 0, 0, time.UTC), to)
+
+	from, to, err = parseStatementMonth("2024-02", now)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), from)
+	assert.Equal(t, time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC), to, "leap year")
+
+	_, _, err = parseStatementMonth("2024-13", now)
+	assert.ErrorIs(t, err, ErrInvalidMonth)
+	_, _, err = parseStatementMonth("February", now)
+	assert.ErrorIs(t, err, ErrInvalidMonth)
+}
+
+func TestPriceStatement(t *testing.T) {
+	config.Config = &config.Structure{BillingCurrency: "USD"}
+
+	statement := &models.UsageStatement{Credits: 1234}
+	require.NoError(t, priceStatement(statement))
+	assert.Empty(t, statement.Amount, "no price, no invoice")
+
+	config.Config.BillingCreditPrice = "0.0015"
+	require.NoError(t, priceStatement(statement))
+	assert.Equal(t, "USD", statement.Currency)
+	assert.Equal(t, "0.0015", statement.UnitPrice)
+	assert.Equal(t, "1.85", statement.Amount)
+
+	config.Config.BillingCreditPrice = "free"
+	assert.Error(t, priceStatement(statement))
+}
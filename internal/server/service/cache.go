@@ -1,23 +1,29 @@
 package service
 
-import "main/internal/server/repo/redis"
+import (
+	"main/internal/server/repo/redis"
+	"main/pkg/config"
+)
 
 var (
 	cache        = &redis.Cache{}
 	cacheService = redis.CacheService(cache)
 )
 
-func GetIpRequestCount(ip string) (int, error) {
-	res, err := cacheService.GetIpRequestCount(ip)
+// SetWallet caches a balance fetched over RPC for the default TTL.
+func SetWallet(wallet, balance string) error {
+	err := cacheService.SetWallet(wallet, balance)
 	if err != nil {
-		return 0, err
+		return err
 	}
 
-	return res, nil
+	return nil
 }
 
-func IncrementIpRequestCount(ip string) error {
-	err := cacheService.IncrementIpRequestCount(ip)
+// setSubscribedWallet caches a balance pushed by an account subscription. The subscription
+// keeps the entry current, so it is cached for much longer than the default TTL.
+func setSubscribedWallet(wallet, balance string) error {
+	err := cacheService.SetWalletWithTTL(wallet, balance, config.Config.HotWalletTTL)
 	if err != nil {
 		return err
 	}
@@ -25,8 +31,8 @@ func IncrementIpRequestCount(ip string) error {
 	return nil
 }
 
-func SetWallet(wallet, balance string) error {
-	err := cacheService.SetWallet(wallet, balance)
+func DeleteWallet(wallet string) error {
+	err := cacheService.DeleteWallet(wallet)
 	if err != nil {
 		return err
 	}
@@ -42,3 +48,48 @@ func GetWallet(wallet string) (string, error) {
 
 	return res, nil
 }
+
+func SetPriorityFees(accounts, estimate string) error {
+	err := cacheService.SetPriorityFees(accounts, estimate)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func GetPriorityFees(accounts string) (string, error) {
+	res, err := cacheService.GetPriorityFees(accounts)
+	if err != nil {
+		return "", err
+	}
+
+	return res, nil
+}
+
+func SetWalletGroupCache(id, balance string) error {
+	err := cacheService.SetWalletGroup(id, balance, config.Config.WalletGroupCacheTTL)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func GetWalletGroupCache(id string) (string, error) {
+	res, err := cacheService.GetWalletGroup(id)
+	if err != nil {
+		return "", err
+	}
+
+	return res, nil
+}
+
+func DeleteWalletGroupCache(id string) error {
+	err := cacheService.DeleteWalletGroup(id)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,30 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"log"
+	"time"
+)
+
+var callbackClient = newOutboundClient(10 * time.Second)
+
+// sendCallback POSTs payload as JSON to url. Failures are logged and not retried.
+func sendCallback(url string, payload any) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		log.Println("Error encoding callback payload: " + err.Error())
+		return
+	}
+
+	resp, err := callbackClient.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		log.Println("Error sending callback to " + url + ": " + err.Error())
+		return
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		log.Printf("Callback to %s returned status %d", url, resp.StatusCode)
+	}
+}
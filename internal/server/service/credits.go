@@ -0,0 +1,134 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"main/pkg/config"
+	"main/pkg/models"
+	"strconv"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// defaultCreditCosts are the credits of one operation unless CREDIT_COSTS overrides them.
+var defaultCreditCosts = map[string]int64{
+	models.OperationRequest:          1,
+	models.OperationWallet:           1,
+	models.OperationTokenLookup:      1,
+	models.OperationTransactionFetch: 1,
+}
+
+var creditCosts = defaultCreditCosts
+
+func initCredits() {
+	creditCosts = parseCreditCosts(config.Config.CreditCosts)
+}
+
+// parseCreditCosts overrides the default costs with the configured ones. Unknown
+// operations and invalid costs are logged and ignored.
+func parseCreditCosts(configured map[string]string) map[string]int64 {
+	costs := make(map[string]int64, len(defaultCreditCosts))
+	for operation, cost := range defaultCreditCosts {
+		costs[operation] = cost
+	}
+	for operation, value := range configured {
+		if _, ok := defaultCreditCosts[operation]; !ok {
+			log.Println("Ignoring the cost of unknown operation " + operation)
+			continue
+		}
+		cost, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || cost < 0 {
+			log.Println("Ignoring invalid cost of " + operation + ": " + value)
+			continue
+		}
+		costs[operation] = cost
+	}
+	return costs
+}
+
+// CreditCost returns the credits of quantity operations.
+func CreditCost(operation string, quantity int) int64 {
+	return creditCosts[operation] * int64(quantity)
+}
+
+// ChargeCredits charges the license for quantity operations before they run. Credits are
+// counted in Redis and flushed to Mongo in batches by flushCredits, while Redis is
+// unreachable they are charged in Mongo directly. When the license's remaining credits
+// do not cover the cost nothing is charged and a *models.CreditError is returned.
+func ChargeCredits(license *models.License, operation string, quantity int) error {
+	if licenseKeyService == nil {
+		return fmt.Errorf("license service not initialized")
+	}
+	cost := CreditCost(operation, quantity)
+	if cost == 0 {
+		return nil
+	}
+
+	usageLimit := int64(-1)
+	if license.UsageLimit != nil {
+		usageLimit = *license.UsageLimit
+	}
+	spent, charged, err := cacheService.ChargeCredits(license.ID.Hex(), cost, license.UsageCount, usageLimit)
+	if err == nil {
+		if charged {
+			return nil
+		}
+		return &models.CreditError{Cost: cost, Remaining: max(0, usageLimit-spent)}
+	}
+
+	_, err = licenseKeyService.ChargeUsage(license.ID, cost)
+	if err == nil {
+		// reload the usage count, which the Redis counter is held to once it is back
+		dropCachedLicense(license.ID.Hex())
+		return nil
+	}
+	if !errors.Is(err, models.ErrInsufficientCredits) {
+		return err
+	}
+
+	current, err := licenseKeyService.GetLicenseByID(license.ID)
+	if err != nil {
+		return err
+	}
+	return &models.CreditError{Cost: cost, Remaining: remainingCredits(current)}
+}
+
+// flushCredits writes the credits charged in Redis to Mongo every USAGE_FLUSH_INTERVAL,
+// with one update per license instead of one per request. Credits that fail to flush
+// are put back for the next round.
+func flushCredits() {
+	ticker := time.NewTicker(config.Config.UsageFlushInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		credits, err := cacheService.TakeUnflushedCredits()
+		if err != nil {
+			log.Println("Error taking unflushed credits:", err)
+			continue
+		}
+		for id, amount := range credits {
+			licenseID, err := primitive.ObjectIDFromHex(id)
+			if err != nil {
+				log.Println("Dropping unflushed credits of invalid license ID " + id)
+				continue
+			}
+			if err = licenseKeyService.AddUsage(licenseID, amount); err == nil {
+				continue
+			}
+			log.Println("Error flushing credits of license " + id + ": " + err.Error())
+			if err = cacheService.RestoreUnflushedCredits(id, amount); err != nil {
+				log.Printf("Lost %d credits of license %s: %s", amount, id, err.Error())
+			}
+		}
+	}
+}
+
+// remainingCredits is what the license may still spend, -1 when its usage is unlimited.
+func remainingCredits(license *models.License) int64 {
+	if license.UsageLimit == nil {
+		return -1
+	}
+	return max(0, *license.UsageLimit-license.UsageCount)
+}
@@ -0,0 +1,40 @@
+package service
+
+import (
+	"main/pkg/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCreditCosts(t *testing.T) {
+	costs := parseCreditCosts(map[string]string{
+		models.OperationWallet:      "2",
+		models.OperationTokenLookup: "0",
+		"unknown":                   "5",
+		models.OperationRequest:     "-1",
+	})
+
+	assert.Equal(t, map[string]int64{
+		models.OperationRequest:          1,
+		models.OperationWallet:           2,
+		models.OperationTokenLookup:      0,
+		models.OperationTransactionFetch: 1,
+	}, costs)
+	assert.Equal(t, int64(1), defaultCreditCosts[models.OperationWallet], "defaults are not modified")
+}
+
+func TestCreditCost(t *testing.T) {
+	creditCosts = map[string]int64{models.OperationWallet: 3}
+	t.Cleanup(func() { creditCosts = defaultCreditCosts })
+
+	assert.Equal(t, int64(15), CreditCost(models.OperationWallet, 5))
+	assert.Equal(t, int64(0), CreditCost(models.OperationWallet, 0))
+}
+
+func TestRemainingCredits(t *testing.T) {
+	limit := int64(100)
+	assert.Equal(t, int64(40), remainingCredits(&models.License{UsageCount: 60, UsageLimit: &limit}))
+	assert.Equal(t, int64(0), remainingCredits(&models.License{UsageCount: 120, UsageLimit: &limit}))
+	assert.Equal(t, int64(-1), remainingCredits(&models.License{UsageCount: 60}))
+}
@@ -0,0 +1,109 @@
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"main/pkg/config"
+	"main/pkg/models"
+	"main/pkg/solana"
+	"sort"
+	"strings"
+
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// ValidatePriorityFeeAccounts checks the accounts before they are sent to the RPC node,
+// which rejects the whole call for one invalid address or more than 128 accounts.
+func ValidatePriorityFeeAccounts(accounts []string) error {
+	if len(accounts) > solana.MaxPriorityFeeAccounts {
+		return fmt.Errorf("at most %d accounts are supported", solana.MaxPriorityFeeAccounts)
+	}
+	for _, account := range accounts {
+		if !solana.IsValidAddress(account) {
+			return fmt.Errorf("invalid account: %s", account)
+		}
+	}
+	return nil
+}
+
+// EstimatePriorityFees returns compute unit price suggestions based on the fees paid in
+// recent slots, scoped to the given writable accounts when any are supplied.
+func EstimatePriorityFees(accounts []string) (*models.PriorityFeeEstimate, error) {
+	key := priorityFeesCacheKey(accounts)
+	if cached, err := GetPriorityFees(key); err == nil {
+		var estimate models.PriorityFeeEstimate
+		if err = json.Unmarshal([]byte(cached), &estimate); err == nil {
+			return &estimate, nil
+		}
+	}
+
+	fees, err := solana.NewSolClient().GetRecentPrioritizationFees(accounts)
+	if err != nil {
+		return nil, err
+	}
+
+	estimate := estimateFromSamples(fees, uint64(config.Config.PriorityFeeSlotWindow))
+	estimate.Accounts = accounts
+
+	if encoded, err := json.Marshal(estimate); err == nil {
+		if err = SetPriorityFees(key, string(encoded)); err != nil {
+			log.Println("Error caching priority fees:", err)
+		}
+	}
+
+	return estimate, nil
+}
+
+// estimateFromSamples computes percentiles over the samples that fall within window
+// slots of the most recent one.
+func estimateFromSamples(samples []rpc.PriorizationFeeResult, window uint64) *models.PriorityFeeEstimate {
+	estimate := &models.PriorityFeeEstimate{}
+	for _, sample := range samples {
+		if sample.Slot > estimate.Slot {
+			estimate.Slot = sample.Slot
+		}
+	}
+
+	var fees []uint64
+	for _, sample := range samples {
+		if window > 0 && estimate.Slot-sample.Slot >= window {
+			continue
+		}
+		fees = append(fees, sample.PrioritizationFee)
+	}
+	if len(fees) == 0 {
+		return estimate
+	}
+	sort.Slice(fees, func(i, j int) bool { return fees[i] < fees[j] })
+
+	estimate.Samples = len(fees)
+	estimate.Low = percentile(fees, 25)
+	estimate.Medium = percentile(fees, 50)
+	estimate.High = percentile(fees, 75)
+	estimate.VeryHigh = percentile(fees, 95)
+
+	return estimate
+}
+
+// percentile uses the nearest-rank method on an already sorted slice.
+func percentile(sorted []uint64, p int) uint64 {
+	rank := (p*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+func priorityFeesCacheKey(accounts []string) string {
+	if len(accounts) == 0 {
+		return "global"
+	}
+
+	sorted := append([]string(nil), accounts...)
+	sort.Strings(sorted)
+	sum := sha256.Sum256([]byte(strings.Join(sorted, ",")))
+	return hex.EncodeToString(sum[:])
+}
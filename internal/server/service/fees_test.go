@@ -0,0 +1,62 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEstimateFromSamples_Percentiles(t *testing.T) {
+	var samples []rpc.PriorizationFeeResult
+	for i := uint64(1); i <= 100; i++ {
+		samples = append(samples, rpc.PriorizationFeeResult{Slot: 1000 + i, PrioritizationFee: i * 10})
+	}
+
+	estimate := estimateFromSamples(samples, 150)
+
+	assert.Equal(t, uint64(1100), estimate.Slot)
+	assert.Equal(t, 100, estimate.Samples)
+	assert.Equal(t, uint64(250), estimate.Low)
+	assert.Equal(t, uint64(500), estimate.Medium)
+	assert.Equal(t, uint64(750), estimate.High)
+	assert.Equal(t, uint64(950), estimate.VeryHigh)
+}
+
+func TestEstimateFromSamples_SlotWindow(t *testing.T) {
+	samples := []rpc.PriorizationFeeResult{
+		{Slot: 10, PrioritizationFee: 1_000_000},
+		{Slot: 95, PrioritizationFee: 100},
+		{Slot: 100, PrioritizationFee: 200},
+	}
+
+	estimate := estimateFromSamples(samples, 10)
+
+	assert.Equal(t, 2, estimate.Samples)
+	assert.Equal(t, uint64(200), estimate.VeryHigh, "samples outside the window are ignored")
+}
+
+func TestEstimateFromSamples_Empty(t *testing.T) {
+	estimate := estimateFromSamples(nil, 150)
+
+	assert.Equal(t, 0, estimate.Samples)
+	assert.Equal(t, uint64(0), estimate.Medium)
+}
+
+func TestPriorityFeesCacheKey_OrderIndependent(t *testing.T) {
+	assert.Equal(t, "global", priorityFeesCacheKey(nil))
+	assert.Equal(t,
+		priorityFeesCacheKey([]string{"a", "b"}),
+		priorityFeesCacheKey([]string{"b", "a"}),
+	)
+}
+
+func TestValidatePriorityFeeAccounts(t *testing.T) {
+	valid := "So11111111111111111111111111111111111111112"
+	assert.NoError(t, ValidatePriorityFeeAccounts(nil))
+	assert.NoError(t, ValidatePriorityFeeAccounts([]string{valid}))
+
+	assert.Error(t, ValidatePriorityFeeAccounts([]string{valid, "not-an-address"}))
+	assert.Error(t, ValidatePriorityFeeAccounts(strings.Split(strings.Repeat(valid+",", 128)+valid, ",")), "129 accounts")
+}
@@ -0,0 +1,300 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"main/internal/database/mongo"
+	mongo2 "main/internal/server/repo/mongo"
+	"main/pkg/models"
+	"main/pkg/solana"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	maxWalletGroupMembers = 100
+	// tokenFetchConcurrency bounds the getTokenAccountsByOwner calls made for one group.
+	tokenFetchConcurrency = 8
+)
+
+var (
+	walletGroups       *mongo2.WalletGroups
+	walletGroupService mongo2.WalletGroupsImpl
+)
+
+func initWalletGroups() {
+	walletGroups = &mongo2.WalletGroups{
+		Collection: mongo.Database.Collection("wallet_groups"),
+	}
+	walletGroupService = mongo2.WalletGroupsImpl(walletGroups)
+}
+
+func CreateWalletGroup(licenseID primitive.ObjectID, request models.WalletGroupRequest) (*models.WalletGroup, error) {
+	if walletGroupService == nil {
+		return nil, fmt.Errorf("wallet group service not initialized")
+	}
+	if err := validateWalletGroup(request); err != nil {
+		return nil, err
+	}
+
+	group := &models.WalletGroup{
+		LicenseID: licenseID,
+		Name:      request.Name,
+		Members:   request.Members,
+	}
+	if err := walletGroupService.CreateGroup(group); err != nil {
+		return nil, err
+	}
+
+	return group, nil
+}
+
+func ListWalletGroups(licenseID primitive.ObjectID) ([]models.WalletGroup, error) {
+	if walletGroupService == nil {
+		return nil, fmt.Errorf("wallet group service not initialized")
+	}
+
+	return walletGroupService.ListGroups(licenseID)
+}
+
+func GetWalletGroup(licenseID, id primitive.ObjectID) (*models.WalletGroup, error) {
+	if walletGroupService == nil {
+		return nil, fmt.Errorf("wallet group service not initialized")
+	}
+
+	return walletGroupService.GetGroup(licenseID, id)
+}
+
+func UpdateWalletGroup(licenseID, id primitive.ObjectID, request models.WalletGroupRequest) (*models.WalletGroup, error) {
+	if walletGroupService == nil {
+		return nil, fmt.Errorf("wallet group service not initialized")
+	}
+	if err := validateWalletGroup(request); err != nil {
+		return nil, err
+	}
+
+	group := &models.WalletGroup{
+		ID:        id,
+		LicenseID: licenseID,
+		Name:      request.Name,
+		Members:   request.Members,
+	}
+	if err := walletGroupService.UpdateGroup(group); err != nil {
+		return nil, err
+	}
+	invalidateWalletGroup(id)
+
+	return walletGroupService.GetGroup(licenseID, id)
+}
+
+func DeleteWalletGroup(licenseID, id primitive.ObjectID) error {
+	if walletGroupService == nil {
+		return fmt.Errorf("wallet group service not initialized")
+	}
+	if err := walletGroupService.DeleteGroup(licenseID, id); err != nil {
+		return err
+	}
+	invalidateWalletGroup(id)
+
+	return nil
+}
+
+func invalidateWalletGroup(id primitive.ObjectID) {
+	if err := DeleteWalletGroupCache(id.Hex()); err != nil {
+		log.Println("Error invalidating wallet group cache:", err)
+	}
+}
+
+func validateWalletGroup(request models.WalletGroupRequest) error {
+	if request.Name == "" {
+		return errors.New("name is required")
+	}
+	if len(request.Members) == 0 {
+		return errors.New("a wallet group needs at least one member")
+	}
+	if len(request.Members) > maxWalletGroupMembers {
+		return fmt.Errorf("a wallet group holds at most %d members", maxWalletGroupMembers)
+	}
+
+	seen := make(map[string]bool, len(request.Members))
+	for _, member := range request.Members {
+		if !solana.IsValidAddress(member.Address) {
+			return errors.New("invalid wallet address: " + member.Address)
+		}
+		if seen[member.Address] {
+			return errors.New("duplicate wallet address: " + member.Address)
+		}
+		seen[member.Address] = true
+	}
+
+	return nil
+}
+
+// GetWalletGroupBalance returns the SOL and token totals of the group with a breakdown per
+// member, served from the cache when a recent result exists.
+func GetWalletGroupBalance(group *models.WalletGroup) (*models.WalletGroupBalance, error) {
+	if cached, err := GetWalletGroupCache(group.ID.Hex()); err == nil {
+		var balance models.WalletGroupBalance
+		if err = json.Unmarshal([]byte(cached), &balance); err == nil {
+			balance.Cache = "hit"
+			return &balance, nil
+		}
+	}
+
+	addresses := make([]string, len(group.Members))
+	for i, member := range group.Members {
+		addresses[i] = member.Address
+	}
+
+	client := solana.NewSolClient()
+	lamports, slots, err := client.GetBalances(addresses)
+	if err != nil {
+		return nil, err
+	}
+	holdings, err := fetchTokenHoldings(client, addresses)
+	if err != nil {
+		return nil, err
+	}
+
+	balance := aggregateWalletGroup(group, lamports, holdings)
+	tokenLists := [][]models.TokenTotal{balance.Tokens}
+	for _, member := range balance.Members {
+		tokenLists = append(tokenLists, member.Tokens)
+	}
+	annotateTokenTotals(tokenLists...)
+	// every balance is at least as recent as the oldest batch
+	balance.Slot = minSlot(slots)
+	balance.UpdatedAt = time.Now()
+
+	if encoded, err := json.Marshal(balance); err == nil {
+		if err = SetWalletGroupCache(group.ID.Hex(), string(encoded)); err != nil {
+			log.Println("Error caching wallet group balance:", err)
+		}
+	}
+	balance.Cache = "miss"
+
+	return balance, nil
+}
+
+func minSlot(slots map[string]uint64) uint64 {
+	oldest := uint64(0)
+	for _, slot := range slots {
+		if oldest == 0 || slot < oldest {
+			oldest = slot
+		}
+	}
+	return oldest
+}
+
+func fetchTokenHoldings(client *solana.SolClient, addresses []string) (map[string][]solana.TokenHolding, error) {
+	holdings := make(map[string][]solana.TokenHolding, len(addresses))
+	var (
+		mutex    sync.Mutex
+		wg       sync.WaitGroup
+		firstErr error
+	)
+
+	slots := make(chan struct{}, tokenFetchConcurrency)
+	for _, address := range addresses {
+		wg.Add(1)
+		slots <- struct{}{}
+		go func(address string) {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			result, err := client.GetTokenHoldings(address)
+
+			mutex.Lock()
+			defer mutex.Unlock()
+			if err != nil {
+				if firstErr == nil {
+					firstErr = err
+				}
+				return
+			}
+			holdings[address] = result
+		}(address)
+	}
+	wg.Wait()
+
+	return holdings, firstErr
+}
+
+// aggregateWalletGroup sums balances with integer arithmetic. Lamports and token amounts
+// are added as big integers so totals are exact however large the group gets.
+func aggregateWalletGroup(group *models.WalletGroup, lamports map[string]uint64, holdings map[string][]solana.TokenHolding) *models.WalletGroupBalance {
+	totalLamports := new(big.Int)
+	totals := newTokenTotals()
+
+	members := make([]models.WalletGroupMemberBalance, len(group.Members))
+	for i, member := range group.Members {
+		memberLamports := lamports[member.Address]
+		totalLamports.Add(totalLamports, new(big.Int).SetUint64(memberLamports))
+
+		memberTotals := newTokenTotals()
+		for _, holding := range holdings[member.Address] {
+			memberTotals.add(holding)
+			totals.add(holding)
+		}
+
+		members[i] = models.WalletGroupMemberBalance{
+			Address:  member.Address,
+			Label:    member.Label,
+			Lamports: memberLamports,
+			Sol:      solana.FormatAmount(new(big.Int).SetUint64(memberLamports), 9),
+			Tokens:   memberTotals.list(),
+		}
+	}
+
+	return &models.WalletGroupBalance{
+		GroupID:  group.ID.Hex(),
+		Name:     group.Name,
+		Lamports: totalLamports.String(),
+		Sol:      solana.FormatAmount(totalLamports, 9),
+		Tokens:   totals.list(),
+		Members:  members,
+	}
+}
+
+type tokenTotals struct {
+	amounts  map[string]*big.Int
+	decimals map[string]uint8
+}
+
+func newTokenTotals() *tokenTotals {
+	return &tokenTotals{
+		amounts:  make(map[string]*big.Int),
+		decimals: make(map[string]uint8),
+	}
+}
+
+func (t *tokenTotals) add(holding solana.TokenHolding) {
+	total, ok := t.amounts[holding.Mint]
+	if !ok {
+		total = new(big.Int)
+		t.amounts[holding.Mint] = total
+		t.decimals[holding.Mint] = holding.Decimals
+	}
+	total.Add(total, holding.Amount)
+}
+
+// list returns the totals ordered by mint so cached and fresh results compare equal.
+func (t *tokenTotals) list() []models.TokenTotal {
+	result := make([]models.TokenTotal, 0, len(t.amounts))
+	for mint, amount := range t.amounts {
+		result = append(result, models.TokenTotal{
+			Mint:     mint,
+			Amount:   amount.String(),
+			Decimals: t.decimals[mint],
+			UiAmount: solana.FormatAmount(amount, t.decimals[mint]),
+		})
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Mint < result[j].Mint })
+	return result
+}
@@ -0,0 +1,51 @@
+package service
+
+import (
+	"main/pkg/models"
+	"main/pkg/solana"
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAggregateWalletGroup(t *testing.T) {
+	group := &models.WalletGroup{
+		Name: "treasury",
+		Members: []models.WalletGroupMember{
+			{Address: "a", Label: "cold"},
+			{Address: "b"},
+		},
+	}
+	lamports := map[string]uint64{"a": math.MaxUint64, "b": 1}
+	holdings := map[string][]solana.TokenHolding{
+		"a": {
+			{Mint: "usdc", Amount: big.NewInt(1_500_000), Decimals: 6},
+			{Mint: "usdc", Amount: big.NewInt(500_000), Decimals: 6},
+		},
+		"b": {
+			{Mint: "usdc", Amount: big.NewInt(1), Decimals: 6},
+			{Mint: "bonk", Amount: big.NewInt(42), Decimals: 5},
+		},
+	}
+
+	balance := aggregateWalletGroup(group, lamports, holdings)
+
+	assert.Equal(t, "18446744073709551616", balance.Lamports, "total exceeds uint64 without wrapping")
+	assert.Equal(t, "18446744073.709551616", balance.Sol)
+	assert.Equal(t, []models.TokenTotal{
+		{Mint: "bonk", Amount: "42", Decimals: 5, UiAmount: "0.00042"},
+		{Mint: "usdc", Amount: "2000001", Decimals: 6, UiAmount: "2.000001"},
+	}, balance.Tokens)
+
+	assert.Len(t, balance.Members, 2)
+	assert.Equal(t, "cold", balance.Members[0].Label)
+	assert.Equal(t, []models.TokenTotal{{Mint: "usdc", Amount: "2000000", Decimals: 6, UiAmount: "2.000000"}}, balance.Members[0].Tokens)
+	assert.Equal(t, "0.000000001", balance.Members[1].Sol)
+}
+
+func TestMinSlot(t *testing.T) {
+	assert.Equal(t, uint64(0), minSlot(nil))
+	assert.Equal(t, uint64(90), minSlot(map[string]uint64{"a": 120, "b": 90, "c": 100}), "the oldest batch is reported")
+}
@@ -0,0 +1,176 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"main/internal/database/mongo"
+	mongo2 "main/internal/server/repo/mongo"
+	"main/pkg/config"
+	"main/pkg/models"
+	"main/pkg/solana"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	historyFlushInterval = 5 * time.Second
+	historyFlushSize     = 500
+	historyBufferSize    = 10000
+	historyDefaultRange  = 24 * time.Hour
+	historyMinInterval   = time.Minute
+	historyMaxResults    = 10000
+)
+
+var (
+	balanceHistory        *mongo2.BalanceHistory
+	balanceHistoryService mongo2.BalanceHistoryImpl
+	historyPoints         = make(chan models.BalancePoint, historyBufferSize)
+	// droppedHistoryPoints counts the points dropped since the writer last logged them.
+	droppedHistoryPoints atomic.Int64
+)
+
+func initBalanceHistory() {
+	balanceHistory = &mongo2.BalanceHistory{
+		Collection: mongo.Database.Collection("balance_history"),
+	}
+	if err := balanceHistory.EnsureCollection(config.Config.BalanceHistoryRetention); err != nil {
+		log.Println("Error creating balance history collection: " + err.Error())
+	}
+	balanceHistoryService = mongo2.BalanceHistoryImpl(balanceHistory)
+
+	addBalanceObserver(recordBalancePoint)
+	go writeBalanceHistory()
+}
+
+// recordBalancePoint queues the observation for the batch writer. When Mongo falls behind
+// the point is dropped rather than blocking the observers, and the drops are logged by
+// the writer.
+func recordBalancePoint(observation models.BalanceObservation) {
+	point := models.BalancePoint{
+		Wallet:     observation.Wallet,
+		Lamports:   observation.Lamports,
+		Slot:       observation.Slot,
+		ObservedAt: observation.ObservedAt,
+	}
+
+	select {
+	case historyPoints <- point:
+	default:
+		droppedHistoryPoints.Add(1)
+	}
+}
+
+// writeBalanceHistory inserts queued points in batches, every historyFlushInterval or
+// as soon as historyFlushSize points are waiting.
+func writeBalanceHistory() {
+	ticker := time.NewTicker(historyFlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]models.BalancePoint, 0, historyFlushSize)
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		if err := balanceHistoryService.InsertPoints(batch); err != nil {
+			log.Println("Error writing balance history: " + err.Error())
+		}
+		batch = batch[:0]
+	}
+
+	for {
+		select {
+		case point := <-historyPoints:
+			batch = append(batch, point)
+			if len(batch) >= historyFlushSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+			if dropped := droppedHistoryPoints.Swap(0); dropped > 0 {
+				log.Printf("Balance history buffer full, dropped %d observations", dropped)
+			}
+		}
+	}
+}
+
+// GetBalanceHistory returns the raw observations of the wallet in the range, or buckets
+// with min, max and last balance when an interval is given.
+func GetBalanceHistory(wallet, from, to, interval string) (*models.BalanceHistoryResponse, error) {
+	if balanceHistoryService == nil {
+		return nil, fmt.Errorf("balance history service not initialized")
+	}
+	if !solana.IsValidAddress(wallet) {
+		return nil, errors.New("invalid wallet address")
+	}
+
+	query, err := parseHistoryQuery(from, to, interval, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	response := &models.BalanceHistoryResponse{
+		Wallet: wallet,
+		From:   query.from,
+		To:     query.to,
+	}
+	if query.interval == 0 {
+		response.Points, err = balanceHistoryService.ListPoints(wallet, query.from, query.to, historyMaxResults)
+		return response, err
+	}
+
+	response.Interval = query.interval.String()
+	response.Buckets, err = balanceHistoryService.ListBuckets(wallet, query.from, query.to, query.interval)
+	return response, err
+}
+
+type historyQuery struct {
+	from     time.Time
+	to       time.Time
+	interval time.Duration
+}
+
+// parseHistoryQuery reads from and to as RFC 3339 or Unix seconds, defaulting to the last
+// 24 hours, and interval as a Go duration such as "1h". An empty interval means raw points.
+func parseHistoryQuery(from, to, interval string, now time.Time) (historyQuery, error) {
+	query := historyQuery{to: now}
+
+	var err error
+	if to != "" {
+		if query.to, err = parseHistoryTime(to); err != nil {
+			return query, errors.New("invalid to: " + err.Error())
+		}
+	}
+	query.from = query.to.Add(-historyDefaultRange)
+	if from != "" {
+		if query.from, err = parseHistoryTime(from); err != nil {
+			return query, errors.New("invalid from: " + err.Error())
+		}
+	}
+	if !query.from.Before(query.to) {
+		return query, errors.New("from must be before to")
+	}
+
+	if interval == "" {
+		return query, nil
+	}
+	if query.interval, err = time.ParseDuration(interval); err != nil {
+		return query, errors.New("invalid interval: " + err.Error())
+	}
+	if query.interval < historyMinInterval {
+		return query, fmt.Errorf("interval must be at least %s", historyMinInterval)
+	}
+	if query.to.Sub(query.from)/query.interval > historyMaxResults {
+		return query, fmt.Errorf("range holds more than %d intervals", historyMaxResults)
+	}
+
+	return query, nil
+}
+
+func parseHistoryTime(value string) (time.Time, error) {
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.Unix(seconds, 0).UTC(), nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
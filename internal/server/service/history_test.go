@@ -0,0 +1,34 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseHistoryQuery(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	query, err := parseHistoryQuery("", "", "", now)
+	require.NoError(t, err)
+	assert.Equal(t, now.Add(-24*time.Hour), query.from)
+	assert.Equal(t, now, query.to)
+	assert.Zero(t, query.interval)
+
+	query, err = parseHistoryQuery("2024-05-02T12:00:00Z", "1717243200", "1h", now)
+	require.NoError(t, err)
+	assert.Equal(t, now.Add(-30*24*time.Hour), query.from)
+	assert.True(t, now.Equal(query.to))
+	assert.Equal(t, time.Hour, query.interval)
+
+	_, err = parseHistoryQuery("", "", "10s", now)
+	assert.Error(t, err, "interval below a minute")
+
+	_, err = parseHistoryQuery("2020-01-01T00:00:00Z", "", "1m", now)
+	assert.Error(t, err, "too many buckets")
+
+	_, err = parseHistoryQuery("1717243200", "1717243200", "", now)
+	assert.Error(t, err, "empty range")
+}
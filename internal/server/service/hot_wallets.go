@@ -0,0 +1,123 @@
+package service
+
+import (
+	"log"
+	"main/pkg/config"
+	"main/pkg/solana"
+	"main/pkg/stream"
+	"sync"
+	"time"
+)
+
+var (
+	walletFetches    = make(map[string]int)
+	hotWallets       = make(map[string]bool)
+	hotWalletsMutex  = sync.Mutex{}
+	hotWalletUpdates = make(chan stream.BalanceUpdate, 1024)
+)
+
+func initHotWallets() {
+	if config.Config.HotWalletThreshold <= 0 {
+		return
+	}
+
+	stream.OnDisconnect(invalidateHotWallets)
+	go applyHotWalletUpdates()
+	go demoteHotWallets()
+}
+
+// RecordWalletFetch counts a balance lookup. Wallets looked up at least
+// HotWalletThreshold times within HotWalletWindow get an account subscription that
+// keeps their cache entry current, so it can live for HotWalletTTL.
+func RecordWalletFetch(wallet string) {
+	if config.Config.HotWalletThreshold <= 0 {
+		return
+	}
+
+	hotWalletsMutex.Lock()
+	defer hotWalletsMutex.Unlock()
+
+	walletFetches[wallet]++
+	if hotWallets[wallet] || walletFetches[wallet] < config.Config.HotWalletThreshold {
+		return
+	}
+	if len(hotWallets) >= config.Config.HotWalletMax {
+		return
+	}
+
+	if err := stream.Subscribe(wallet, hotWalletUpdates); err != nil {
+		log.Println("Error subscribing to hot wallet:", err)
+		return
+	}
+	hotWallets[wallet] = true
+}
+
+func isHotWallet(wallet string) bool {
+	hotWalletsMutex.Lock()
+	defer hotWalletsMutex.Unlock()
+
+	return hotWallets[wallet]
+}
+
+func applyHotWalletUpdates() {
+	for update := range hotWalletUpdates {
+		if update.Error != "" {
+			// the subscription is gone, the wallet may be promoted again later
+			hotWalletsMutex.Lock()
+			delete(hotWallets, update.Wallet)
+			hotWalletsMutex.Unlock()
+			continue
+		}
+		if !isHotWallet(update.Wallet) {
+			continue
+		}
+		ObserveBalance(update.Wallet, update.Lamports, update.Slot)
+		if err := setSubscribedWallet(update.Wallet, solana.LamportsToSol(update.Lamports)); err != nil {
+			log.Println("Error updating hot wallet cache:", err)
+		}
+	}
+}
+
+// demoteHotWallets drops the subscription of wallets that cooled down during the last
+// window and evicts their long lived cache entry.
+func demoteHotWallets() {
+	ticker := time.NewTicker(config.Config.HotWalletWindow)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		hotWalletsMutex.Lock()
+		var cooled []string
+		for wallet := range hotWallets {
+			if walletFetches[wallet] < config.Config.HotWalletThreshold {
+				cooled = append(cooled, wallet)
+				delete(hotWallets, wallet)
+				stream.Unsubscribe(wallet, hotWalletUpdates)
+			}
+		}
+		walletFetches = make(map[string]int)
+		hotWalletsMutex.Unlock()
+
+		evictWallets(cooled)
+	}
+}
+
+// invalidateHotWallets evicts every hot wallet, their cache entries cannot be trusted
+// after notifications may have been missed.
+func invalidateHotWallets() {
+	hotWalletsMutex.Lock()
+	wallets := make([]string, 0, len(hotWallets))
+	for wallet := range hotWallets {
+		wallets = append(wallets, wallet)
+	}
+	hotWalletsMutex.Unlock()
+
+	evictWallets(wallets)
+}
+
+func evictWallets(wallets []string) {
+	for _, wallet := range wallets {
+		if err := DeleteWallet(wallet); err != nil {
+			log.Println("Error evicting wallet from cache:", err)
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package service
+
+import "main/internal/database/mongo"
+
+// Init starts the background services and wires the Mongo backed ones. Package init functions run before
+// mongo.Init has connected, so this has to be called explicitly afterwards.
+func Init() {
+	initHotWallets()
+	initRateLimiter()
+	initCredits()
+	initPrices()
+
+	// Only initialize if MongoDB is available
+	if mongo.Database == nil {
+		return
+	}
+
+	initLicenses()
+	initPlans()
+	initUsage()
+	initTransactions()
+	initPayments()
+	initLicensePayments()
+	initLicenseRotations()
+	initWatchlists()
+	initWebhooks()
+	initWalletGroups()
+	initTokens()
+	initAlerts()
+	initBalanceHistory()
+	initSnapshots()
+}
+
+// Shutdown writes what the background services still hold in memory. It is called once
+// the server stopped taking requests.
+func Shutdown() {
+	flushPendingUsage()
+}
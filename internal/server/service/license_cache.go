@@ -0,0 +1,208 @@
+package service
+
+import (
+	"errors"
+	"log"
+	mongo2 "main/internal/server/repo/mongo"
+	"main/pkg/config"
+	"main/pkg/models"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	mongodriver "go.mongodb.org/mongo-driver/mongo"
+)
+
+// licenseCacheSweepInterval is how often expired entries are dropped from the process cache.
+const licenseCacheSweepInterval = time.Minute
+
+type cachedLicenseID struct {
+	// id is empty for keys that belong to no license.
+	id      string
+	expires time.Time
+}
+
+type cachedLicense struct {
+	license *models.License
+	expires time.Time
+}
+
+// licenseCache keeps licenses in process in front of the Redis cache. Licenses are
+// cached by ID and keys map to the ID, so a change to a license is invalidated with
+// its ID alone, whichever keys it was looked up by.
+var licenseCache = struct {
+	sync.Mutex
+	ids       map[string]cachedLicenseID
+	licenses  map[string]cachedLicense
+	lastSweep time.Time
+}{
+	ids:      make(map[string]cachedLicenseID),
+	licenses: make(map[string]cachedLicense),
+}
+
+// lookupLicense returns the license of the key from the process cache, Redis or Mongo,
+// in that order, or mongo.ErrNoDocuments when the key belongs to no license. Whether
+// the key is still accepted has to be checked on the result.
+func lookupLicense(key string) (*models.License, error) {
+	hash := mongo2.HashLicenseKey(key)
+	now := time.Now()
+
+	id, found := cachedLicenseIDOf(hash, now)
+	if found && id == "" {
+		return nil, mongodriver.ErrNoDocuments
+	}
+	if found {
+		if license := cachedLicenseOf(id, now); license != nil {
+			return license, nil
+		}
+	}
+
+	license, err := licenseKeyService.GetLicenseByKey(key)
+	if errors.Is(err, mongodriver.ErrNoDocuments) {
+		cacheLicenseID(hash, "", config.Config.LicenseNegativeCacheTTL, now)
+		return nil, err
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	id = license.ID.Hex()
+	cacheLicenseID(hash, id, config.Config.LicenseCacheTTL, now)
+	cacheLicense(id, license, now)
+	return license, nil
+}
+
+func cachedLicenseIDOf(hash string, now time.Time) (string, bool) {
+	licenseCache.Lock()
+	entry, ok := licenseCache.ids[hash]
+	licenseCache.Unlock()
+	if ok && now.Before(entry.expires) {
+		return entry.id, true
+	}
+
+	id, err := cacheService.GetLicenseID(hash)
+	if err != nil {
+		return "", false
+	}
+	ttl := config.Config.LicenseCacheTTL
+	if id == "" {
+		ttl = config.Config.LicenseNegativeCacheTTL
+	}
+	storeLicenseID(hash, id, now.Add(ttl), now)
+	return id, true
+}
+
+func cachedLicenseOf(id string, now time.Time) *models.License {
+	licenseCache.Lock()
+	entry, ok := licenseCache.licenses[id]
+	licenseCache.Unlock()
+	if ok && now.Before(entry.expires) {
+		return entry.license
+	}
+
+	data, err := cacheService.GetLicense(id)
+	if err != nil {
+		return nil
+	}
+	var license models.License
+	if err = bson.Unmarshal(data, &license); err != nil {
+		return nil
+	}
+	storeLicense(id, &license, now.Add(config.Config.LicenseCacheTTL), now)
+	return &license
+}
+
+func cacheLicenseID(hash, id string, ttl time.Duration, now time.Time) {
+	storeLicenseID(hash, id, now.Add(ttl), now)
+	if err := cacheService.SetLicenseID(hash, id, ttl); err != nil {
+		log.Println("Error caching license key: " + err.Error())
+	}
+}
+
+func cacheLicense(id string, license *models.License, now time.Time) {
+	storeLicense(id, license, now.Add(config.Config.LicenseCacheTTL), now)
+	data, err := bson.Marshal(license)
+	if err != nil {
+		log.Println("Error encoding license " + id + ": " + err.Error())
+		return
+	}
+	if err = cacheService.SetLicense(id, data, config.Config.LicenseCacheTTL); err != nil {
+		log.Println("Error caching license " + id + ": " + err.Error())
+	}
+}
+
+func storeLicenseID(hash, id string, expires, now time.Time) {
+	licenseCache.Lock()
+	defer licenseCache.Unlock()
+	sweepLicenseCache(now)
+	licenseCache.ids[hash] = cachedLicenseID{id: id, expires: expires}
+}
+
+func storeLicense(id string, license *models.License, expires, now time.Time) {
+	licenseCache.Lock()
+	defer licenseCache.Unlock()
+	sweepLicenseCache(now)
+	licenseCache.licenses[id] = cachedLicense{license: license, expires: expires}
+}
+
+// sweepLicenseCache drops expired entries, so keys that were tried once do not pile up.
+// The caller holds the lock.
+func sweepLicenseCache(now time.Time) {
+	if now.Sub(licenseCache.lastSweep) < licenseCacheSweepInterval {
+		return
+	}
+	licenseCache.lastSweep = now
+
+	for hash, entry := range licenseCache.ids {
+		if !now.Before(entry.expires) {
+			delete(licenseCache.ids, hash)
+		}
+	}
+	for id, entry := range licenseCache.licenses {
+		if !now.Before(entry.expires) {
+			delete(licenseCache.licenses, id)
+		}
+	}
+}
+
+// InvalidateLicense drops the cached license everywhere: in Redis, in this process and,
+// through pub/sub, in every other instance. It has to be called after every change to
+// a license.
+func InvalidateLicense(id primitive.ObjectID) {
+	dropCachedLicense(id.Hex())
+	if err := cacheService.DeleteLicense(id.Hex()); err != nil {
+		log.Println("Error deleting cached license " + id.Hex() + ": " + err.Error())
+	}
+	if err := cacheService.PublishLicenseInvalidation(id.Hex()); err != nil {
+		log.Println("Error publishing invalidation of license " + id.Hex() + ": " + err.Error())
+	}
+}
+
+func dropCachedLicense(id string) {
+	licenseCache.Lock()
+	defer licenseCache.Unlock()
+	delete(licenseCache.licenses, id)
+}
+
+// acceptsLicenseKey reports whether the license may be used with the key: the license
+// is active and unexpired and the key is its current key or a retired key within its
+// grace period. Spent credits are left to ChargeCredits, which tells the caller what
+// the request costs.
+func acceptsLicenseKey(license *models.License, hash string, now time.Time) bool {
+	if !license.IsActive {
+		return false
+	}
+	if license.ExpiresAt != nil && now.After(*license.ExpiresAt) {
+		return false
+	}
+	if license.KeyHash == hash {
+		return true
+	}
+	for _, retired := range license.RetiredKeys {
+		if retired.KeyHash == hash && now.Before(retired.ExpiresAt) {
+			return true
+		}
+	}
+	return false
+}
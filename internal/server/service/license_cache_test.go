@@ -0,0 +1,65 @@
+package service
+
+import (
+	"main/pkg/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAcceptsLicenseKey(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	past, future := now.Add(-time.Hour), now.Add(time.Hour)
+	license := func() *models.License {
+		return &models.License{
+			IsActive: true,
+			KeyHash:  "current",
+			RetiredKeys: []models.RetiredKey{
+				{KeyHash: "retired", ExpiresAt: future},
+				{KeyHash: "expired", ExpiresAt: past},
+			},
+		}
+	}
+
+	assert.True(t, acceptsLicenseKey(license(), "current", now))
+	assert.True(t, acceptsLicenseKey(license(), "retired", now), "retired keys work during their grace period")
+	assert.False(t, acceptsLicenseKey(license(), "expired", now))
+	assert.False(t, acceptsLicenseKey(license(), "unknown", now))
+
+	inactive := license()
+	inactive.IsActive = false
+	assert.False(t, acceptsLicenseKey(inactive, "current", now))
+
+	expired := license()
+	expired.ExpiresAt = &past
+	assert.False(t, acceptsLicenseKey(expired, "current", now))
+
+	spent := license()
+	limit := int64(10)
+	spent.UsageCount, spent.UsageLimit = 10, &limit
+	assert.True(t, acceptsLicenseKey(spent, "current", now), "spent credits are refused when charged")
+}
+
+func TestLicenseCacheSweep(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	t.Cleanup(func() {
+		licenseCache.ids = make(map[string]cachedLicenseID)
+		licenseCache.licenses = make(map[string]cachedLicense)
+		licenseCache.lastSweep = time.Time{}
+	})
+
+	storeLicenseID("stale", "", now.Add(time.Second), now)
+	storeLicense("stale", &models.License{}, now.Add(time.Second), now)
+	storeLicenseID("fresh", "license", now.Add(time.Hour), now)
+
+	later := now.Add(2 * licenseCacheSweepInterval)
+	storeLicense("fresh", &models.License{}, later.Add(time.Hour), later)
+
+	assert.NotContains(t, licenseCache.ids, "stale")
+	assert.NotContains(t, licenseCache.licenses, "stale")
+	assert.Contains(t, licenseCache.ids, "fresh")
+
+	dropCachedLicense("fresh")
+	assert.NotContains(t, licenseCache.licenses, "fresh")
+}
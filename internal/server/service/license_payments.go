@@ -0,0 +1,216 @@
+package service
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"main/internal/database/mongo"
+	mongo2 "main/internal/server/repo/mongo"
+	"main/pkg/config"
+	"main/pkg/models"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	mongodriver "go.mongodb.org/mongo-driver/mongo"
+)
+
+var errPaymentAlreadyApplied = errors.New("payment signature already applied")
+
+var (
+	licensePayments       *mongo2.LicensePayments
+	licensePaymentService mongo2.LicensePaymentsImpl
+)
+
+func initLicensePayments() {
+	licensePayments = &mongo2.LicensePayments{
+		Collection: mongo.Database.Collection("license_payments"),
+	}
+	if err := licensePayments.EnsureIndexes(); err != nil {
+		log.Println("Error creating license payment indexes: " + err.Error())
+	}
+	licensePaymentService = mongo2.LicensePaymentsImpl(licensePayments)
+}
+
+// PurchaseLicense creates a payment request that issues a new license once paid.
+func PurchaseLicense(request models.PurchaseLicenseRequest) (*models.LicensePurchase, error) {
+	if request.Name == "" {
+		return nil, errors.New("name is required")
+	}
+
+	return createLicensePayment(request.Currency, request.CallbackURL, func(payment *models.PaymentRequest) {
+		payment.Purpose = models.PaymentPurposeLicensePurchase
+		payment.LicenseName = request.Name
+	})
+}
+
+// TopUpLicense creates a payment request that extends an existing license once paid.
+func TopUpLicense(request models.TopUpLicenseRequest) (*models.LicensePurchase, error) {
+	if licenseKeyService == nil {
+		return nil, fmt.Errorf("license service not initialized")
+	}
+	license, err := licenseKeyService.GetLicenseByKey(request.LicenseKey)
+	if err != nil {
+		return nil, errors.New("unknown license key")
+	}
+	if license.RevokedAt != nil {
+		return nil, ErrLicenseRevoked
+	}
+	if !license.IsActive {
+		return nil, errors.New("license is not active")
+	}
+
+	return createLicensePayment(request.Currency, request.CallbackURL, func(payment *models.PaymentRequest) {
+		payment.Purpose = models.PaymentPurposeLicenseTopUp
+		payment.LicenseID = &license.ID
+	})
+}
+
+// GetLicensePurchase returns the payment and, once it has been applied, the license.
+// The claim token handed out at creation is required because the reference is public on-chain.
+func GetLicensePurchase(reference, claimToken string) (*models.LicensePurchase, error) {
+	if paymentService == nil {
+		return nil, fmt.Errorf("payment service not initialized")
+	}
+	payment, err := paymentService.GetPayment(reference)
+	if err != nil {
+		return nil, err
+	}
+	if payment.Purpose == "" || subtle.ConstantTimeCompare([]byte(hashClaimToken(claimToken)), []byte(payment.ClaimTokenHash)) != 1 {
+		return nil, mongodriver.ErrNoDocuments
+	}
+
+	purchase := &models.LicensePurchase{Payment: payment}
+	if payment.Status == models.PaymentStatusPaid && payment.LicenseID != nil {
+		purchase.License, err = licenseKeyService.GetLicenseByID(*payment.LicenseID)
+		if err != nil {
+			return nil, err
+		}
+		// the key of a purchased license is generated on the first claim and never shown again,
+		// a concurrent claim that lost the race fails with models.ErrLicenseKeyIssued
+		if payment.Purpose == models.PaymentPurposeLicensePurchase && purchase.License.KeyHash == "" {
+			key, err := licenseKeyService.IssueLicenseKey(purchase.License.ID)
+			if err != nil {
+				return nil, err
+			}
+			purchase.License.Key = key
+		}
+	}
+
+	return purchase, nil
+}
+
+func createLicensePayment(currency, callbackURL string, configure func(payment *models.PaymentRequest)) (*models.LicensePurchase, error) {
+	if paymentService == nil || licensePaymentService == nil {
+		return nil, fmt.Errorf("payment service not initialized")
+	}
+	if config.Config.LicensePaymentRecipient == "" {
+		return nil, errors.New("license purchases are not enabled")
+	}
+
+	request := models.CreatePaymentRequest{
+		Recipient:   config.Config.LicensePaymentRecipient,
+		Label:       "Solana API license",
+		CallbackURL: callbackURL,
+	}
+	switch strings.ToUpper(currency) {
+	case "", "SOL":
+		request.Amount = config.Config.LicensePriceSol
+	case "USDC":
+		if config.Config.UsdcMint == "" {
+			return nil, errors.New("USDC payments are not enabled")
+		}
+		request.Amount = config.Config.LicensePriceUsdc
+		request.Mint = config.Config.UsdcMint
+	default:
+		return nil, fmt.Errorf("unsupported currency %q", currency)
+	}
+
+	payment, err := newPaymentRequest(request)
+	if err != nil {
+		return nil, err
+	}
+	configure(payment)
+
+	claimToken, err := newClaimToken()
+	if err != nil {
+		return nil, err
+	}
+	payment.ClaimTokenHash = hashClaimToken(claimToken)
+
+	if err = paymentService.CreatePayment(payment); err != nil {
+		return nil, err
+	}
+
+	return &models.LicensePurchase{Payment: payment, ClaimToken: claimToken}, nil
+}
+
+// applyLicensePayment records the signature before crediting, so a transaction that
+// references several payment requests can only ever be credited once. Crediting is
+// idempotent, a signature recorded for this payment but not marked applied, because the
+// process stopped or Mongo failed in between, is credited again on the next poll.
+func applyLicensePayment(payment *models.PaymentRequest, signature string) error {
+	record := &models.AppliedPayment{Signature: signature, Reference: payment.Reference}
+	switch payment.Purpose {
+	case models.PaymentPurposeLicensePurchase:
+		record.LicenseID = primitive.NewObjectID()
+	case models.PaymentPurposeLicenseTopUp:
+		record.LicenseID = *payment.LicenseID
+	}
+	record, err := licensePaymentService.RecordAppliedPayment(record)
+	if err != nil {
+		return err
+	}
+	if record.Reference != payment.Reference {
+		return errPaymentAlreadyApplied
+	}
+	if !record.LicenseID.IsZero() {
+		payment.LicenseID = &record.LicenseID
+	}
+	if record.AppliedAt != nil {
+		return nil
+	}
+
+	usage := config.Config.LicensePackageUsage
+	extension := time.Duration(config.Config.LicensePackageDays) * 24 * time.Hour
+
+	switch payment.Purpose {
+	case models.PaymentPurposeLicensePurchase:
+		expiry := time.Now().Add(extension)
+		_, err = licenseKeyService.CreateLicense(&models.License{
+			ID:         record.LicenseID,
+			Name:       payment.LicenseName,
+			ExpiresAt:  &expiry,
+			UsageLimit: &usage,
+			Scopes:     defaultLicenseScopes(),
+		}, false)
+		// the license was created by an earlier attempt
+		if err != nil && !mongodriver.IsDuplicateKeyError(err) {
+			return err
+		}
+	case models.PaymentPurposeLicenseTopUp:
+		if _, err = licenseKeyService.TopUpLicense(record.LicenseID, signature, usage, extension); err != nil {
+			return err
+		}
+		InvalidateLicense(record.LicenseID)
+	}
+
+	return licensePaymentService.MarkPaymentApplied(signature)
+}
+
+func newClaimToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+func hashClaimToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,74 @@
+package service
+
+import (
+	"errors"
+	"main/pkg/config"
+	"main/pkg/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type stubAppliedPayments struct {
+	models.LicensePaymentService
+	records map[string]*models.AppliedPayment
+}
+
+func (s *stubAppliedPayments) RecordAppliedPayment(payment *models.AppliedPayment) (*models.AppliedPayment, error) {
+	if stored, ok := s.records[payment.Signature]; ok {
+		copied := *stored
+		return &copied, nil
+	}
+	copied := *payment
+	s.records[payment.Signature] = &copied
+	return payment, nil
+}
+
+func (s *stubAppliedPayments) MarkPaymentApplied(signature string) error {
+	now := time.Now()
+	s.records[signature].AppliedAt = &now
+	return nil
+}
+
+type stubPurchasedLicenses struct {
+	models.LicenseKeyService
+	failNext bool
+	created  []primitive.ObjectID
+}
+
+func (s *stubPurchasedLicenses) CreateLicense(license *models.License, _ bool) (*models.License, error) {
+	if s.failNext {
+		s.failNext = false
+		return nil, errors.New("mongo unavailable")
+	}
+	s.created = append(s.created, license.ID)
+	return license, nil
+}
+
+func TestApplyLicensePayment_ResumesAfterFailure(t *testing.T) {
+	config.Config = &config.Structure{SolanaNetwork: "devnet", LicensePackageUsage: 100, LicensePackageDays: 30}
+	previousPayments, previousLicenses := licensePaymentService, licenseKeyService
+	applied := &stubAppliedPayments{records: make(map[string]*models.AppliedPayment)}
+	licenses := &stubPurchasedLicenses{failNext: true}
+	licensePaymentService, licenseKeyService = applied, licenses
+	t.Cleanup(func() {
+		licensePaymentService, licenseKeyService = previousPayments, previousLicenses
+	})
+
+	payment := &models.PaymentRequest{Reference: "ref", Purpose: models.PaymentPurposeLicensePurchase, LicenseName: "acme"}
+	require.Error(t, applyLicensePayment(payment, "sig"))
+	assert.Nil(t, applied.records["sig"].AppliedAt, "recorded but not applied")
+
+	require.NoError(t, applyLicensePayment(payment, "sig"), "the next poll finishes it")
+	require.Len(t, licenses.created, 1)
+	assert.Equal(t, licenses.created[0], *payment.LicenseID, "the license ID is kept across attempts")
+
+	require.NoError(t, applyLicensePayment(payment, "sig"), "an applied signature is not credited again")
+	assert.Len(t, licenses.created, 1)
+
+	other := &models.PaymentRequest{Reference: "other", Purpose: models.PaymentPurposeLicensePurchase}
+	assert.ErrorIs(t, applyLicensePayment(other, "sig"), errPaymentAlreadyApplied)
+}
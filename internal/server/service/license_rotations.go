@@ -0,0 +1,120 @@
+package service
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"log"
+	"main/internal/database/mongo"
+	mongo2 "main/internal/server/repo/mongo"
+	"main/pkg/config"
+	"main/pkg/models"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// maxKeyRotationGrace bounds how long a rotated out key may keep working.
+const maxKeyRotationGrace = 30 * 24 * time.Hour
+
+var (
+	// ErrRetiredKey is returned when a license rotates its key with a key that was already rotated out.
+	ErrRetiredKey = errors.New("rotate with the current key, not a retired one")
+	// ErrLicenseUnclaimed is returned when the key of a purchased license that was not claimed yet is rotated.
+	ErrLicenseUnclaimed = errors.New("license key has not been claimed yet")
+)
+
+var (
+	licenseRotations       *mongo2.LicenseKeyRotations
+	licenseRotationService mongo2.LicenseKeyRotationsImpl
+)
+
+func initLicenseRotations() {
+	licenseRotations = &mongo2.LicenseKeyRotations{
+		Collection: mongo.Database.Collection("license_key_rotations"),
+	}
+	licenseRotationService = mongo2.LicenseKeyRotationsImpl(licenseRotations)
+}
+
+// RotateLicenseKey issues a new key for the license. The old key keeps working for the
+// grace period and shares the license's usage counters and limits until then. A license
+// that has no key yet is an unclaimed purchase, whose first key only its buyer may claim.
+func RotateLicenseKey(id primitive.ObjectID, request models.RotateLicenseKeyRequest, rotatedBy string) (*models.KeyRotation, error) {
+	if licenseKeyService == nil || licenseRotationService == nil {
+		return nil, fmt.Errorf("license service not initialized")
+	}
+	grace, err := parseKeyRotationGrace(request.GracePeriod)
+	if err != nil {
+		return nil, err
+	}
+
+	license, err := licenseKeyService.GetLicenseByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if license.RevokedAt != nil {
+		return nil, ErrLicenseRevoked
+	}
+	if license.KeyHash == "" {
+		return nil, ErrLicenseUnclaimed
+	}
+
+	now := time.Now()
+	rotation := &models.KeyRotation{
+		LicenseID: id,
+		RotatedBy: rotatedBy,
+		RotatedAt: now,
+	}
+	var previous *models.License
+	rotation.Key, previous, err = licenseKeyService.RotateLicenseKey(id, grace)
+	if err != nil {
+		return nil, err
+	}
+	rotation.OldKeyPrefix = previous.KeyPrefix
+	if grace > 0 {
+		expiresAt := now.Add(grace)
+		rotation.OldKeyExpiresAt = &expiresAt
+	}
+	InvalidateLicense(id)
+	rotation.KeyPrefix = mongo2.LicenseKeyPrefixOf(rotation.Key)
+
+	if err = licenseRotationService.RecordRotation(rotation); err != nil {
+		log.Println("Error recording key rotation of license " + id.Hex() + ": " + err.Error())
+	}
+
+	return rotation, nil
+}
+
+// RotateOwnLicenseKey rotates the key of the calling license. Only the current key may
+// rotate, so a retired key that leaked cannot be used to obtain a fresh one.
+func RotateOwnLicenseKey(license *models.License, apiKey string, request models.RotateLicenseKeyRequest) (*models.KeyRotation, error) {
+	if subtle.ConstantTimeCompare([]byte(mongo2.HashLicenseKey(apiKey)), []byte(license.KeyHash)) != 1 {
+		return nil, ErrRetiredKey
+	}
+
+	return RotateLicenseKey(license.ID, request, models.KeyRotatedByLicense)
+}
+
+func ListKeyRotations(licenseID primitive.ObjectID) ([]models.KeyRotation, error) {
+	if licenseRotationService == nil {
+		return nil, fmt.Errorf("license service not initialized")
+	}
+
+	return licenseRotationService.ListRotations(licenseID)
+}
+
+func parseKeyRotationGrace(value string) (time.Duration, error) {
+	if value == "" {
+		return config.Config.LicenseKeyRotationGrace, nil
+	}
+
+	grace, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, errors.New("invalid grace_period: " + err.Error())
+	}
+	if grace < 0 || grace > maxKeyRotationGrace {
+		return 0, fmt.Errorf("grace_period must be between 0s and %s", maxKeyRotationGrace)
+	}
+
+	return grace, nil
+}
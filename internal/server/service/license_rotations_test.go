@@ -0,0 +1,45 @@
+package service
+
+import (
+	"main/pkg/config"
+	"main/pkg/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseKeyRotationGrace(t *testing.T) {
+	config.Config = &config.Structure{LicenseKeyRotationGrace: 24 * time.Hour}
+
+	tests := []struct {
+		value    string
+		expected time.Duration
+		wantErr  bool
+	}{
+		{"", 24 * time.Hour, false},
+		{"0s", 0, false},
+		{"48h", 48 * time.Hour, false},
+		{"720h", 720 * time.Hour, false},
+		{"721h", 0, true},
+		{"-1h", 0, true},
+		{"two days", 0, true},
+	}
+
+	for _, tt := range tests {
+		grace, err := parseKeyRotationGrace(tt.value)
+		if tt.wantErr {
+			assert.Error(t, err, tt.value)
+			continue
+		}
+		assert.NoError(t, err, tt.value)
+		assert.Equal(t, tt.expected, grace, tt.value)
+	}
+}
+
+func TestRotateOwnLicenseKeyRejectsRetiredKey(t *testing.T) {
+	license := &models.License{KeyHash: "0000"}
+
+	_, err := RotateOwnLicenseKey(license, "sk_retired", models.RotateLicenseKeyRequest{})
+	assert.ErrorIs(t, err, ErrRetiredKey)
+}
@@ -1,32 +1,99 @@
 package service
 
 import (
+	"errors"
 	"fmt"
+	"log"
 	"main/internal/database/mongo"
 	mongo2 "main/internal/server/repo/mongo"
+	"main/pkg/config"
 	"main/pkg/models"
+	"slices"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	mongodriver "go.mongodb.org/mongo-driver/mongo"
+)
+
+const (
+	defaultLicensePageSize = 20
+	maxLicensePageSize     = 100
 )
 
+var ErrLicenseRevoked = errors.New("license is revoked")
+
 var (
 	licenseKeys       *mongo2.LicenseKey
 	licenseKeyService mongo2.LicenseKeyImpl
 )
 
-func init() {
-	// Only initialize if MongoDB is available
-	if mongo.Database != nil {
-		licenseKeys = &mongo2.LicenseKey{
-			Collection: mongo.Database.Collection("license_keys"),
-		}
-		licenseKeyService = mongo2.LicenseKeyImpl(licenseKeys)
+func initLicenses() {
+	licenseKeys = &mongo2.LicenseKey{
+		Collection: mongo.Database.Collection("license_keys"),
+		Migrations: mongo.Database.Collection("license_migrations"),
 	}
+	if err := licenseKeys.EnsureIndexes(); err != nil {
+		log.Println("Error creating license indexes: " + err.Error())
+	}
+	if migrated, err := licenseKeys.MigratePlaintextKeys(); err != nil {
+		log.Println("Error hashing plaintext license keys: " + err.Error())
+	} else if migrated > 0 {
+		log.Printf("Hashed %d plaintext license keys", migrated)
+	}
+	// licenses from before scopes keep the full access they had
+	if granted, err := licenseKeys.GrantMissingScopes(defaultLicenseScopes()); err != nil {
+		log.Println("Error granting scopes to licenses without scopes: " + err.Error())
+	} else if granted > 0 {
+		log.Printf("Granted default scopes to %d licenses", granted)
+	}
+	if granted, err := licenseKeys.GrantScopesOnce("license-scopes", models.LicenseScopes); err != nil {
+		log.Println("Error granting license scopes: " + err.Error())
+	} else if granted > 0 {
+		log.Printf("Granted license scopes to %d licenses", granted)
+	}
+	licenseKeyService = mongo2.LicenseKeyImpl(licenseKeys)
+
+	cacheService.SubscribeLicenseInvalidations(dropCachedLicense)
+	go flushCredits()
 }
 
 func CreateLicense(request models.CreateLicenseRequest) (*models.License, error) {
 	if licenseKeyService == nil {
 		return nil, fmt.Errorf("license service not initialized")
 	}
-	result, err := licenseKeyService.CreateLicense(request.Name, &request.Expiry, &request.UsageLimit)
+	if strings.TrimSpace(request.Name) == "" {
+		return nil, errors.New("name is required")
+	}
+	if request.UsageLimit < 0 {
+		return nil, errors.New("usage_limit must not be negative")
+	}
+	scopes := request.Scopes
+	if len(scopes) == 0 {
+		scopes = defaultLicenseScopes()
+	}
+	if err := validateScopes(scopes); err != nil {
+		return nil, err
+	}
+	if err := validatePlan(request.Plan); err != nil {
+		return nil, err
+	}
+	// zero values mean no expiry and unlimited usage
+	var expiry *time.Time
+	if !request.Expiry.IsZero() {
+		expiry = &request.Expiry
+	}
+	var limit *int64
+	if request.UsageLimit > 0 {
+		limit = &request.UsageLimit
+	}
+	result, err := licenseKeyService.CreateLicense(&models.License{
+		Name:       request.Name,
+		ExpiresAt:  expiry,
+		UsageLimit: limit,
+		Scopes:     scopes,
+		Plan:       request.Plan,
+	}, true)
 	if err != nil {
 		return nil, err
 	}
@@ -34,25 +101,130 @@ func CreateLicense(request models.CreateLicenseRequest) (*models.License, error)
 	return result, nil
 }
 
+// ValidateLicense returns the license of the key if the key is accepted. Licenses are
+// served from the license cache, so most requests do not reach Mongo.
 func ValidateLicense(key string) (*models.License, error) {
 	if licenseKeyService == nil {
 		return nil, fmt.Errorf("license service not initialized")
 	}
-	result, err := licenseKeyService.ValidateLicense(key)
+	license, err := lookupLicense(key)
+	if err != nil {
+		return nil, err
+	}
+	if !acceptsLicenseKey(license, mongo2.HashLicenseKey(key), time.Now()) {
+		return nil, mongodriver.ErrNoDocuments
+	}
+
+	// the cached license is shared between requests
+	result := *license
+	return &result, nil
+}
+
+func GetLicense(id primitive.ObjectID) (*models.License, error) {
+	if licenseKeyService == nil {
+		return nil, fmt.Errorf("license service not initialized")
+	}
+
+	return licenseKeyService.GetLicenseByID(id)
+}
+
+// ListLicenses returns one page of the licenses matching the filter, newest first.
+func ListLicenses(filter models.LicenseFilter) (*models.LicenseList, error) {
+	if licenseKeyService == nil {
+		return nil, fmt.Errorf("license service not initialized")
+	}
+	if filter.Page < 0 || filter.Limit < 0 {
+		return nil, errors.New("page and limit must not be negative")
+	}
+	if filter.Page == 0 {
+		filter.Page = 1
+	}
+	if filter.Limit == 0 {
+		filter.Limit = defaultLicensePageSize
+	}
+	filter.Limit = min(filter.Limit, maxLicensePageSize)
+
+	licenses, total, err := licenseKeyService.ListLicenses(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.LicenseList{
+		Licenses: licenses,
+		Total:    total,
+		Page:     filter.Page,
+		Limit:    filter.Limit,
+	}, nil
+}
+
+func UpdateLicense(id primitive.ObjectID, request models.UpdateLicenseRequest) (*models.License, error) {
+	if licenseKeyService == nil {
+		return nil, fmt.Errorf("license service not initialized")
+	}
+	if err := validateLicenseUpdate(request); err != nil {
+		return nil, err
+	}
+
+	license, err := licenseKeyService.GetLicenseByID(id)
 	if err != nil {
 		return nil, err
 	}
+	if license.RevokedAt != nil && request.IsActive != nil && *request.IsActive {
+		return nil, ErrLicenseRevoked
+	}
+
+	result, err := licenseKeyService.UpdateLicense(id, request)
+	if err != nil {
+		return nil, err
+	}
+	InvalidateLicense(id)
 
 	return result, nil
 }
 
-func IncrementLicenseUsage(key string) error {
+func RevokeLicense(id primitive.ObjectID) (*models.License, error) {
 	if licenseKeyService == nil {
-		return fmt.Errorf("license service not initialized")
+		return nil, fmt.Errorf("license service not initialized")
 	}
-	err := licenseKeyService.IncrementUsage(key)
+
+	result, err := licenseKeyService.RevokeLicense(id)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	InvalidateLicense(id)
+
+	return result, nil
+}
+
+func validateLicenseUpdate(request models.UpdateLicenseRequest) error {
+	if request.Name != nil && strings.TrimSpace(*request.Name) == "" {
+		return errors.New("name must not be empty")
+	}
+	if request.UsageLimit.Value != nil && *request.UsageLimit.Value < 0 {
+		return errors.New("usage_limit must not be negative")
+	}
+	if request.Scopes != nil {
+		if err := validateScopes(*request.Scopes); err != nil {
+			return err
+		}
+	}
+	if request.Plan != nil {
+		return validatePlan(*request.Plan)
+	}
+
+	return nil
+}
+
+// defaultLicenseScopes grants every endpoint on the network this instance serves.
+func defaultLicenseScopes() []string {
+	return append(slices.Clone(models.ApiScopes), models.NetworkScope(config.Config.SolanaNetwork))
+}
+
+func validateScopes(scopes []string) error {
+	for _, scope := range scopes {
+		if !models.IsKnownScope(scope) {
+			return fmt.Errorf("unknown scope %q", scope)
+		}
 	}
 
 	return nil
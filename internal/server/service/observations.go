@@ -0,0 +1,55 @@
+package service
+
+import (
+	"main/pkg/models"
+	"sync"
+	"time"
+)
+
+type balanceObserver func(observation models.BalanceObservation)
+
+type lastObservation struct {
+	lamports uint64
+	slot     uint64
+}
+
+var (
+	lastBalances      = make(map[string]lastObservation)
+	lastBalancesMutex = sync.Mutex{}
+	balanceObservers  []balanceObserver
+)
+
+// addBalanceObserver registers observer for every balance observation. It is only
+// called from the init functions, before any observation can happen.
+func addBalanceObserver(observer balanceObserver) {
+	balanceObservers = append(balanceObservers, observer)
+}
+
+// ObserveBalance hands a freshly read balance to the registered observers. Reads from
+// an older slot than the last one seen for the wallet are dropped, as they would
+// report a change that already happened.
+func ObserveBalance(wallet string, lamports, slot uint64) {
+	lastBalancesMutex.Lock()
+	last, seen := lastBalances[wallet]
+	if seen && slot < last.slot {
+		lastBalancesMutex.Unlock()
+		return
+	}
+	lastBalances[wallet] = lastObservation{lamports: lamports, slot: slot}
+	lastBalancesMutex.Unlock()
+
+	observation := models.BalanceObservation{
+		Wallet:     wallet,
+		Lamports:   lamports,
+		Slot:       slot,
+		ObservedAt: time.Now(),
+	}
+	if seen {
+		previous := last.lamports
+		observation.Previous = &previous
+	}
+
+	for _, observer := range balanceObservers {
+		go observer(observation)
+	}
+}
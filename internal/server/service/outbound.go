@@ -0,0 +1,93 @@
+package service
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+)
+
+var errInternalAddress = errors.New("address is not publicly routable")
+
+// nonPublicPrefixes are the ranges the net.IP helpers do not already cover: "this
+// network", carrier-grade NAT, IETF protocol assignments and benchmarking.
+var nonPublicPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("192.0.0.0/24"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+}
+
+// newOutboundClient returns a client for URLs chosen by API callers or written on-chain.
+// Its dialer refuses every address that is not publicly routable. The check runs on the
+// resolved address right before connecting, so neither redirects nor DNS rebinding get
+// around it, and no proxy is used that would connect on the client's behalf.
+func newOutboundClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: func(_, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || !isPublicIP(ip) {
+				return errInternalAddress
+			}
+			return nil
+		},
+	}
+
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			Proxy:               nil,
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: timeout,
+			MaxIdleConnsPerHost: 4,
+		},
+	}
+}
+
+func isPublicIP(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() || ip.IsMulticast() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() {
+		return false
+	}
+	addr, ok := netip.AddrFromSlice(ip)
+	if !ok {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, prefix := range nonPublicPrefixes {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
+
+// validateOutboundUrl checks a callback or webhook URL when it is registered. Hosts that
+// resolve to internal addresses later are still refused by newOutboundClient.
+func validateOutboundUrl(rawUrl string) error {
+	parsed, err := url.Parse(rawUrl)
+	if err != nil || parsed.Scheme != "https" || parsed.Hostname() == "" {
+		return errors.New("url must be an absolute https URL")
+	}
+	if parsed.User != nil {
+		return errors.New("url must not contain credentials")
+	}
+
+	host := strings.ToLower(parsed.Hostname())
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") || strings.HasSuffix(host, ".internal") {
+		return errors.New("url must point to a public host")
+	}
+	if ip := net.ParseIP(host); ip != nil && !isPublicIP(ip) {
+		return errors.New("url must point to a public host")
+	}
+
+	return nil
+}
@@ -0,0 +1,192 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"main/internal/database/mongo"
+	mongo2 "main/internal/server/repo/mongo"
+	"main/pkg/config"
+	"main/pkg/models"
+	"main/pkg/solana"
+	"math/big"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	mongodriver "go.mongodb.org/mongo-driver/mongo"
+)
+
+const defaultPaymentExpiry = 15 * time.Minute
+
+var (
+	payments       *mongo2.Payments
+	paymentService mongo2.PaymentsImpl
+)
+
+func initPayments() {
+	payments = &mongo2.Payments{
+		Collection: mongo.Database.Collection("payments"),
+	}
+	paymentService = mongo2.PaymentsImpl(payments)
+
+	go watchPayments()
+}
+
+func CreatePaymentRequest(licenseID primitive.ObjectID, request models.CreatePaymentRequest) (*models.PaymentRequest, error) {
+	if paymentService == nil {
+		return nil, fmt.Errorf("payment service not initialized")
+	}
+
+	payment, err := newPaymentRequest(request)
+	if err != nil {
+		return nil, err
+	}
+	payment.OwnerID = licenseID
+	if err = paymentService.CreatePayment(payment); err != nil {
+		return nil, err
+	}
+
+	return payment, nil
+}
+
+func newPaymentRequest(request models.CreatePaymentRequest) (*models.PaymentRequest, error) {
+	if !solana.IsValidAddress(request.Recipient) {
+		return nil, errors.New("invalid recipient")
+	}
+	if request.CallbackURL != "" {
+		if err := validateOutboundUrl(request.CallbackURL); err != nil {
+			return nil, errors.New("invalid callback_url: " + err.Error())
+		}
+	}
+
+	decimals := uint8(9)
+	if request.Mint != "" {
+		mintDecimals, err := solana.NewSolClient().GetMintDecimals(request.Mint)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load mint: %w", err)
+		}
+		decimals = mintDecimals
+	}
+	amount, err := solana.ParseAmount(request.Amount, decimals)
+	if err != nil {
+		return nil, err
+	}
+
+	expiry := defaultPaymentExpiry
+	if request.ExpiresInSeconds > 0 {
+		expiry = time.Duration(request.ExpiresInSeconds) * time.Second
+	}
+
+	// the URL and the payer get the parsed amount, never the raw input
+	normalized := solana.FormatAmount(new(big.Int).SetUint64(amount), decimals)
+	if strings.Contains(normalized, ".") {
+		normalized = strings.TrimRight(strings.TrimRight(normalized, "0"), ".")
+	}
+
+	reference := solana.NewReference()
+	return &models.PaymentRequest{
+		Reference:       reference,
+		URL:             solana.PaymentURL(request.Recipient, normalized, request.Mint, reference, request.Label, request.Message, request.Memo),
+		Recipient:       request.Recipient,
+		Amount:          normalized,
+		AmountBaseUnits: amount,
+		Mint:            request.Mint,
+		Label:           request.Label,
+		Message:         request.Message,
+		Memo:            request.Memo,
+		CallbackURL:     request.CallbackURL,
+		Status:          models.PaymentStatusPending,
+		ExpiresAt:       time.Now().Add(expiry),
+	}, nil
+}
+
+// GetPaymentRequest returns the payment request if it was created by the license. The
+// reference is public on-chain, so it alone does not give access.
+func GetPaymentRequest(licenseID primitive.ObjectID, reference string) (*models.PaymentRequest, error) {
+	if paymentService == nil {
+		return nil, fmt.Errorf("payment service not initialized")
+	}
+	result, err := paymentService.GetPayment(reference)
+	if err != nil {
+		return nil, err
+	}
+	if result.OwnerID != licenseID {
+		return nil, mongodriver.ErrNoDocuments
+	}
+
+	return result, nil
+}
+
+func watchPayments() {
+	ticker := time.NewTicker(config.Config.PaymentPollInterval)
+	defer ticker.Stop()
+
+	client := solana.NewSolClient()
+	for range ticker.C {
+		pending, err := paymentService.ListPendingPayments()
+		if err != nil {
+			log.Println("Error listing pending payments:", err)
+			continue
+		}
+
+		for i := range pending {
+			checkPayment(client, &pending[i])
+		}
+	}
+}
+
+// checkPayment looks for a transaction carrying the payment reference that pays the
+// expected recipient at least the requested amount, and expires the request otherwise.
+func checkPayment(client *solana.SolClient, payment *models.PaymentRequest) {
+	signatures, err := client.GetReferenceSignatures(payment.Reference)
+	if err != nil {
+		log.Println("Error fetching reference signatures:", err)
+		return
+	}
+
+	for _, signature := range signatures {
+		received, slot, err := client.GetReceivedAmount(signature, payment.Recipient, payment.Mint)
+		if err != nil {
+			log.Println("Error validating payment transaction:", err)
+			continue
+		}
+		if received < payment.AmountBaseUnits {
+			continue
+		}
+		if payment.Purpose != "" {
+			err = applyLicensePayment(payment, signature)
+			if errors.Is(err, errPaymentAlreadyApplied) {
+				continue
+			}
+			if err != nil {
+				// the payment stays pending and is applied again on the next poll
+				log.Println("Error applying license payment "+signature+":", err)
+				return
+			}
+		}
+
+		now := time.Now()
+		payment.Status = models.PaymentStatusPaid
+		payment.Signature = signature
+		payment.Slot = slot
+		payment.PaidAt = &now
+		savePayment(payment)
+		return
+	}
+
+	if time.Now().After(payment.ExpiresAt) {
+		payment.Status = models.PaymentStatusExpired
+		savePayment(payment)
+	}
+}
+
+func savePayment(payment *models.PaymentRequest) {
+	if err := paymentService.UpdatePayment(payment); err != nil {
+		log.Println("Error updating payment:", err)
+		return
+	}
+	if payment.CallbackURL != "" {
+		go sendCallback(payment.CallbackURL, *payment)
+	}
+}
@@ -0,0 +1,186 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"main/internal/database/mongo"
+	mongo2 "main/internal/server/repo/mongo"
+	"main/pkg/config"
+	"main/pkg/models"
+	"regexp"
+	"sync"
+	"time"
+)
+
+// planCacheTTL is how long a plan is served from memory, plan changes reach every
+// instance within it.
+const planCacheTTL = 30 * time.Second
+
+var (
+	ErrDefaultPlan = errors.New("the default plan cannot be deleted")
+
+	planIDPattern = regexp.MustCompile(`^[a-z0-9_-]{1,32}$`)
+
+	plans       *mongo2.Plans
+	planService mongo2.PlansImpl
+
+	planCache      = make(map[string]cachedPlan)
+	planCacheMutex sync.Mutex
+)
+
+type cachedPlan struct {
+	plan     *models.Plan
+	loadedAt time.Time
+}
+
+func initPlans() {
+	plans = &mongo2.Plans{
+		Collection: mongo.Database.Collection("plans"),
+	}
+	planService = mongo2.PlansImpl(plans)
+
+	if err := planService.CreatePlanIfMissing(builtinDefaultPlan()); err != nil {
+		log.Println("Error creating the default plan: " + err.Error())
+	}
+	// plans stored before max_watched_wallets would otherwise be unlimited
+	if updated, err := planService.SetMissingWatchedWalletLimit(builtinDefaultPlan().MaxWatchedWallets); err != nil {
+		log.Println("Error setting the watched wallet limit of stored plans: " + err.Error())
+	} else if updated > 0 {
+		log.Printf("Set the watched wallet limit of %d plans", updated)
+	}
+}
+
+// builtinDefaultPlan is stored as DEFAULT_PLAN when no such plan exists, and used when
+// plans cannot be loaded at all.
+func builtinDefaultPlan() *models.Plan {
+	return &models.Plan{
+		ID:                   config.Config.DefaultPlan,
+		Name:                 "Default",
+		RequestsPerSecond:    10,
+		RequestsPerMinute:    300,
+		RequestsPerDay:       100000,
+		Burst:                20,
+		MaxWalletsPerRequest: 100,
+		MaxConcurrency:       10,
+		MaxWatchedWallets:    1000,
+	}
+}
+
+// GetLicensePlan returns the plan of the license. Licenses without a plan, or whose plan
+// was deleted, are on DEFAULT_PLAN.
+func GetLicensePlan(license *models.License) *models.Plan {
+	if license.Plan != "" {
+		if plan, err := getPlan(license.Plan); err == nil {
+			return plan
+		}
+	}
+
+	plan, err := getPlan(config.Config.DefaultPlan)
+	if err != nil {
+		return builtinDefaultPlan()
+	}
+	return plan
+}
+
+func getPlan(id string) (*models.Plan, error) {
+	planCacheMutex.Lock()
+	cached, ok := planCache[id]
+	planCacheMutex.Unlock()
+	if ok && time.Since(cached.loadedAt) < planCacheTTL {
+		return cached.plan, nil
+	}
+
+	if planService == nil {
+		return nil, fmt.Errorf("plan service not initialized")
+	}
+	plan, err := planService.GetPlan(id)
+	if err != nil {
+		return nil, err
+	}
+
+	planCacheMutex.Lock()
+	planCache[id] = cachedPlan{plan: plan, loadedAt: time.Now()}
+	planCacheMutex.Unlock()
+
+	return plan, nil
+}
+
+func ListPlans() ([]models.Plan, error) {
+	if planService == nil {
+		return nil, fmt.Errorf("plan service not initialized")
+	}
+
+	return planService.ListPlans()
+}
+
+// SavePlan creates or replaces the plan with the given ID.
+func SavePlan(id string, plan models.Plan) (*models.Plan, error) {
+	if planService == nil {
+		return nil, fmt.Errorf("plan service not initialized")
+	}
+	plan.ID = id
+	if err := validatePlanLimits(&plan); err != nil {
+		return nil, err
+	}
+
+	if err := planService.SavePlan(&plan); err != nil {
+		return nil, err
+	}
+	forgetPlan(id)
+
+	return &plan, nil
+}
+
+// DeletePlan removes a plan, licenses on it fall back to DEFAULT_PLAN.
+func DeletePlan(id string) error {
+	if planService == nil {
+		return fmt.Errorf("plan service not initialized")
+	}
+	if id == config.Config.DefaultPlan {
+		return ErrDefaultPlan
+	}
+
+	if err := planService.DeletePlan(id); err != nil {
+		return err
+	}
+	forgetPlan(id)
+
+	return nil
+}
+
+func forgetPlan(id string) {
+	planCacheMutex.Lock()
+	delete(planCache, id)
+	planCacheMutex.Unlock()
+}
+
+// validatePlan checks that a license can be put on the plan, empty means DEFAULT_PLAN.
+func validatePlan(id string) error {
+	if id == "" {
+		return nil
+	}
+	if _, err := getPlan(id); err != nil {
+		return fmt.Errorf("unknown plan %q", id)
+	}
+
+	return nil
+}
+
+func validatePlanLimits(plan *models.Plan) error {
+	if !planIDPattern.MatchString(plan.ID) {
+		return errors.New("plan id must be 1 to 32 lowercase letters, digits, '-' or '_'")
+	}
+	if plan.Name == "" {
+		return errors.New("name is required")
+	}
+	if plan.RequestsPerSecond < 0 || plan.RequestsPerMinute < 0 || plan.RequestsPerDay < 0 ||
+		plan.Burst < 0 || plan.MaxWalletsPerRequest < 0 || plan.MaxConcurrency < 0 || plan.MaxWatchedWallets < 0 {
+		return errors.New("limits must not be negative")
+	}
+	if plan.Burst != 0 && plan.Burst < plan.RequestsPerSecond {
+		return errors.New("burst must not be below requests_per_second")
+	}
+
+	return nil
+}
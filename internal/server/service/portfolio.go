@@ -0,0 +1,130 @@
+package service
+
+import (
+	"errors"
+	"main/pkg/config"
+	"main/pkg/models"
+	"main/pkg/prices"
+	"main/pkg/solana"
+	"math/big"
+	"time"
+)
+
+// usdDecimals is the precision of the USD values in a portfolio.
+const usdDecimals = 2
+
+var priceSource prices.Source
+
+func initPrices() {
+	priceSource = &prices.PythSource{
+		Client: solana.NewSolClient(),
+		Feeds:  config.Config.PythPriceAccounts,
+	}
+}
+
+// GetPortfolio values the SOL, staked SOL and token balances of the wallet with prices
+// from the configured price source.
+func GetPortfolio(wallet string) (*models.Portfolio, error) {
+	if !solana.IsValidAddress(wallet) {
+		return nil, errors.New("invalid wallet address")
+	}
+
+	client := solana.NewSolClient()
+	lamports, _, err := client.GetLamports(wallet)
+	if err != nil {
+		return nil, err
+	}
+	staked, err := client.GetStakedLamports(wallet)
+	if err != nil {
+		return nil, err
+	}
+	holdings, err := client.GetTokenHoldings(wallet)
+	if err != nil {
+		return nil, err
+	}
+
+	totals := newTokenTotals()
+	for _, holding := range holdings {
+		totals.add(holding)
+	}
+	tokenList := totals.list()
+	annotateTokenTotals(tokenList)
+	assets := []models.PortfolioAsset{
+		portfolioAsset(models.PortfolioAssetSol, solana.WrappedSolMint, new(big.Int).SetUint64(lamports), 9),
+	}
+	if staked > 0 {
+		assets = append(assets, portfolioAsset(models.PortfolioAssetStaked, solana.WrappedSolMint, new(big.Int).SetUint64(staked), 9))
+	}
+	for _, token := range tokenList {
+		asset := portfolioAsset(models.PortfolioAssetToken, token.Mint, totals.amounts[token.Mint], token.Decimals)
+		asset.Symbol = token.Symbol
+		assets = append(assets, asset)
+	}
+
+	mints := make([]string, 0, len(assets))
+	for _, asset := range assets {
+		mints = append(mints, asset.Mint)
+	}
+	quotes, slot, err := priceSource.GetPrices(mints)
+	if err != nil {
+		return nil, err
+	}
+
+	return valuePortfolio(wallet, assets, quotes, slot, time.Now()), nil
+}
+
+func portfolioAsset(asset, mint string, amount *big.Int, decimals uint8) models.PortfolioAsset {
+	return models.PortfolioAsset{
+		Asset:    asset,
+		Mint:     mint,
+		Amount:   amount.String(),
+		Decimals: decimals,
+		UiAmount: solana.FormatAmount(amount, decimals),
+	}
+}
+
+// valuePortfolio prices every asset and sums the values with exact rational arithmetic.
+// Assets without a price, or whose price fails the staleness or confidence check, are
+// listed with a price_error and left out of the total.
+func valuePortfolio(wallet string, assets []models.PortfolioAsset, quotes map[string]prices.Price, slot uint64, now time.Time) *models.Portfolio {
+	total := new(big.Rat)
+	complete := true
+
+	for i := range assets {
+		asset := &assets[i]
+		quote, ok := quotes[asset.Mint]
+		if !ok {
+			asset.PriceError = "no price feed"
+			complete = false
+			continue
+		}
+		if err := quote.Validate(now, config.Config.PriceMaxAge, config.Config.PriceMaxConfidence); err != nil {
+			asset.PriceError = err.Error()
+			complete = false
+			continue
+		}
+
+		amount, _ := new(big.Int).SetString(asset.Amount, 10)
+		units := new(big.Rat).SetFrac(amount, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(asset.Decimals)), nil))
+		value := new(big.Rat).Mul(units, quote.Value())
+		total.Add(total, value)
+
+		precision := 0
+		if quote.Expo < 0 {
+			precision = int(-quote.Expo)
+		}
+		published := quote.PublishTime
+		asset.PriceUsd = quote.Value().FloatString(precision)
+		asset.ConfidenceUsd = quote.Confidence().FloatString(precision)
+		asset.PricePublishedAt = &published
+		asset.ValueUsd = value.FloatString(usdDecimals)
+	}
+
+	return &models.Portfolio{
+		Wallet:    wallet,
+		Assets:    assets,
+		TotalUsd:  total.FloatString(usdDecimals),
+		Complete:  complete,
+		PriceSlot: slot,
+	}
+}
@@ -0,0 +1,50 @@
+package service
+
+import (
+	"main/pkg/config"
+	"main/pkg/models"
+	"main/pkg/prices"
+	"main/pkg/solana"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValuePortfolio(t *testing.T) {
+	config.Config = &config.Structure{PriceMaxAge: time.Minute, PriceMaxConfidence: 0.02}
+	now := time.Now()
+
+	source := &prices.FixtureSource{
+		Slot: 250_000_000,
+		Prices: map[string]prices.Price{
+			solana.WrappedSolMint: {Price: 15_000_000_000, Conf: 5_000_000, Expo: -8, PublishTime: now.Add(-5 * time.Second)},
+			"usdc":                {Price: 100_000_000, Conf: 10_000, Expo: -8, PublishTime: now.Add(-5 * time.Second)},
+			"stale":               {Price: 100_000_000, Conf: 10_000, Expo: -8, PublishTime: now.Add(-time.Hour)},
+		},
+	}
+	assets := []models.PortfolioAsset{
+		portfolioAsset(models.PortfolioAssetSol, solana.WrappedSolMint, big.NewInt(1_500_000_000), 9),
+		portfolioAsset(models.PortfolioAssetStaked, solana.WrappedSolMint, big.NewInt(10_000_000_000), 9),
+		portfolioAsset(models.PortfolioAssetToken, "usdc", big.NewInt(2_500_000), 6),
+		portfolioAsset(models.PortfolioAssetToken, "stale", big.NewInt(1), 0),
+		portfolioAsset(models.PortfolioAssetToken, "unknown", big.NewInt(1), 0),
+	}
+
+	quotes, slot, err := source.GetPrices([]string{solana.WrappedSolMint, "usdc", "stale", "unknown"})
+	require.NoError(t, err)
+	portfolio := valuePortfolio("wallet", assets, quotes, slot, now)
+
+	assert.Equal(t, "225.00", portfolio.Assets[0].ValueUsd)
+	assert.Equal(t, "150.00000000", portfolio.Assets[0].PriceUsd)
+	assert.Equal(t, "1500.00", portfolio.Assets[1].ValueUsd)
+	assert.Equal(t, "2.50", portfolio.Assets[2].ValueUsd)
+	assert.Contains(t, portfolio.Assets[3].PriceError, "stale")
+	assert.Equal(t, "no price feed", portfolio.Assets[4].PriceError)
+
+	assert.Equal(t, "1727.50", portfolio.TotalUsd)
+	assert.False(t, portfolio.Complete)
+	assert.Equal(t, uint64(250_000_000), portfolio.PriceSlot)
+}
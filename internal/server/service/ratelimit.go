@@ -0,0 +1,178 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"main/pkg/config"
+	"main/pkg/models"
+	"main/pkg/ratelimit"
+	"time"
+)
+
+// concurrencyTTL bounds how long a request counts as in flight should its release be lost.
+const concurrencyTTL = time.Minute
+
+var rateLimiter *ratelimit.Limiter
+
+func initRateLimiter() {
+	rateLimiter = &ratelimit.Limiter{
+		Store:    ratelimit.StoreFunc(cacheService.AllowRates),
+		Fallback: ratelimit.NewMemoryStore(),
+		Policy:   ratelimit.Policy(config.Config.RateLimitFallback),
+	}
+}
+
+type namedLimit struct {
+	name  string
+	limit ratelimit.Limit
+}
+
+// planLimits returns the limits a license on the plan is held to. Requests per second
+// refill a token bucket of Burst tokens, requests per minute are counted in a sliding
+// window log and the daily quota is a token bucket that refills over the day, which
+// keeps the state of large quotas small.
+func planLimits(plan *models.Plan) []namedLimit {
+	var limits []namedLimit
+	if plan.RequestsPerSecond > 0 {
+		limits = append(limits, namedLimit{"second", ratelimit.Limit{
+			Algorithm: ratelimit.TokenBucket,
+			Limit:     plan.RequestsPerSecond,
+			Window:    time.Second,
+			Burst:     max(plan.Burst, plan.RequestsPerSecond),
+		}})
+	}
+	if plan.RequestsPerMinute > 0 {
+		limits = append(limits, namedLimit{"minute", ratelimit.Limit{
+			Algorithm: ratelimit.SlidingWindow,
+			Limit:     plan.RequestsPerMinute,
+			Window:    time.Minute,
+		}})
+	}
+	if plan.RequestsPerDay > 0 {
+		limits = append(limits, namedLimit{"day", ratelimit.Limit{
+			Algorithm: ratelimit.TokenBucket,
+			Limit:     plan.RequestsPerDay,
+			Window:    24 * time.Hour,
+		}})
+	}
+	return limits
+}
+
+// CheckLicenseRateLimit counts the request against the license's plan limits. Next to
+// the deciding result it returns what is left of every limit.
+func CheckLicenseRateLimit(license *models.License, plan *models.Plan) (ratelimit.Result, []models.RateLimitBudget, error) {
+	limits := planLimits(plan)
+	decision, results, err := checkRateLimit("license:"+license.ID.Hex(), limits)
+	if err != nil {
+		return decision, nil, err
+	}
+
+	budgets := make([]models.RateLimitBudget, len(results))
+	for i, result := range results {
+		budgets[i] = models.RateLimitBudget{
+			Window:    limits[i].name,
+			Limit:     result.Limit,
+			Remaining: result.Remaining,
+			Reset:     int64((result.Reset + time.Second - 1) / time.Second),
+		}
+	}
+	return decision, budgets, nil
+}
+
+// CheckIpRateLimit counts the request against the per-IP limit that applies to every
+// caller whatever their plan.
+func CheckIpRateLimit(ip string) (ratelimit.Result, error) {
+	var limits []namedLimit
+	if config.Config.IpRequestsPerMinute > 0 {
+		limits = append(limits, namedLimit{"minute", ratelimit.Limit{
+			Algorithm: ratelimit.SlidingWindow,
+			Limit:     int64(config.Config.IpRequestsPerMinute),
+			Window:    time.Minute,
+		}})
+	}
+	decision, _, err := checkRateLimit("ip:"+ip, limits)
+	return decision, err
+}
+
+// CheckRouteRateLimit counts the request against a limit of its own for one route and license.
+func CheckRouteRateLimit(route string, license *models.License, limit ratelimit.Limit) (ratelimit.Result, error) {
+	decision, _, err := checkRateLimit("route:"+route+":license:"+license.ID.Hex(), []namedLimit{{"route", limit}})
+	return decision, err
+}
+
+// checkRateLimit applies every limit and returns the deciding result along with the
+// result of each limit. A request rejected by one limit is not counted against the others.
+func checkRateLimit(subject string, limits []namedLimit) (ratelimit.Result, []ratelimit.Result, error) {
+	if len(limits) == 0 {
+		return ratelimit.Result{Allowed: true}, nil, nil
+	}
+	if rateLimiter == nil {
+		return ratelimit.Result{}, nil, fmt.Errorf("rate limiter not initialized")
+	}
+
+	keys := make([]string, len(limits))
+	rates := make([]ratelimit.Limit, len(limits))
+	for i, limit := range limits {
+		keys[i] = subject + ":" + limit.name
+		rates[i] = limit.limit
+	}
+	results, err := rateLimiter.AllowAll(keys, rates)
+	if err != nil {
+		return ratelimit.Result{}, nil, err
+	}
+
+	return decidingResult(results), results, nil
+}
+
+// decidingResult reports the limit that decides the request: the rejecting limit that
+// frees up last, or otherwise the one with the fewest requests left.
+func decidingResult(results []ratelimit.Result) ratelimit.Result {
+	decision := results[0]
+	for _, result := range results[1:] {
+		switch {
+		case result.Allowed != decision.Allowed:
+			if !result.Allowed {
+				decision = result
+			}
+		case !result.Allowed:
+			if result.RetryAfter > decision.RetryAfter {
+				decision = result
+			}
+		case result.Remaining < decision.Remaining:
+			decision = result
+		}
+	}
+	return decision
+}
+
+// AcquireConcurrencySlot counts a request of the license as in flight. It reports false
+// when the plan's concurrency is used up, otherwise the returned function has to be
+// called once the request is done. While Redis is unreachable concurrency is only
+// limited under the closed fallback policy, which rejects the request.
+func AcquireConcurrencySlot(license *models.License, plan *models.Plan) (func(), bool, error) {
+	if plan.MaxConcurrency <= 0 {
+		return func() {}, true, nil
+	}
+
+	id := license.ID.Hex()
+	inFlight, err := cacheService.AcquireConcurrency(id, concurrencyTTL)
+	if err != nil {
+		if ratelimit.Policy(config.Config.RateLimitFallback) == ratelimit.PolicyClosed {
+			return nil, false, errors.Join(ratelimit.ErrUnavailable, err)
+		}
+		return func() {}, true, nil
+	}
+
+	release := func() {
+		if err := cacheService.ReleaseConcurrency(id); err != nil {
+			log.Println("Error releasing concurrency slot: " + err.Error())
+		}
+	}
+	if inFlight > plan.MaxConcurrency {
+		release()
+		return nil, false, nil
+	}
+
+	return release, true, nil
+}
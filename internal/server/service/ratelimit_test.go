@@ -0,0 +1,48 @@
+package service
+
+import (
+	"main/pkg/models"
+	"main/pkg/ratelimit"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlanLimits(t *testing.T) {
+	limits := planLimits(&models.Plan{RequestsPerSecond: 5, Burst: 20, RequestsPerMinute: 100, RequestsPerDay: 1000})
+
+	require.Len(t, limits, 3)
+	assert.Equal(t, namedLimit{"second", ratelimit.Limit{Algorithm: ratelimit.TokenBucket, Limit: 5, Window: time.Second, Burst: 20}}, limits[0])
+	assert.Equal(t, namedLimit{"minute", ratelimit.Limit{Algorithm: ratelimit.SlidingWindow, Limit: 100, Window: time.Minute}}, limits[1])
+	assert.Equal(t, namedLimit{"day", ratelimit.Limit{Algorithm: ratelimit.TokenBucket, Limit: 1000, Window: 24 * time.Hour}}, limits[2])
+
+	limits = planLimits(&models.Plan{RequestsPerSecond: 5, Burst: 2})
+	require.Len(t, limits, 1)
+	assert.Equal(t, int64(5), limits[0].limit.Burst, "the burst is at least one second of requests")
+
+	assert.Empty(t, planLimits(&models.Plan{}), "zero limits are unlimited")
+}
+
+func TestDecidingResult(t *testing.T) {
+	second := ratelimit.Result{Allowed: true, Limit: 10, Remaining: 8, Reset: 200 * time.Millisecond}
+	minute := ratelimit.Result{Allowed: true, Limit: 100, Remaining: 5, Reset: 30 * time.Second}
+	day := ratelimit.Result{Allowed: true, Limit: 1000, Remaining: 500, Reset: time.Hour}
+
+	t.Run("fewest remaining decides", func(t *testing.T) {
+		assert.Equal(t, minute, decidingResult([]ratelimit.Result{second, minute, day}))
+	})
+
+	t.Run("rejecting limit decides", func(t *testing.T) {
+		rejected := ratelimit.Result{Limit: 1000, Reset: time.Hour, RetryAfter: time.Minute}
+		assert.Equal(t, rejected, decidingResult([]ratelimit.Result{second, minute, rejected}))
+	})
+
+	t.Run("longest retry among rejecting limits", func(t *testing.T) {
+		short := ratelimit.Result{Limit: 10, RetryAfter: 100 * time.Millisecond}
+		long := ratelimit.Result{Limit: 1000, RetryAfter: time.Minute}
+		assert.Equal(t, long, decidingResult([]ratelimit.Result{short, minute, long}))
+		assert.Equal(t, long, decidingResult([]ratelimit.Result{long, short}))
+	})
+}
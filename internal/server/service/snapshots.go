@@ -0,0 +1,234 @@
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"main/internal/database/mongo"
+	mongo2 "main/internal/server/repo/mongo"
+	"main/pkg/config"
+	"main/pkg/models"
+	"main/pkg/solana"
+	"math/big"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/gagliardetto/solana-go/rpc"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	mongodriver "go.mongodb.org/mongo-driver/mongo"
+)
+
+const (
+	// maxRunningSnapshots bounds the getProgramAccounts scans running at once, they are
+	// among the most expensive RPC calls.
+	maxRunningSnapshots = 2
+	snapshotInsertBatch = 1000
+)
+
+var (
+	ErrSnapshotNotReady = errors.New("snapshot is not completed")
+	// ErrSnapshotPending is returned while the license has a job waiting for a slot.
+	ErrSnapshotPending = errors.New("a snapshot of this license is already pending")
+	// ErrInvalidSnapshot wraps the validation errors of a snapshot request.
+	ErrInvalidSnapshot = errors.New("invalid snapshot request")
+	// ErrTokenUnavailable wraps failures to read the mint that are not the caller's fault.
+	ErrTokenUnavailable = errors.New("token mint could not be read")
+
+	snapshots       *mongo2.Snapshots
+	snapshotService mongo2.SnapshotsImpl
+	snapshotSlots   = make(chan struct{}, maxRunningSnapshots)
+)
+
+func initSnapshots() {
+	snapshots = &mongo2.Snapshots{
+		Collection: mongo.Database.Collection("snapshots"),
+		Holders:    mongo.Database.Collection("snapshot_holders"),
+	}
+	if err := snapshots.EnsureIndexes(config.Config.SnapshotRetention); err != nil {
+		log.Println("Error creating snapshot indexes: " + err.Error())
+	}
+	snapshotService = mongo2.SnapshotsImpl(snapshots)
+
+	// jobs interrupted by a restart are started over
+	unfinished, err := snapshotService.ListUnfinishedSnapshots()
+	if err != nil {
+		log.Println("Error loading unfinished snapshots: " + err.Error())
+		return
+	}
+	for i := range unfinished {
+		go runSnapshot(&unfinished[i])
+	}
+}
+
+// CreateSnapshot queues a holder snapshot of the mint and returns the pending job.
+func CreateSnapshot(licenseID primitive.ObjectID, request models.CreateSnapshotRequest) (*models.Snapshot, error) {
+	if snapshotService == nil {
+		return nil, fmt.Errorf("snapshot service not initialized")
+	}
+
+	if !solana.IsValidAddress(request.Mint) {
+		return nil, fmt.Errorf("%w: invalid mint address", ErrInvalidSnapshot)
+	}
+
+	token, err := GetTokenInfo(request.Mint)
+	if errors.Is(err, solana.ErrNotAMint) || errors.Is(err, rpc.ErrNotFound) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrTokenUnavailable, err)
+	}
+
+	minAmount := uint64(0)
+	if request.MinAmount != "" {
+		if minAmount, err = solana.ParseAmount(request.MinAmount, token.Decimals); err != nil {
+			return nil, fmt.Errorf("%w: invalid min_amount: %w", ErrInvalidSnapshot, err)
+		}
+	}
+
+	snapshot := &models.Snapshot{
+		LicenseID: licenseID,
+		Mint:      token.Mint,
+		Program:   token.Program,
+		Decimals:  token.Decimals,
+		MinAmount: strconv.FormatUint(minAmount, 10),
+		Status:    models.SnapshotStatusPending,
+	}
+	err = snapshotService.CreateSnapshot(snapshot)
+	if mongodriver.IsDuplicateKeyError(err) {
+		return nil, ErrSnapshotPending
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// the job works on its own copy, the returned snapshot is encoded concurrently
+	job := *snapshot
+	go runSnapshot(&job)
+
+	return snapshot, nil
+}
+
+func GetSnapshot(licenseID, id primitive.ObjectID) (*models.Snapshot, error) {
+	if snapshotService == nil {
+		return nil, fmt.Errorf("snapshot service not initialized")
+	}
+
+	return snapshotService.GetSnapshot(licenseID, id)
+}
+
+func ListSnapshots(licenseID primitive.ObjectID) ([]models.Snapshot, error) {
+	if snapshotService == nil {
+		return nil, fmt.Errorf("snapshot service not initialized")
+	}
+
+	return snapshotService.ListSnapshots(licenseID)
+}
+
+// ExportSnapshot calls fn for every holder of a completed snapshot, ordered by owner.
+func ExportSnapshot(snapshot *models.Snapshot, fn func(holder models.SnapshotHolder) error) error {
+	if snapshot.Status != models.SnapshotStatusCompleted {
+		return ErrSnapshotNotReady
+	}
+
+	return snapshotService.EachHolder(snapshot.ID, fn)
+}
+
+func runSnapshot(snapshot *models.Snapshot) {
+	snapshotSlots <- struct{}{}
+	defer func() { <-snapshotSlots }()
+
+	started := time.Now()
+	snapshot.Status = models.SnapshotStatusRunning
+	snapshot.StartedAt = &started
+	saveSnapshot(snapshot)
+
+	if err := takeSnapshot(snapshot); err != nil {
+		log.Println("Error taking snapshot " + snapshot.ID.Hex() + ": " + err.Error())
+		snapshot.Status = models.SnapshotStatusFailed
+		snapshot.Error = err.Error()
+	} else {
+		snapshot.Status = models.SnapshotStatusCompleted
+	}
+
+	completed := time.Now()
+	snapshot.CompletedAt = &completed
+	saveSnapshot(snapshot)
+}
+
+func takeSnapshot(snapshot *models.Snapshot) error {
+	// a resumed job may have written part of its holders before the restart
+	if err := snapshotService.DeleteHolders(snapshot.ID); err != nil {
+		return err
+	}
+
+	minAmount, err := strconv.ParseUint(snapshot.MinAmount, 10, 64)
+	if err != nil {
+		return err
+	}
+
+	accounts, slot, err := solana.NewSolClient().GetMintTokenAccounts(snapshot.Mint, snapshot.Program)
+	if err != nil {
+		return err
+	}
+
+	holders, total, digest := aggregateHolders(snapshot.ID, accounts, minAmount, snapshot.Decimals)
+	for start := 0; start < len(holders); start += snapshotInsertBatch {
+		if err = snapshotService.InsertHolders(holders[start:min(start+snapshotInsertBatch, len(holders))]); err != nil {
+			return err
+		}
+	}
+
+	snapshot.Slot = slot
+	snapshot.Accounts = int64(len(accounts))
+	snapshot.Holders = int64(len(holders))
+	snapshot.TotalAmount = strconv.FormatUint(total, 10)
+	snapshot.Digest = digest
+	return nil
+}
+
+func saveSnapshot(snapshot *models.Snapshot) {
+	if err := snapshotService.UpdateSnapshot(snapshot); err != nil {
+		log.Println("Error saving snapshot " + snapshot.ID.Hex() + ": " + err.Error())
+	}
+}
+
+// aggregateHolders sums the token accounts per owner, drops owners below minAmount and
+// returns the holders ordered by owner with their total and digest. The sum cannot
+// overflow as it is bounded by the mint's u64 supply.
+func aggregateHolders(snapshotID primitive.ObjectID, accounts []solana.TokenAccountBalance, minAmount uint64, decimals uint8) ([]models.SnapshotHolder, uint64, string) {
+	amounts := make(map[string]uint64)
+	counts := make(map[string]int)
+	for _, account := range accounts {
+		amounts[account.Owner] += account.Amount
+		counts[account.Owner]++
+	}
+
+	owners := make([]string, 0, len(amounts))
+	for owner, amount := range amounts {
+		if amount > 0 && amount >= minAmount {
+			owners = append(owners, owner)
+		}
+	}
+	sort.Strings(owners)
+
+	total := uint64(0)
+	hash := sha256.New()
+	holders := make([]models.SnapshotHolder, len(owners))
+	for i, owner := range owners {
+		amount := amounts[owner]
+		total += amount
+		holders[i] = models.SnapshotHolder{
+			SnapshotID: snapshotID,
+			Owner:      owner,
+			Amount:     strconv.FormatUint(amount, 10),
+			UiAmount:   solana.FormatAmount(new(big.Int).SetUint64(amount), decimals),
+			Accounts:   counts[owner],
+		}
+		hash.Write([]byte(owner + "," + holders[i].Amount + "\n"))
+	}
+
+	return holders, total, hex.EncodeToString(hash.Sum(nil))
+}
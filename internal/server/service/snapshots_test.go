@@ -0,0 +1,39 @@
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"main/pkg/solana"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestAggregateHolders(t *testing.T) {
+	accounts := []solana.TokenAccountBalance{
+		{Owner: "carol", Amount: 5},
+		{Owner: "alice", Amount: 1_000_000},
+		{Owner: "bob", Amount: 0},
+		{Owner: "alice", Amount: 500_000},
+		{Owner: "dave", Amount: 2_000_000},
+	}
+
+	holders, total, digest := aggregateHolders(primitive.NewObjectID(), accounts, 10, 6)
+
+	require.Len(t, holders, 2)
+	assert.Equal(t, "alice", holders[0].Owner)
+	assert.Equal(t, "1500000", holders[0].Amount)
+	assert.Equal(t, "1.500000", holders[0].UiAmount)
+	assert.Equal(t, 2, holders[0].Accounts)
+	assert.Equal(t, "dave", holders[1].Owner)
+	assert.Equal(t, uint64(3_500_000), total)
+
+	expected := sha256.Sum256([]byte("alice,1500000\ndave,2000000\n"))
+	assert.Equal(t, hex.EncodeToString(expected[:]), digest)
+
+	reordered := []solana.TokenAccountBalance{accounts[4], accounts[3], accounts[2], accounts[1], accounts[0]}
+	_, _, again := aggregateHolders(primitive.NewObjectID(), reordered, 10, 6)
+	assert.Equal(t, digest, again, "digest does not depend on RPC ordering")
+}
@@ -0,0 +1,198 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"main/internal/database/mongo"
+	mongo2 "main/internal/server/repo/mongo"
+	"main/pkg/config"
+	"main/pkg/models"
+	"main/pkg/solana"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	mongodriver "go.mongodb.org/mongo-driver/mongo"
+)
+
+const (
+	// maxMetadataDocumentSize bounds the off-chain metadata JSON read for the logo URI.
+	maxMetadataDocumentSize = 1 << 20
+	// maxTokenRefreshes bounds the background fetches of mints that are not stored yet.
+	maxTokenRefreshes = 4
+)
+
+var (
+	tokens       *mongo2.Tokens
+	tokenService mongo2.TokensImpl
+	// refreshingTokens holds the mints with a background refresh in flight.
+	refreshingTokens  = sync.Map{}
+	tokenRefreshSlots = make(chan struct{}, maxTokenRefreshes)
+	// metadataClient fetches URIs written on-chain by whoever minted the token, so it
+	// only reaches public hosts.
+	metadataClient = newOutboundClient(5 * time.Second)
+)
+
+func initTokens() {
+	tokens = &mongo2.Tokens{
+		Collection: mongo.Database.Collection("tokens"),
+	}
+	tokenService = mongo2.TokensImpl(tokens)
+}
+
+// GetTokenInfo returns the decoded mint from Mongo while it is younger than
+// TOKEN_INFO_REFRESH_INTERVAL and re-reads it from chain otherwise. When the refresh
+// fails a stale entry is served rather than an error.
+func GetTokenInfo(mint string) (*models.TokenInfo, error) {
+	if tokenService == nil {
+		return nil, fmt.Errorf("token service not initialized")
+	}
+	if !solana.IsValidAddress(mint) {
+		return nil, errors.New("invalid mint address")
+	}
+
+	cached, err := tokenService.GetToken(mint)
+	if err != nil && !errors.Is(err, mongodriver.ErrNoDocuments) {
+		return nil, err
+	}
+	if cached != nil && time.Since(cached.RefreshedAt) < config.Config.TokenInfoRefreshInterval {
+		return cached, nil
+	}
+
+	token, err := refreshTokenInfo(mint)
+	if err != nil {
+		if cached != nil && !errors.Is(err, solana.ErrNotAMint) {
+			log.Println("Error refreshing token " + mint + ", serving cached info: " + err.Error())
+			return cached, nil
+		}
+		return nil, err
+	}
+
+	return token, nil
+}
+
+func refreshTokenInfo(mint string) (*models.TokenInfo, error) {
+	info, err := solana.NewSolClient().GetMintInfo(mint)
+	if err != nil {
+		return nil, err
+	}
+
+	token := &models.TokenInfo{
+		Mint:            info.Mint,
+		Program:         info.Program,
+		Supply:          strconv.FormatUint(info.Supply, 10),
+		Decimals:        info.Decimals,
+		MintAuthority:   info.MintAuthority,
+		FreezeAuthority: info.FreezeAuthority,
+		Extensions:      make([]models.TokenExtension, len(info.Extensions)),
+		Slot:            info.Slot,
+		RefreshedAt:     time.Now(),
+	}
+	for i, extension := range info.Extensions {
+		token.Extensions[i] = models.TokenExtension{Name: extension.Name, Fields: extension.Fields}
+	}
+	if info.Metadata != nil {
+		token.Name = info.Metadata.Name
+		token.Symbol = info.Metadata.Symbol
+		token.MetadataUri = info.Metadata.Uri
+		token.MetadataSource = info.Metadata.Source
+		token.LogoUri = fetchLogoUri(info.Metadata.Uri)
+	}
+
+	if err = tokenService.SaveToken(token); err != nil {
+		log.Println("Error saving token " + mint + ": " + err.Error())
+	}
+
+	return token, nil
+}
+
+// fetchLogoUri reads the image field of the off-chain metadata JSON. Metadata hosts are
+// often slow or gone, so any failure just leaves the logo out.
+func fetchLogoUri(uri string) string {
+	if validateOutboundUrl(uri) != nil {
+		return ""
+	}
+
+	resp, err := metadataClient.Get(uri)
+	if err != nil {
+		return ""
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return ""
+	}
+
+	var document struct {
+		Image string `json:"image"`
+	}
+	if err = json.NewDecoder(http.MaxBytesReader(nil, resp.Body, maxMetadataDocumentSize)).Decode(&document); err != nil {
+		return ""
+	}
+
+	return document.Image
+}
+
+// cachedTokens returns the stored info of the mints without touching the RPC. Mints that
+// are not stored yet are fetched in the background, so later responses include them. At
+// most maxTokenRefreshes run at once, mints beyond that are left to a later request.
+func cachedTokens(mints []string) map[string]models.TokenInfo {
+	result := make(map[string]models.TokenInfo, len(mints))
+	if tokenService == nil || len(mints) == 0 {
+		return result
+	}
+
+	stored, err := tokenService.GetTokens(mints)
+	if err != nil {
+		log.Println("Error loading token info:", err)
+		return result
+	}
+	for _, token := range stored {
+		result[token.Mint] = token
+	}
+
+	for _, mint := range mints {
+		if _, ok := result[mint]; ok {
+			continue
+		}
+		if _, running := refreshingTokens.LoadOrStore(mint, true); running {
+			continue
+		}
+		select {
+		case tokenRefreshSlots <- struct{}{}:
+		default:
+			refreshingTokens.Delete(mint)
+			continue
+		}
+		go func(mint string) {
+			defer func() {
+				refreshingTokens.Delete(mint)
+				<-tokenRefreshSlots
+			}()
+			if _, err := refreshTokenInfo(mint); err != nil {
+				log.Println("Error fetching token " + mint + ": " + err.Error())
+			}
+		}(mint)
+	}
+
+	return result
+}
+
+// annotateTokenTotals fills in the symbols of the mints known to the token registry.
+func annotateTokenTotals(totals ...[]models.TokenTotal) {
+	var mints []string
+	for _, list := range totals {
+		for _, total := range list {
+			mints = append(mints, total.Mint)
+		}
+	}
+
+	known := cachedTokens(mints)
+	for _, list := range totals {
+		for i := range list {
+			list[i].Symbol = known[list[i].Mint].Symbol
+		}
+	}
+}
@@ -0,0 +1,257 @@
+package service
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
+	"main/internal/database/mongo"
+	mongo2 "main/internal/server/repo/mongo"
+	"main/pkg/config"
+	"main/pkg/models"
+	"main/pkg/solana"
+	"time"
+
+	solanago "github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// maxPollsAfterExpiry bounds how often a transaction that was seen but is no longer
+// found after its blockhash expired is polled before it is given up as dropped, e.g.
+// because it only landed on a fork that was abandoned.
+const maxPollsAfterExpiry = 30
+
+var (
+	transactions       *mongo2.Transactions
+	transactionService mongo2.TransactionsImpl
+)
+
+func initTransactions() {
+	transactions = &mongo2.Transactions{
+		Collection: mongo.Database.Collection("transactions"),
+	}
+	transactionService = mongo2.TransactionsImpl(transactions)
+
+	// pick up transactions that were still in flight when the process last stopped
+	pending, err := transactionService.ListPendingTransactions()
+	if err != nil {
+		log.Println("Error loading pending transactions: " + err.Error())
+		return
+	}
+	for i := range pending {
+		go trackTransaction(&pending[i])
+	}
+}
+
+// SendTransaction broadcasts the transaction and tracks it on behalf of the license.
+func SendTransaction(licenseID primitive.ObjectID, request models.SendTransactionRequest) (*models.TransactionRecord, error) {
+	if transactionService == nil {
+		return nil, fmt.Errorf("transaction service not initialized")
+	}
+
+	if request.WebhookURL != "" {
+		if err := validateOutboundUrl(request.WebhookURL); err != nil {
+			return nil, errors.New("invalid webhook_url: " + err.Error())
+		}
+	}
+
+	rawTx, err := base64.StdEncoding.DecodeString(request.Transaction)
+	if err != nil {
+		return nil, fmt.Errorf("transaction is not valid base64: %w", err)
+	}
+
+	tx, err := solanago.TransactionFromBytes(rawTx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode transaction: %w", err)
+	}
+	if len(tx.Signatures) == 0 || tx.Signatures[0].IsZero() {
+		return nil, errors.New("transaction is not signed")
+	}
+
+	signature, err := solana.NewSolClient().SendRawTransaction(rawTx, request.SkipPreflight)
+	if err != nil {
+		return nil, err
+	}
+
+	record := &models.TransactionRecord{
+		Signature:  signature,
+		RawTx:      request.Transaction,
+		Blockhash:  tx.Message.RecentBlockhash.String(),
+		Status:     models.TransactionStatusPending,
+		Broadcasts: 1,
+		WebhookURL: request.WebhookURL,
+		OwnerID:    licenseID,
+	}
+	if err = transactionService.CreateTransaction(record); err != nil {
+		return nil, err
+	}
+
+	go trackTransaction(record)
+
+	return record, nil
+}
+
+// GetTransactionStatus returns a transaction the license sent, other licenses get
+// mongo.ErrNoDocuments.
+func GetTransactionStatus(licenseID primitive.ObjectID, signature string) (*models.TransactionRecord, error) {
+	if transactionService == nil {
+		return nil, fmt.Errorf("transaction service not initialized")
+	}
+	result, err := transactionService.GetTransaction(licenseID, signature)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// trackTransaction rebroadcasts the transaction until it lands or its blockhash
+// expires, then keeps polling its status until it is finalized or failed. Once the
+// blockhash expired a transaction that is no longer found is polled maxPollsAfterExpiry
+// times before it is expired.
+func trackTransaction(record *models.TransactionRecord) {
+	client := solana.NewSolClient()
+	ticker := time.NewTicker(config.Config.TxRebroadcastInterval)
+	defer ticker.Stop()
+
+	pollsAfterExpiry := 0
+	for range ticker.C {
+		status, err := client.GetSignatureStatus(record.Signature, false)
+		if err != nil {
+			log.Println("Error fetching signature status:", err)
+			continue
+		}
+
+		if status != nil {
+			if applyTrackedStatus(record, status) {
+				return
+			}
+			continue
+		}
+
+		valid, err := client.IsBlockhashValid(record.Blockhash)
+		if err != nil {
+			log.Println("Error checking blockhash:", err)
+			continue
+		}
+		if !valid {
+			// a transaction that landed drops out of the recent status cache after about
+			// 150 slots, which is always the case for records resumed after a restart
+			status, err = client.GetSignatureStatus(record.Signature, true)
+			if err != nil {
+				log.Println("Error searching signature history:", err)
+				continue
+			}
+			if status != nil {
+				if applyTrackedStatus(record, status) {
+					return
+				}
+				continue
+			}
+			if record.Status != models.TransactionStatusPending {
+				// it was seen before, so it may still be on its way to being finalized
+				if pollsAfterExpiry++; pollsAfterExpiry < maxPollsAfterExpiry {
+					continue
+				}
+				expireTransaction(record, "transaction was dropped before it was finalized")
+				return
+			}
+			expireTransaction(record, "blockhash expired before the transaction landed")
+			return
+		}
+
+		if record.Broadcasts >= config.Config.TxMaxRebroadcasts {
+			continue
+		}
+		rawTx, err := base64.StdEncoding.DecodeString(record.RawTx)
+		if err != nil {
+			log.Println("Error decoding stored transaction:", err)
+			return
+		}
+		if _, err = client.SendRawTransaction(rawTx, true); err != nil {
+			log.Println("Error rebroadcasting transaction:", err)
+			continue
+		}
+		record.Broadcasts++
+		saveTransaction(record, false)
+	}
+}
+
+// applyTrackedStatus saves the reported status and returns true when tracking is done.
+func applyTrackedStatus(record *models.TransactionRecord, status *rpc.SignatureStatusesResult) bool {
+	if applySignatureStatus(record, status) {
+		saveTransaction(record, true)
+	}
+	return record.Status.Done()
+}
+
+// expireTransaction gives up on a transaction whose blockhash expired before it was
+// finalized. A record another instance saw finalized or failed is left alone.
+func expireTransaction(record *models.TransactionRecord, reason string) {
+	record.Status = models.TransactionStatusExpired
+	record.Error = reason
+	expired, err := transactionService.ExpireTransaction(record)
+	if err != nil {
+		log.Println("Error expiring transaction:", err)
+		return
+	}
+	if expired && record.WebhookURL != "" {
+		go sendCallback(record.WebhookURL, *record)
+	}
+}
+
+// applySignatureStatus moves the record forward to the reported commitment level.
+// It returns false when nothing changed, as statuses never move backwards.
+func applySignatureStatus(record *models.TransactionRecord, status *rpc.SignatureStatusesResult) bool {
+	if status.Err != nil {
+		record.Status = models.TransactionStatusFailed
+		record.Error = fmt.Sprint(status.Err)
+		record.Slot = status.Slot
+		return true
+	}
+
+	next := models.TransactionStatus(status.ConfirmationStatus)
+	if transactionStatusRank(next) <= transactionStatusRank(record.Status) {
+		return false
+	}
+
+	now := time.Now()
+	record.Status = next
+	record.Slot = status.Slot
+	// a poll can skip a level, so earlier timestamps are backfilled
+	if record.ProcessedAt == nil {
+		record.ProcessedAt = &now
+	}
+	if next != models.TransactionStatusProcessed && record.ConfirmedAt == nil {
+		record.ConfirmedAt = &now
+	}
+	if next == models.TransactionStatusFinalized {
+		record.FinalizedAt = &now
+	}
+
+	return true
+}
+
+func transactionStatusRank(status models.TransactionStatus) int {
+	switch status {
+	case models.TransactionStatusProcessed:
+		return 1
+	case models.TransactionStatusConfirmed:
+		return 2
+	case models.TransactionStatusFinalized:
+		return 3
+	default:
+		return 0
+	}
+}
+
+// saveTransaction persists the record and, when the status changed, notifies the webhook.
+func saveTransaction(record *models.TransactionRecord, statusChanged bool) {
+	if err := transactionService.UpdateTransaction(record); err != nil {
+		log.Println("Error updating transaction:", err)
+	}
+	if statusChanged && record.WebhookURL != "" {
+		go sendCallback(record.WebhookURL, *record)
+	}
+}
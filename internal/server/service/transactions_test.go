@@ -0,0 +1,56 @@
+package service
+
+import (
+	"main/pkg/models"
+	"testing"
+
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplySignatureStatus_Progression(t *testing.T) {
+	record := &models.TransactionRecord{Status: models.TransactionStatusPending}
+
+	changed := applySignatureStatus(record, &rpc.SignatureStatusesResult{
+		Slot:               100,
+		ConfirmationStatus: rpc.ConfirmationStatusProcessed,
+	})
+	assert.True(t, changed)
+	assert.Equal(t, models.TransactionStatusProcessed, record.Status)
+	assert.NotNil(t, record.ProcessedAt)
+	assert.Nil(t, record.ConfirmedAt)
+
+	changed = applySignatureStatus(record, &rpc.SignatureStatusesResult{
+		Slot:               100,
+		ConfirmationStatus: rpc.ConfirmationStatusFinalized,
+	})
+	assert.True(t, changed)
+	assert.Equal(t, models.TransactionStatusFinalized, record.Status)
+	assert.NotNil(t, record.ConfirmedAt, "skipped levels should be backfilled")
+	assert.NotNil(t, record.FinalizedAt)
+	assert.True(t, record.Status.Done())
+}
+
+func TestApplySignatureStatus_NeverMovesBackwards(t *testing.T) {
+	record := &models.TransactionRecord{Status: models.TransactionStatusConfirmed}
+
+	changed := applySignatureStatus(record, &rpc.SignatureStatusesResult{
+		ConfirmationStatus: rpc.ConfirmationStatusProcessed,
+	})
+	assert.False(t, changed)
+	assert.Equal(t, models.TransactionStatusConfirmed, record.Status)
+}
+
+func TestApplySignatureStatus_Failed(t *testing.T) {
+	record := &models.TransactionRecord{Status: models.TransactionStatusPending}
+
+	changed := applySignatureStatus(record, &rpc.SignatureStatusesResult{
+		Slot:               42,
+		Err:                map[string]any{"InstructionError": []any{0, "Custom"}},
+		ConfirmationStatus: rpc.ConfirmationStatusProcessed,
+	})
+	assert.True(t, changed)
+	assert.Equal(t, models.TransactionStatusFailed, record.Status)
+	assert.NotEmpty(t, record.Error)
+	assert.Equal(t, uint64(42), record.Slot)
+}
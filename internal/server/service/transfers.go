@@ -0,0 +1,107 @@
+package service
+
+import (
+	"fmt"
+	"main/pkg/models"
+	"main/pkg/solana"
+)
+
+const (
+	solTransferComputeUnits   = 20_000
+	tokenTransferComputeUnits = 80_000
+)
+
+func BuildTransfer(request models.BuildTransferRequest) (*models.BuildTransferResponse, error) {
+	client := solana.NewSolClient()
+
+	params := solana.TransferParams{
+		From:             request.From,
+		To:               request.To,
+		Mint:             request.Mint,
+		Memo:             request.Memo,
+		ComputeUnitLimit: request.ComputeUnitLimit,
+	}
+	writable := []string{request.From, request.To}
+
+	if request.Mint == "" {
+		params.Decimals = 9
+		if params.ComputeUnitLimit == 0 {
+			params.ComputeUnitLimit = solTransferComputeUnits
+		}
+	} else {
+		decimals, err := client.GetMintDecimals(request.Mint)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load mint: %w", err)
+		}
+		params.Decimals = decimals
+		if params.ComputeUnitLimit == 0 {
+			params.ComputeUnitLimit = tokenTransferComputeUnits
+		}
+
+		source, err := solana.AssociatedTokenAddress(request.From, request.Mint)
+		if err != nil {
+			return nil, err
+		}
+		destination, err := solana.AssociatedTokenAddress(request.To, request.Mint)
+		if err != nil {
+			return nil, err
+		}
+		exists, err := client.AccountExists(destination)
+		if err != nil {
+			return nil, err
+		}
+		params.CreateRecipientAccount = !exists
+		writable = []string{request.From, source, destination}
+	}
+
+	amount, err := solana.ParseAmount(request.Amount, params.Decimals)
+	if err != nil {
+		return nil, err
+	}
+	params.Amount = amount
+
+	fees, err := EstimatePriorityFees(writable)
+	if err != nil {
+		return nil, fmt.Errorf("failed to estimate priority fee: %w", err)
+	}
+	params.ComputeUnitPrice, err = priorityFeeLevel(fees, request.Priority)
+	if err != nil {
+		return nil, err
+	}
+
+	blockhash, err := client.GetLatestBlockhash()
+	if err != nil {
+		return nil, err
+	}
+	params.Blockhash = blockhash.Blockhash.String()
+
+	tx, err := solana.BuildTransferTransaction(params)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.BuildTransferResponse{
+		Transaction:          tx,
+		Blockhash:            params.Blockhash,
+		LastValidBlockHeight: blockhash.LastValidBlockHeight,
+		Amount:               params.Amount,
+		ComputeUnitLimit:     params.ComputeUnitLimit,
+		ComputeUnitPrice:     params.ComputeUnitPrice,
+		CreatesTokenAccount:  params.CreateRecipientAccount,
+	}, nil
+}
+
+func priorityFeeLevel(estimate *models.PriorityFeeEstimate, level string) (uint64, error) {
+	switch level {
+	case "low":
+		return estimate.Low, nil
+	case "", "medium":
+		return estimate.Medium, nil
+	case "high":
+		return estimate.High, nil
+	case "very_high":
+		return estimate.VeryHigh, nil
+	default:
+		return 0, fmt.Errorf("unknown priority %q", level)
+	}
+}
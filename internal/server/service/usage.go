@@ -0,0 +1,277 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"main/internal/database/mongo"
+	mongo2 "main/internal/server/repo/mongo"
+	"main/pkg/config"
+	"main/pkg/models"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	usageDateLayout = "2006-01-02"
+	// accountUsageDays is how many days of daily usage GET /api/me returns.
+	accountUsageDays = 30
+	// maxUsageReportDays bounds the range of a usage report.
+	maxUsageReportDays = 366
+	// maxPendingUsageBuckets bounds the buckets kept in memory while Mongo rejects flushes.
+	maxPendingUsageBuckets = 100000
+)
+
+// ErrInvalidUsageRange wraps the errors of a malformed or too long usage report range.
+var ErrInvalidUsageRange = errors.New("invalid usage range")
+
+var (
+	licenseUsage        *mongo2.LicenseUsage
+	licenseUsageService mongo2.LicenseUsageImpl
+)
+
+type usageKey struct {
+	licenseID primitive.ObjectID
+	day       time.Time
+	endpoint  string
+}
+
+// pendingUsage aggregates requests in process until the next flush, so Mongo sees one
+// write per bucket and flush instead of one per request.
+var pendingUsage = struct {
+	sync.Mutex
+	buckets map[usageKey]*models.UsageBucket
+}{buckets: make(map[usageKey]*models.UsageBucket)}
+
+func initUsage() {
+	licenseUsage = &mongo2.LicenseUsage{
+		Collection: mongo.Database.Collection("license_usage"),
+	}
+	if err := licenseUsage.EnsureIndexes(config.Config.UsageRetention); err != nil {
+		log.Println("Error creating license usage indexes: " + err.Error())
+	}
+	licenseUsageService = mongo2.LicenseUsageImpl(licenseUsage)
+
+	go flushUsage()
+}
+
+// RecordUsage counts a request of the license to the endpoint, whether it failed and the
+// credits it was charged.
+func RecordUsage(licenseID primitive.ObjectID, endpoint string, credits int64, failed bool) {
+	if licenseUsageService == nil {
+		return
+	}
+	key := usageKey{licenseID: licenseID, day: usageDay(time.Now()), endpoint: endpoint}
+
+	pendingUsage.Lock()
+	defer pendingUsage.Unlock()
+	bucket, ok := pendingUsage.buckets[key]
+	if !ok {
+		bucket = &models.UsageBucket{LicenseID: licenseID, Day: key.day, Endpoint: endpoint}
+		pendingUsage.buckets[key] = bucket
+	}
+	bucket.Requests++
+	bucket.Credits += credits
+	if failed {
+		bucket.Errors++
+	}
+}
+
+// flushUsage writes the aggregated usage every USAGE_FLUSH_INTERVAL. Buckets that fail
+// to write are merged back for the next flush.
+func flushUsage() {
+	ticker := time.NewTicker(config.Config.UsageFlushInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		writePendingUsage()
+	}
+}
+
+func writePendingUsage() {
+	buckets := takePendingUsage()
+	if len(buckets) == 0 {
+		return
+	}
+	if err := licenseUsageService.AddUsage(buckets); err != nil {
+		log.Println("Error writing license usage: " + err.Error())
+		restorePendingUsage(buckets)
+	}
+}
+
+// flushPendingUsage writes the usage counted since the last flush, so it survives a shutdown.
+func flushPendingUsage() {
+	if licenseUsageService == nil {
+		return
+	}
+	writePendingUsage()
+}
+
+func takePendingUsage() []models.UsageBucket {
+	pendingUsage.Lock()
+	defer pendingUsage.Unlock()
+
+	buckets := make([]models.UsageBucket, 0, len(pendingUsage.buckets))
+	for _, bucket := range pendingUsage.buckets {
+		buckets = append(buckets, *bucket)
+	}
+	pendingUsage.buckets = make(map[usageKey]*models.UsageBucket)
+	return buckets
+}
+
+// restorePendingUsage merges buckets that failed to write back. While Mongo keeps failing
+// new buckets beyond maxPendingUsageBuckets are dropped rather than growing without bound.
+func restorePendingUsage(buckets []models.UsageBucket) {
+	pendingUsage.Lock()
+	defer pendingUsage.Unlock()
+
+	dropped := 0
+	defer func() {
+		if dropped > 0 {
+			log.Printf("Dropped %d usage buckets that could not be written", dropped)
+		}
+	}()
+	for _, bucket := range buckets {
+		key := usageKey{licenseID: bucket.LicenseID, day: bucket.Day, endpoint: bucket.Endpoint}
+		if pending, ok := pendingUsage.buckets[key]; ok {
+			pending.Requests += bucket.Requests
+			pending.Errors += bucket.Errors
+			pending.Credits += bucket.Credits
+			continue
+		}
+		if len(pendingUsage.buckets) >= maxPendingUsageBuckets {
+			dropped++
+			continue
+		}
+		restored := bucket
+		pendingUsage.buckets[key] = &restored
+	}
+}
+
+// GetAccount describes the license to its holder: plan, expiry, credits and rate limits
+// left and the daily usage of the last 30 days.
+func GetAccount(license *models.License, plan *models.Plan, rateLimits []models.RateLimitBudget) (*models.Account, error) {
+	if licenseKeyService == nil || licenseUsageService == nil {
+		return nil, fmt.Errorf("license service not initialized")
+	}
+
+	// the license in the request is cached, the usage count has to be current
+	current, err := licenseKeyService.GetLicenseByID(license.ID)
+	if err != nil {
+		return nil, err
+	}
+	usageCount, err := cacheService.GetSpentCredits(current.ID.Hex(), current.UsageCount)
+	if err != nil {
+		usageCount = current.UsageCount
+	}
+
+	to := usageDay(time.Now())
+	from := to.AddDate(0, 0, -(accountUsageDays - 1))
+	days, err := licenseUsageService.DailyUsage(current.ID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	account := &models.Account{
+		Name:       current.Name,
+		KeyPrefix:  current.KeyPrefix,
+		Plan:       plan,
+		Scopes:     current.Scopes,
+		ExpiresAt:  current.ExpiresAt,
+		UsageCount: usageCount,
+		UsageLimit: current.UsageLimit,
+		RateLimits: rateLimits,
+		DailyUsage: fillUsageDays(days, from, to),
+	}
+	if current.UsageLimit != nil {
+		remaining := max(0, *current.UsageLimit-usageCount)
+		account.RemainingCredits = &remaining
+	}
+	if account.RateLimits == nil {
+		account.RateLimits = []models.RateLimitBudget{}
+	}
+
+	return account, nil
+}
+
+// GetUsageReport sums the license's usage by endpoint over the days from to to, both
+// inclusive and formatted as 2006-01-02. They default to the last 30 days.
+func GetUsageReport(licenseID primitive.ObjectID, from, to string) (*models.UsageReport, error) {
+	if licenseUsageService == nil {
+		return nil, fmt.Errorf("license usage service not initialized")
+	}
+
+	fromDay, toDay, err := parseUsageRange(from, to, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	endpoints, err := licenseUsageService.EndpointUsage(licenseID, fromDay, toDay)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.UsageReport{
+		From:      fromDay.Format(usageDateLayout),
+		To:        toDay.Format(usageDateLayout),
+		Endpoints: endpoints,
+	}
+	for _, endpoint := range endpoints {
+		report.Requests += endpoint.Requests
+		report.Errors += endpoint.Errors
+		report.Credits += endpoint.Credits
+	}
+	return report, nil
+}
+
+func parseUsageRange(from, to string, now time.Time) (time.Time, time.Time, error) {
+	toDay := usageDay(now)
+	var err error
+	if to != "" {
+		if toDay, err = time.Parse(usageDateLayout, to); err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("%w: invalid to, expected YYYY-MM-DD", ErrInvalidUsageRange)
+		}
+	}
+	fromDay := toDay.AddDate(0, 0, -(accountUsageDays - 1))
+	if from != "" {
+		if fromDay, err = time.Parse(usageDateLayout, from); err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("%w: invalid from, expected YYYY-MM-DD", ErrInvalidUsageRange)
+		}
+	}
+
+	if fromDay.After(toDay) {
+		return time.Time{}, time.Time{}, fmt.Errorf("%w: from must not be after to", ErrInvalidUsageRange)
+	}
+	if toDay.Sub(fromDay) >= maxUsageReportDays*24*time.Hour {
+		return time.Time{}, time.Time{}, fmt.Errorf("%w: the range spans at most %d days", ErrInvalidUsageRange, maxUsageReportDays)
+	}
+
+	return fromDay, toDay, nil
+}
+
+// fillUsageDays returns one entry for every day from from to to, with zeros for days
+// without usage.
+func fillUsageDays(days []models.DailyUsage, from, to time.Time) []models.DailyUsage {
+	byDate := make(map[string]models.DailyUsage, len(days))
+	for _, day := range days {
+		byDate[day.Date] = day
+	}
+
+	filled := []models.DailyUsage{}
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		date := day.Format(usageDateLayout)
+		usage, ok := byDate[date]
+		if !ok {
+			usage = models.DailyUsage{Date: date}
+		}
+		filled = append(filled, usage)
+	}
+	return filled
+}
+
+// usageDay is the start of the UTC day of t, the bucket usage at t is counted in.
+func usageDay(t time.Time) time.Time {
+	return t.UTC().Truncate(24 * time.Hour)
+}
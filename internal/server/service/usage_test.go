@@ -0,0 +1,96 @@
+package service
+
+import (
+	"main/pkg/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestParseUsageRange(t *testing.T) {
+	now := time.Date(2024, 6, 15, 18, 30, 0, 0, time.UTC)
+
+	from, to, err := parseUsageRange("", "", now)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 5, 17, 0, 0, 0, 0, time.UTC), from)
+	assert.Equal(t, time.Date(2024, 6, 15, 0, 0, 0, 0, time.UTC), to)
+
+	from, to, err = parseUsageRange("2024-06-01", "2024-06-01", now)
+	require.NoError(t, err)
+	assert.Equal(t, from, to, "a single day")
+
+	_, _, err = parseUsageRange("2024-06-02", "2024-06-01", now)
+	assert.Error(t, err, "from after to")
+
+	_, _, err = parseUsageRange("2023-01-01", "2024-06-01", now)
+	assert.Error(t, err, "range too long")
+
+	_, _, err = parseUsageRange("June 1st", "", now)
+	assert.ErrorIs(t, err, ErrInvalidUsageRange)
+}
+
+func TestFillUsageDays(t *testing.T) {
+	from := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 6, 3, 0, 0, 0, 0, time.UTC)
+
+	days := fillUsageDays([]models.DailyUsage{{Date: "2024-06-02", Requests: 4, Credits: 40}}, from, to)
+
+	assert.Equal(t, []models.DailyUsage{
+		{Date: "2024-06-01"},
+		{Date: "2024-06-02", Requests: 4, Credits: 40},
+		{Date: "2024-06-03"},
+	}, days)
+}
+
+type stubUsageService struct {
+	models.LicenseUsageService
+}
+
+func TestPendingUsage(t *testing.T) {
+	previous := licenseUsageService
+	licenseUsageService = stubUsageService{}
+	t.Cleanup(func() {
+		licenseUsageService = previous
+		takePendingUsage()
+	})
+
+	license := primitive.NewObjectID()
+	RecordUsage(license, "POST /api/get-balance", 5, false)
+	RecordUsage(license, "POST /api/get-balance", 3, true)
+	RecordUsage(license, "GET /api/me", 0, false)
+
+	buckets := takePendingUsage()
+	require.Len(t, buckets, 2)
+	assert.Empty(t, takePendingUsage(), "taking empties the buffer")
+
+	byEndpoint := make(map[string]models.UsageBucket)
+	for _, bucket := range buckets {
+		byEndpoint[bucket.Endpoint] = bucket
+	}
+	assert.Equal(t, int64(2), byEndpoint["POST /api/get-balance"].Requests)
+	assert.Equal(t, int64(1), byEndpoint["POST /api/get-balance"].Errors)
+	assert.Equal(t, int64(8), byEndpoint["POST /api/get-balance"].Credits)
+
+	// a failed flush is merged with what was recorded in the meantime
+	RecordUsage(license, "GET /api/me", 0, false)
+	restorePendingUsage(buckets)
+	for _, bucket := range takePendingUsage() {
+		if bucket.Endpoint == "GET /api/me" {
+			assert.Equal(t, int64(2), bucket.Requests)
+		}
+	}
+}
+
+func TestRestorePendingUsageIsBounded(t *testing.T) {
+	t.Cleanup(func() { takePendingUsage() })
+
+	buckets := make([]models.UsageBucket, maxPendingUsageBuckets+10)
+	for i := range buckets {
+		buckets[i] = models.UsageBucket{LicenseID: primitive.NewObjectID(), Endpoint: "GET /api/me", Requests: 1}
+	}
+	restorePendingUsage(buckets)
+	assert.Len(t, takePendingUsage(), maxPendingUsageBuckets)
+}
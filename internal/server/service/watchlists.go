@@ -0,0 +1,274 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"main/internal/database/mongo"
+	mongo2 "main/internal/server/repo/mongo"
+	"main/pkg/config"
+	"main/pkg/models"
+	"main/pkg/solana"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const maxWatchlistWallets = 1000
+
+const (
+	// watchedWalletLease bounds how long a crashed instance can block a license's
+	// watched wallet changes.
+	watchedWalletLease = 10 * time.Second
+	// watchedWalletLockWait is how long a change waits for another one of the same
+	// license to finish.
+	watchedWalletLockWait = 5 * time.Second
+)
+
+var (
+	ErrWatchedWalletsBusy = errors.New("the license's watched wallets are being changed, try again")
+
+	watchlists       *mongo2.Watchlists
+	watchlistService mongo2.WatchlistsImpl
+)
+
+func initWatchlists() {
+	watchlists = &mongo2.Watchlists{
+		Collection: mongo.Database.Collection("watchlists"),
+		Locks:      mongo.Database.Collection("watched_wallet_locks"),
+	}
+	watchlistService = mongo2.WatchlistsImpl(watchlists)
+
+	go refreshWatchedWallets()
+}
+
+func CreateWatchlist(licenseID primitive.ObjectID, plan *models.Plan, request models.WatchlistRequest) (*models.Watchlist, error) {
+	if watchlistService == nil {
+		return nil, fmt.Errorf("watchlist service not initialized")
+	}
+	if err := validateWatchlist(request); err != nil {
+		return nil, err
+	}
+
+	watchlist := &models.Watchlist{
+		LicenseID: licenseID,
+		Name:      request.Name,
+		Wallets:   request.Wallets,
+	}
+	err := withWatchedWalletLock(licenseID, func() error {
+		if err := checkWatchedWalletLimit(licenseID, plan, primitive.NilObjectID, watchlistAddresses(request.Wallets)); err != nil {
+			return err
+		}
+		return watchlistService.CreateWatchlist(watchlist)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return watchlist, nil
+}
+
+func ListWatchlists(licenseID primitive.ObjectID) ([]models.Watchlist, error) {
+	if watchlistService == nil {
+		return nil, fmt.Errorf("watchlist service not initialized")
+	}
+	result, err := watchlistService.ListWatchlists(licenseID)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func GetWatchlist(licenseID, id primitive.ObjectID) (*models.Watchlist, error) {
+	if watchlistService == nil {
+		return nil, fmt.Errorf("watchlist service not initialized")
+	}
+	result, err := watchlistService.GetWatchlist(licenseID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func UpdateWatchlist(licenseID, id primitive.ObjectID, plan *models.Plan, request models.WatchlistRequest) (*models.Watchlist, error) {
+	if watchlistService == nil {
+		return nil, fmt.Errorf("watchlist service not initialized")
+	}
+	if err := validateWatchlist(request); err != nil {
+		return nil, err
+	}
+
+	watchlist := &models.Watchlist{
+		ID:        id,
+		LicenseID: licenseID,
+		Name:      request.Name,
+		Wallets:   request.Wallets,
+	}
+	err := withWatchedWalletLock(licenseID, func() error {
+		if err := checkWatchedWalletLimit(licenseID, plan, id, watchlistAddresses(request.Wallets)); err != nil {
+			return err
+		}
+		return watchlistService.UpdateWatchlist(watchlist)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return watchlistService.GetWatchlist(licenseID, id)
+}
+
+func DeleteWatchlist(licenseID, id primitive.ObjectID) error {
+	if watchlistService == nil {
+		return fmt.Errorf("watchlist service not initialized")
+	}
+
+	return watchlistService.DeleteWatchlist(licenseID, id)
+}
+
+func validateWatchlist(request models.WatchlistRequest) error {
+	if request.Name == "" {
+		return errors.New("name is required")
+	}
+	if len(request.Wallets) > maxWatchlistWallets {
+		return fmt.Errorf("a watchlist holds at most %d wallets", maxWatchlistWallets)
+	}
+
+	seen := make(map[string]bool, len(request.Wallets))
+	for _, wallet := range request.Wallets {
+		if !solana.IsValidAddress(wallet.Address) {
+			return errors.New("invalid wallet address: " + wallet.Address)
+		}
+		if seen[wallet.Address] {
+			return errors.New("duplicate wallet address: " + wallet.Address)
+		}
+		seen[wallet.Address] = true
+	}
+
+	return nil
+}
+
+// withWatchedWalletLock runs fn while holding the license's watched wallet lease, so
+// concurrent changes on any instance cannot together go over the plan's limit.
+func withWatchedWalletLock(licenseID primitive.ObjectID, fn func() error) error {
+	token := primitive.NewObjectID()
+	deadline := time.Now().Add(watchedWalletLockWait)
+	for {
+		locked, err := watchlistService.LockWatchedWallets(licenseID, token, watchedWalletLease)
+		if err != nil {
+			return err
+		}
+		if locked {
+			break
+		}
+		if time.Now().After(deadline) {
+			return ErrWatchedWalletsBusy
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	defer func() {
+		if err := watchlistService.UnlockWatchedWallets(licenseID, token); err != nil {
+			log.Println("Error releasing watched wallet lease:", err)
+		}
+	}()
+
+	return fn()
+}
+
+// checkWatchedWalletLimit rejects a change when the license's watchlists and alert rules
+// would watch more distinct wallets than its plan allows, as all of them are refreshed in
+// the background. replaced is the watchlist the change replaces, if any.
+func checkWatchedWalletLimit(licenseID primitive.ObjectID, plan *models.Plan, replaced primitive.ObjectID, wallets []string) error {
+	if plan == nil || plan.MaxWatchedWallets <= 0 {
+		return nil
+	}
+	existing, err := watchlistService.ListWatchlists(licenseID)
+	if err != nil {
+		return err
+	}
+	var rules []models.AlertRule
+	if alertService != nil {
+		if rules, err = alertService.ListRules(licenseID); err != nil {
+			return err
+		}
+	}
+	if watched := countWatchedWallets(existing, replaced, rules, wallets); watched > plan.MaxWatchedWallets {
+		return fmt.Errorf("the %s plan allows at most %d watched wallets across all watchlists and alert rules", plan.Name, plan.MaxWatchedWallets)
+	}
+	return nil
+}
+
+func countWatchedWallets(watchlists []models.Watchlist, replaced primitive.ObjectID, rules []models.AlertRule, wallets []string) int {
+	seen := make(map[string]bool)
+	for _, watchlist := range watchlists {
+		if watchlist.ID == replaced {
+			continue
+		}
+		for _, wallet := range watchlist.Wallets {
+			seen[wallet.Address] = true
+		}
+	}
+	for _, rule := range rules {
+		seen[rule.Wallet] = true
+	}
+	for _, wallet := range wallets {
+		seen[wallet] = true
+	}
+	return len(seen)
+}
+
+func watchlistAddresses(wallets []models.WatchlistWallet) []string {
+	addresses := make([]string, 0, len(wallets))
+	for _, wallet := range wallets {
+		addresses = append(addresses, wallet.Address)
+	}
+	return addresses
+}
+
+// refreshWatchedWallets re-caches every watched wallet shortly before its entry would
+// expire, so watchlist balance lookups are served from the cache. Wallets with alert
+// rules are refreshed as well so their rules are evaluated without any lookups.
+func refreshWatchedWallets() {
+	ticker := time.NewTicker(config.Config.WatchlistRefreshInterval)
+	defer ticker.Stop()
+
+	client := solana.NewSolClient()
+	for range ticker.C {
+		wallets, err := watchlistService.ListWatchedWallets()
+		if err != nil {
+			log.Println("Error listing watched wallets:", err)
+			continue
+		}
+		wallets = mergeWallets(wallets, alertedWallets())
+		if len(wallets) == 0 {
+			continue
+		}
+
+		balances, slots, err := client.GetBalances(wallets)
+		if err != nil {
+			log.Println("Error refreshing watched wallets:", err)
+			continue
+		}
+		for wallet, lamports := range balances {
+			ObserveBalance(wallet, lamports, slots[wallet])
+			if err = SetWallet(wallet, solana.LamportsToSol(lamports)); err != nil {
+				log.Println("Error caching watched wallet:", err)
+			}
+		}
+	}
+}
+
+func mergeWallets(wallets, more []string) []string {
+	seen := make(map[string]bool, len(wallets))
+	for _, wallet := range wallets {
+		seen[wallet] = true
+	}
+	for _, wallet := range more {
+		if !seen[wallet] {
+			seen[wallet] = true
+			wallets = append(wallets, wallet)
+		}
+	}
+	return wallets
+}
@@ -0,0 +1,50 @@
+package service
+
+import (
+	"main/pkg/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestValidateWatchlist(t *testing.T) {
+	wallet := "11111111111111111111111111111111"
+
+	assert.NoError(t, validateWatchlist(models.WatchlistRequest{
+		Name:    "treasury",
+		Wallets: []models.WatchlistWallet{{Address: wallet, Label: "system"}},
+	}))
+
+	assert.Error(t, validateWatchlist(models.WatchlistRequest{
+		Wallets: []models.WatchlistWallet{{Address: wallet}},
+	}), "name is required")
+
+	assert.Error(t, validateWatchlist(models.WatchlistRequest{
+		Name:    "bad",
+		Wallets: []models.WatchlistWallet{{Address: "not-a-wallet"}},
+	}))
+
+	assert.Error(t, validateWatchlist(models.WatchlistRequest{
+		Name:    "dupes",
+		Wallets: []models.WatchlistWallet{{Address: wallet}, {Address: wallet}},
+	}))
+}
+
+func TestCountWatchedWallets(t *testing.T) {
+	a := models.WatchlistWallet{Address: "a"}
+	b := models.WatchlistWallet{Address: "b"}
+	c := models.WatchlistWallet{Address: "c"}
+	replaced := primitive.NewObjectID()
+	existing := []models.Watchlist{
+		{ID: primitive.NewObjectID(), Wallets: []models.WatchlistWallet{a, b}},
+		{ID: replaced, Wallets: []models.WatchlistWallet{c}},
+	}
+
+	rules := []models.AlertRule{{Wallet: "a"}, {Wallet: "d"}}
+
+	assert.Equal(t, 3, countWatchedWallets(existing, primitive.NilObjectID, nil, []string{"a"}), "wallets on several watchlists count once")
+	assert.Equal(t, 2, countWatchedWallets(existing, replaced, nil, []string{"b"}), "the replaced watchlist does not count")
+	assert.Equal(t, 4, countWatchedWallets(existing, primitive.NilObjectID, rules, nil), "alerted wallets count once")
+	assert.Equal(t, 5, countWatchedWallets(existing, primitive.NilObjectID, rules, []string{"e"}), "a new alerted wallet counts")
+}
@@ -0,0 +1,352 @@
+package service
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"main/internal/database/mongo"
+	mongo2 "main/internal/server/repo/mongo"
+	"main/pkg/config"
+	"main/pkg/models"
+	"main/pkg/solana"
+	"net/http"
+	"strconv"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	mongodriver "go.mongodb.org/mongo-driver/mongo"
+)
+
+const (
+	webhookRetryPollInterval = 5 * time.Second
+	webhookMaxRetryDelay     = time.Hour
+	webhookDeliveryLogLimit  = 100
+)
+
+var (
+	webhooks                 *mongo2.Webhooks
+	webhookService           mongo2.WebhooksImpl
+	webhookDeliveries        *mongo2.WebhookDeliveries
+	webhookDeliveryService   mongo2.WebhookDeliveriesImpl
+	errWebhookResponseStatus = errors.New("unexpected response status")
+)
+
+func initWebhooks() {
+	webhooks = &mongo2.Webhooks{
+		Collection: mongo.Database.Collection("webhooks"),
+	}
+	webhookService = mongo2.WebhooksImpl(webhooks)
+
+	webhookDeliveries = &mongo2.WebhookDeliveries{
+		Collection:  mongo.Database.Collection("webhook_deliveries"),
+		DeadLetters: mongo.Database.Collection("webhook_dead_letters"),
+	}
+	webhookDeliveryService = mongo2.WebhookDeliveriesImpl(webhookDeliveries)
+
+	addBalanceObserver(dispatchWebhooks)
+	go retryWebhookDeliveries()
+}
+
+func CreateWebhook(licenseID primitive.ObjectID, request models.CreateWebhookRequest) (*models.Webhook, error) {
+	if webhookService == nil {
+		return nil, fmt.Errorf("webhook service not initialized")
+	}
+
+	watchlistID, err := primitive.ObjectIDFromHex(request.WatchlistID)
+	if err != nil {
+		return nil, errors.New("invalid watchlist_id")
+	}
+	if _, err = GetWatchlist(licenseID, watchlistID); err != nil {
+		return nil, errors.New("watchlist not found")
+	}
+	if err = validateOutboundUrl(request.URL); err != nil {
+		return nil, err
+	}
+
+	webhook := &models.Webhook{
+		LicenseID:   licenseID,
+		WatchlistID: watchlistID,
+		URL:         request.URL,
+		Event:       request.Event,
+	}
+	switch request.Event {
+	case models.WebhookEventBalanceChange:
+	case models.WebhookEventThreshold:
+		if request.Threshold == "" {
+			return nil, errors.New("threshold is required for threshold webhooks")
+		}
+		webhook.ThresholdLamports, err = solana.ParseAmount(request.Threshold, 9)
+		if err != nil {
+			return nil, errors.New("invalid threshold: " + err.Error())
+		}
+	default:
+		return nil, errors.New("event must be balance_change or threshold")
+	}
+
+	webhook.Secret, err = newWebhookSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	if err = webhookService.CreateWebhook(webhook); err != nil {
+		return nil, err
+	}
+
+	return webhook, nil
+}
+
+func ListWebhooks(licenseID primitive.ObjectID) ([]models.Webhook, error) {
+	if webhookService == nil {
+		return nil, fmt.Errorf("webhook service not initialized")
+	}
+	result, err := webhookService.ListWebhooks(licenseID)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range result {
+		result[i].Secret = ""
+	}
+	return result, nil
+}
+
+func DeleteWebhook(licenseID, id primitive.ObjectID) error {
+	if webhookService == nil {
+		return fmt.Errorf("webhook service not initialized")
+	}
+
+	return webhookService.DeleteWebhook(licenseID, id)
+}
+
+// ListWebhookDeliveries returns the most recent deliveries of a webhook owned by the license.
+func ListWebhookDeliveries(licenseID, id primitive.ObjectID) ([]models.WebhookDelivery, error) {
+	if webhookService == nil {
+		return nil, fmt.Errorf("webhook service not initialized")
+	}
+	if _, err := webhookService.GetWebhook(licenseID, id); err != nil {
+		return nil, err
+	}
+
+	return webhookDeliveryService.ListDeliveries(id, webhookDeliveryLogLimit)
+}
+
+// newWebhookSecret returns a random 256 bit key for signing the webhook's deliveries.
+func newWebhookSecret() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// dispatchWebhooks queues a delivery for every webhook whose watchlist contains the
+// observed wallet and whose event matches the change.
+func dispatchWebhooks(observation models.BalanceObservation) {
+	if observation.Previous == nil || *observation.Previous == observation.Lamports {
+		return
+	}
+
+	watched, err := watchlistService.FindWatchlistsWithWallet(observation.Wallet)
+	if err != nil {
+		log.Println("Error finding watchlists for " + observation.Wallet + ": " + err.Error())
+		return
+	}
+	if len(watched) == 0 {
+		return
+	}
+
+	labels := make(map[primitive.ObjectID]string, len(watched))
+	ids := make([]primitive.ObjectID, len(watched))
+	for i, watchlist := range watched {
+		ids[i] = watchlist.ID
+		for _, wallet := range watchlist.Wallets {
+			if wallet.Address == observation.Wallet {
+				labels[watchlist.ID] = wallet.Label
+			}
+		}
+	}
+
+	subscribed, err := webhookService.ListWebhooksForWatchlists(ids)
+	if err != nil {
+		log.Println("Error listing webhooks: " + err.Error())
+		return
+	}
+
+	for _, webhook := range subscribed {
+		if !webhookTriggered(webhook, *observation.Previous, observation.Lamports) {
+			continue
+		}
+
+		payload, err := json.Marshal(models.WebhookPayload{
+			Event:            webhook.Event,
+			WebhookID:        webhook.ID.Hex(),
+			Wallet:           observation.Wallet,
+			Label:            labels[webhook.WatchlistID],
+			PreviousLamports: *observation.Previous,
+			Lamports:         observation.Lamports,
+			Balance:          solana.LamportsToSol(observation.Lamports),
+			Threshold:        webhook.ThresholdLamports,
+			Slot:             observation.Slot,
+			ObservedAt:       observation.ObservedAt,
+		})
+		if err != nil {
+			log.Println("Error encoding webhook payload: " + err.Error())
+			continue
+		}
+
+		// the first attempt is made right away, next_attempt_at only lets the retry
+		// worker pick the delivery up if this attempt never finishes
+		lease := time.Now().Add(2 * callbackClient.Timeout)
+		delivery := &models.WebhookDelivery{
+			WebhookID:     webhook.ID,
+			LicenseID:     webhook.LicenseID,
+			URL:           webhook.URL,
+			Payload:       string(payload),
+			Status:        models.DeliveryStatusPending,
+			NextAttemptAt: &lease,
+		}
+		if err = webhookDeliveryService.CreateDelivery(delivery); err != nil {
+			log.Println("Error saving webhook delivery: " + err.Error())
+			continue
+		}
+
+		attemptDelivery(delivery, webhook.Secret)
+	}
+}
+
+// webhookTriggered reports whether a change from previous to current lamports fires the webhook.
+// Threshold webhooks fire when the balance crosses the threshold in either direction.
+func webhookTriggered(webhook models.Webhook, previous, current uint64) bool {
+	switch webhook.Event {
+	case models.WebhookEventBalanceChange:
+		return previous != current
+	case models.WebhookEventThreshold:
+		return (previous < webhook.ThresholdLamports) != (current < webhook.ThresholdLamports)
+	}
+	return false
+}
+
+// retryWebhookDeliveries re-sends failed deliveries once their backoff has elapsed.
+func retryWebhookDeliveries() {
+	ticker := time.NewTicker(webhookRetryPollInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		due, err := webhookDeliveryService.ListDueDeliveries(time.Now())
+		if err != nil {
+			log.Println("Error listing webhook deliveries: " + err.Error())
+			continue
+		}
+
+		for i := range due {
+			delivery := &due[i]
+			webhook, err := webhookService.GetWebhook(delivery.LicenseID, delivery.WebhookID)
+			if err != nil && !errors.Is(err, mongodriver.ErrNoDocuments) {
+				log.Println("Error loading webhook " + delivery.WebhookID.Hex() + ": " + err.Error())
+				continue
+			}
+			if err != nil {
+				delivery.LastError = "webhook deleted"
+				delivery.Attempts = config.Config.WebhookMaxAttempts
+				finishDelivery(delivery)
+				continue
+			}
+
+			delivery.URL = webhook.URL
+			attemptDelivery(delivery, webhook.Secret)
+		}
+	}
+}
+
+// attemptDelivery sends the delivery once and records the outcome, scheduling the next
+// attempt with exponential backoff or dead-lettering it after the last attempt.
+func attemptDelivery(delivery *models.WebhookDelivery, secret string) {
+	delivery.Attempts++
+	status, err := postWebhook(delivery, secret)
+	delivery.ResponseStatus = status
+
+	if err == nil {
+		now := time.Now()
+		delivery.Status = models.DeliveryStatusDelivered
+		delivery.LastError = ""
+		delivery.NextAttemptAt = nil
+		delivery.DeliveredAt = &now
+		saveDelivery(delivery)
+		return
+	}
+
+	delivery.LastError = err.Error()
+	finishDelivery(delivery)
+}
+
+// finishDelivery schedules a failed delivery for another attempt, or moves it to the
+// dead letters once every attempt has been used.
+func finishDelivery(delivery *models.WebhookDelivery) {
+	if delivery.Attempts < config.Config.WebhookMaxAttempts {
+		next := time.Now().Add(webhookRetryDelay(delivery.Attempts))
+		delivery.NextAttemptAt = &next
+		saveDelivery(delivery)
+		return
+	}
+
+	delivery.Status = models.DeliveryStatusDead
+	delivery.NextAttemptAt = nil
+	saveDelivery(delivery)
+	if err := webhookDeliveryService.AddDeadLetter(delivery); err != nil {
+		log.Println("Error dead-lettering webhook delivery " + delivery.ID.Hex() + ": " + err.Error())
+	}
+}
+
+func saveDelivery(delivery *models.WebhookDelivery) {
+	if err := webhookDeliveryService.UpdateDelivery(delivery); err != nil {
+		log.Println("Error updating webhook delivery " + delivery.ID.Hex() + ": " + err.Error())
+	}
+}
+
+// webhookRetryDelay doubles the base delay for every failed attempt, capped at an hour.
+func webhookRetryDelay(attempts int) time.Duration {
+	delay := config.Config.WebhookRetryBaseDelay
+	for i := 1; i < attempts && delay < webhookMaxRetryDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, webhookMaxRetryDelay)
+}
+
+func postWebhook(delivery *models.WebhookDelivery, secret string) (int, error) {
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+
+	req, err := http.NewRequest(http.MethodPost, delivery.URL, bytes.NewBufferString(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Webhook-Id", delivery.WebhookID.Hex())
+	req.Header.Set("X-Webhook-Delivery", delivery.ID.Hex())
+	req.Header.Set("X-Webhook-Timestamp", timestamp)
+	req.Header.Set("X-Webhook-Signature", signWebhookPayload(secret, timestamp, delivery.Payload))
+
+	resp, err := callbackClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("%w %d", errWebhookResponseStatus, resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// signWebhookPayload returns the X-Webhook-Signature value, an HMAC-SHA256 over
+// "<timestamp>.<body>" so receivers can reject replayed requests.
+func signWebhookPayload(secret, timestamp, payload string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "." + payload))
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,40 @@
+package service
+
+import (
+	"main/pkg/config"
+	"main/pkg/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebhookTriggered(t *testing.T) {
+	change := models.Webhook{Event: models.WebhookEventBalanceChange}
+	assert.True(t, webhookTriggered(change, 10, 20))
+	assert.False(t, webhookTriggered(change, 10, 10))
+
+	threshold := models.Webhook{Event: models.WebhookEventThreshold, ThresholdLamports: 100}
+	assert.True(t, webhookTriggered(threshold, 150, 50), "crossing downwards")
+	assert.True(t, webhookTriggered(threshold, 50, 100), "reaching the threshold")
+	assert.False(t, webhookTriggered(threshold, 150, 120), "staying above")
+	assert.False(t, webhookTriggered(threshold, 50, 20), "staying below")
+}
+
+func TestWebhookRetryDelay(t *testing.T) {
+	config.Config = &config.Structure{WebhookRetryBaseDelay: 5 * time.Second}
+
+	assert.Equal(t, 5*time.Second, webhookRetryDelay(1))
+	assert.Equal(t, 10*time.Second, webhookRetryDelay(2))
+	assert.Equal(t, 40*time.Second, webhookRetryDelay(4))
+	assert.Equal(t, time.Hour, webhookRetryDelay(20))
+}
+
+func TestSignWebhookPayload(t *testing.T) {
+	signature := signWebhookPayload("secret", "1700000000", `{"event":"balance_change"}`)
+
+	assert.Equal(t, signature, signWebhookPayload("secret", "1700000000", `{"event":"balance_change"}`))
+	assert.NotEqual(t, signature, signWebhookPayload("secret", "1700000001", `{"event":"balance_change"}`))
+	assert.NotEqual(t, signature, signWebhookPayload("other", "1700000000", `{"event":"balance_change"}`))
+	assert.Len(t, signature, len("sha256=")+64)
+}
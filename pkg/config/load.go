@@ -1,13 +1,133 @@
 package config
 
-import "os"
+import (
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
 
 func Load() {
 	Config = &Structure{
 		RpcUri:      os.Getenv("RPC_URI"),
+		RpcWsUri:    getEnv("RPC_WS_URI", websocketUri(os.Getenv("RPC_URI"))),
 		Port:        os.Getenv("PORT"),
 		MongoDbName: os.Getenv("MONGO_DB_NAME"),
 		MongoUri:    os.Getenv("MONGO_URI"),
 		RedisUri:    os.Getenv("REDIS_URL"),
+
+		SolanaNetwork: getEnv("SOLANA_NETWORK", "mainnet"),
+
+		TxRebroadcastInterval: getEnvDuration("TX_REBROADCAST_INTERVAL", 2*time.Second),
+		TxMaxRebroadcasts:     getEnvInt("TX_MAX_REBROADCASTS", 30),
+
+		PriorityFeeSlotWindow: getEnvInt("PRIORITY_FEE_SLOT_WINDOW", 150),
+
+		PaymentPollInterval: getEnvDuration("PAYMENT_POLL_INTERVAL", 5*time.Second),
+
+		HotWalletThreshold: getEnvInt("HOT_WALLET_THRESHOLD", 5),
+		HotWalletWindow:    getEnvDuration("HOT_WALLET_WINDOW", time.Minute),
+		HotWalletTTL:       getEnvDuration("HOT_WALLET_TTL", 10*time.Minute),
+		HotWalletMax:       getEnvInt("HOT_WALLET_MAX", 1000),
+
+		// shorter than the 10 second wallet cache TTL so watched wallets never expire
+		WatchlistRefreshInterval: getEnvDuration("WATCHLIST_REFRESH_INTERVAL", 8*time.Second),
+
+		WalletGroupCacheTTL: getEnvDuration("WALLET_GROUP_CACHE_TTL", 30*time.Second),
+
+		AlertDefaultCooldown: getEnvDuration("ALERT_DEFAULT_COOLDOWN", 15*time.Minute),
+
+		BalanceHistoryRetention: getEnvDuration("BALANCE_HISTORY_RETENTION", 90*24*time.Hour),
+
+		TokenInfoRefreshInterval: getEnvDuration("TOKEN_INFO_REFRESH_INTERVAL", time.Hour),
+
+		PythPriceAccounts:  getEnvPairs("PYTH_PRICE_ACCOUNTS"),
+		PriceMaxAge:        getEnvDuration("PRICE_MAX_AGE", time.Minute),
+		PriceMaxConfidence: getEnvFloat("PRICE_MAX_CONFIDENCE", 0.02),
+
+		WebhookMaxAttempts:    getEnvInt("WEBHOOK_MAX_ATTEMPTS", 6),
+		WebhookRetryBaseDelay: getEnvDuration("WEBHOOK_RETRY_BASE_DELAY", 5*time.Second),
+
+		DefaultPlan:         getEnv("DEFAULT_PLAN", "default"),
+		IpRequestsPerMinute: getEnvInt("IP_REQUESTS_PER_MINUTE", 300),
+		RateLimitFallback:   getEnv("RATE_LIMIT_FALLBACK", "local"),
+
+		LicenseCacheTTL:         getEnvDuration("LICENSE_CACHE_TTL", 30*time.Second),
+		LicenseNegativeCacheTTL: getEnvDuration("LICENSE_NEGATIVE_CACHE_TTL", 5*time.Second),
+		UsageFlushInterval:      getEnvDuration("USAGE_FLUSH_INTERVAL", 5*time.Second),
+		// long enough for statements of the past year
+		UsageRetention:     getEnvDuration("USAGE_RETENTION", 400*24*time.Hour),
+		SnapshotRetention:  getEnvDuration("SNAPSHOT_RETENTION", 30*24*time.Hour),
+		BillingCreditPrice: os.Getenv("BILLING_CREDIT_PRICE"),
+		BillingCurrency:    getEnv("BILLING_CURRENCY", "USD"),
+
+		CreditCosts: getEnvPairs("CREDIT_COSTS"),
+
+		AdminApiKeys: getEnvPairs("ADMIN_API_KEYS"),
+
+		LicenseKeyRotationGrace: getEnvDuration("LICENSE_KEY_ROTATION_GRACE", 24*time.Hour),
+
+		LicensePaymentRecipient: os.Getenv("LICENSE_PAYMENT_RECIPIENT"),
+		LicensePriceSol:         getEnv("LICENSE_PRICE_SOL", "0.1"),
+		LicensePriceUsdc:        getEnv("LICENSE_PRICE_USDC", "10"),
+		LicensePackageUsage:     int64(getEnvInt("LICENSE_PACKAGE_USAGE", 10000)),
+		LicensePackageDays:      getEnvInt("LICENSE_PACKAGE_DAYS", 30),
+		UsdcMint:                os.Getenv("USDC_MINT"),
+	}
+}
+
+// websocketUri derives the PubSub endpoint from the HTTP RPC endpoint, which is where
+// public and most hosted RPC providers serve it.
+func websocketUri(rpcUri string) string {
+	if strings.HasPrefix(rpcUri, "https://") {
+		return "wss://" + strings.TrimPrefix(rpcUri, "https://")
+	}
+	if strings.HasPrefix(rpcUri, "http://") {
+		return "ws://" + strings.TrimPrefix(rpcUri, "http://")
+	}
+	return rpcUri
+}
+
+func getEnv(key, def string) string {
+	if val := os.Getenv(key); val != "" {
+		return val
+	}
+	return def
+}
+
+// getEnvDuration parses a Go duration string (e.g. "2s") and falls back to def when unset or invalid.
+func getEnvDuration(key string, def time.Duration) time.Duration {
+	val, err := time.ParseDuration(os.Getenv(key))
+	if err != nil {
+		return def
+	}
+	return val
+}
+
+func getEnvInt(key string, def int) int {
+	val, err := strconv.Atoi(os.Getenv(key))
+	if err != nil {
+		return def
+	}
+	return val
+}
+
+func getEnvFloat(key string, def float64) float64 {
+	val, err := strconv.ParseFloat(os.Getenv(key), 64)
+	if err != nil {
+		return def
+	}
+	return val
+}
+
+// getEnvPairs parses a comma separated list of key=value pairs.
+func getEnvPairs(key string) map[string]string {
+	pairs := make(map[string]string)
+	for _, pair := range strings.Split(os.Getenv(key), ",") {
+		name, value, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if ok && name != "" && value != "" {
+			pairs[name] = value
+		}
 	}
+	return pairs
 }
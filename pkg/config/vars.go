@@ -1,11 +1,81 @@
 package config
 
+import "time"
+
 type Structure struct {
 	Port        string
 	RpcUri      string
+	RpcWsUri    string
 	MongoDbName string
 	MongoUri    string
 	RedisUri    string
+	// SolanaNetwork names the cluster behind RpcUri, licenses need its network scope.
+	SolanaNetwork string
+
+	TxRebroadcastInterval time.Duration
+	TxMaxRebroadcasts     int
+
+	PriorityFeeSlotWindow int
+
+	PaymentPollInterval time.Duration
+
+	HotWalletThreshold int
+	HotWalletWindow    time.Duration
+	HotWalletTTL       time.Duration
+	HotWalletMax       int
+
+	WatchlistRefreshInterval time.Duration
+
+	WalletGroupCacheTTL time.Duration
+
+	AlertDefaultCooldown time.Duration
+
+	BalanceHistoryRetention time.Duration
+
+	TokenInfoRefreshInterval time.Duration
+
+	PythPriceAccounts  map[string]string
+	PriceMaxAge        time.Duration
+	PriceMaxConfidence float64
+
+	WebhookMaxAttempts    int
+	WebhookRetryBaseDelay time.Duration
+
+	// DefaultPlan is the plan of licenses that do not name one.
+	DefaultPlan         string
+	IpRequestsPerMinute int
+	// RateLimitFallback is the policy while Redis is unreachable: local, open or closed.
+	RateLimitFallback string
+
+	// LicenseCacheTTL bounds how long a validated license is served from the caches,
+	// LicenseNegativeCacheTTL how long an unknown key is.
+	LicenseCacheTTL         time.Duration
+	LicenseNegativeCacheTTL time.Duration
+	// UsageFlushInterval is how often credits charged in Redis are written to Mongo.
+	UsageFlushInterval time.Duration
+	// UsageRetention is how long per-endpoint usage buckets are kept.
+	UsageRetention time.Duration
+	// SnapshotRetention is how long holder snapshots and their holders are kept.
+	SnapshotRetention time.Duration
+	// BillingCreditPrice is the price of one credit as a decimal, statements are only
+	// priced when it is set.
+	BillingCreditPrice string
+	BillingCurrency    string
+
+	// CreditCosts maps operations to the credits they cost, unset ones keep their default.
+	CreditCosts map[string]string
+
+	// AdminApiKeys maps every admin key to its role.
+	AdminApiKeys map[string]string
+
+	LicenseKeyRotationGrace time.Duration
+
+	LicensePaymentRecipient string
+	LicensePriceSol         string
+	LicensePriceUsdc        string
+	LicensePackageUsage     int64
+	LicensePackageDays      int
+	UsdcMint                string
 }
 
 var (
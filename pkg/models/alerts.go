@@ -0,0 +1,94 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type AlertRuleType string
+
+const (
+	// AlertBalanceBelow fires when the balance falls below ThresholdLamports.
+	AlertBalanceBelow AlertRuleType = "balance_below"
+	// AlertBalanceDrop fires when the balance is more than DropPercent below its peak within the window.
+	AlertBalanceDrop AlertRuleType = "balance_drop"
+	// AlertOutgoingTransfer fires when a single observed decrease exceeds ThresholdLamports.
+	AlertOutgoingTransfer AlertRuleType = "outgoing_transfer"
+)
+
+// Alerts stores the rules in Collection and every fired alert in Events.
+type Alerts struct {
+	Collection *mongo.Collection
+	Events     *mongo.Collection
+}
+
+type AlertRule struct {
+	ID                primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	LicenseID         primitive.ObjectID `bson:"license_id" json:"-"`
+	Name              string             `bson:"name" json:"name"`
+	Wallet            string             `bson:"wallet" json:"wallet"`
+	Type              AlertRuleType      `bson:"type" json:"type"`
+	ThresholdLamports uint64             `bson:"threshold_lamports,omitempty" json:"threshold_lamports,omitempty"`
+	DropPercent       float64            `bson:"drop_percent,omitempty" json:"drop_percent,omitempty"`
+	WindowSeconds     int64              `bson:"window_seconds,omitempty" json:"window_seconds,omitempty"`
+	CooldownSeconds   int64              `bson:"cooldown_seconds" json:"cooldown_seconds"`
+	CallbackUrl       string             `bson:"callback_url,omitempty" json:"callback_url,omitempty"`
+	State             AlertState         `bson:"state" json:"state"`
+	CreatedAt         time.Time          `bson:"created_at" json:"created_at"`
+}
+
+// AlertState is kept on the rule so evaluation survives restarts. A rule fires once
+// when its condition starts to hold and re-arms only after it stops holding.
+type AlertState struct {
+	Firing      bool       `bson:"firing" json:"firing"`
+	LastFiredAt *time.Time `bson:"last_fired_at,omitempty" json:"last_fired_at,omitempty"`
+	LastSlot    uint64     `bson:"last_slot,omitempty" json:"last_slot,omitempty"`
+	// Peaks holds the balances that can still be the window maximum of a drop rule,
+	// in decreasing order of lamports.
+	Peaks []AlertPeak `bson:"peaks,omitempty" json:"-"`
+}
+
+type AlertPeak struct {
+	Lamports uint64    `bson:"lamports"`
+	SeenAt   time.Time `bson:"seen_at"`
+}
+
+type CreateAlertRuleRequest struct {
+	Name   string        `json:"name"`
+	Wallet string        `json:"wallet"`
+	Type   AlertRuleType `json:"type"`
+	// Threshold is a SOL amount used by balance_below and outgoing_transfer rules.
+	Threshold       string  `json:"threshold,omitempty"`
+	DropPercent     float64 `json:"drop_percent,omitempty"`
+	WindowSeconds   int64   `json:"window_seconds,omitempty"`
+	CooldownSeconds int64   `json:"cooldown_seconds,omitempty"`
+	CallbackUrl     string  `json:"callback_url,omitempty"`
+}
+
+type AlertEvent struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	RuleID    primitive.ObjectID `bson:"rule_id" json:"rule_id"`
+	LicenseID primitive.ObjectID `bson:"license_id" json:"-"`
+	RuleName  string             `bson:"rule_name" json:"rule_name"`
+	Type      AlertRuleType      `bson:"type" json:"type"`
+	Wallet    string             `bson:"wallet" json:"wallet"`
+	Lamports  uint64             `bson:"lamports" json:"lamports"`
+	Previous  uint64             `bson:"previous" json:"previous"`
+	Slot      uint64             `bson:"slot" json:"slot"`
+	Message   string             `bson:"message" json:"message"`
+	FiredAt   time.Time          `bson:"fired_at" json:"fired_at"`
+}
+
+type AlertService interface {
+	CreateRule(rule *AlertRule) error
+	ListRules(licenseID primitive.ObjectID) ([]AlertRule, error)
+	DeleteRule(licenseID, id primitive.ObjectID) error
+	// FindRulesForWallet and ListAlertedWallets are used internally by the evaluator and are not license scoped.
+	FindRulesForWallet(wallet string) ([]AlertRule, error)
+	ListAlertedWallets() ([]string, error)
+	UpdateRuleState(id primitive.ObjectID, state AlertState) error
+	CreateEvent(event *AlertEvent) error
+	ListEvents(licenseID primitive.ObjectID, limit int64) ([]AlertEvent, error)
+}
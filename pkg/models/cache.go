@@ -1,12 +1,36 @@
 package models
 
+import (
+	"main/pkg/ratelimit"
+	"time"
+)
+
 type Cache struct {
 	TTLDefaultSeconds int
 }
 
 type CacheImpl interface {
-	GetIpRequestCount(ip string) (int, error)
-	IncrementIpRequestCount(ip string) error
+	AllowRates(keys []string, limits []ratelimit.Limit) ([]ratelimit.Result, error)
+	AcquireConcurrency(id string, ttl time.Duration) (int64, error)
+	ReleaseConcurrency(id string) error
+	ChargeCredits(id string, credits, usageCount, usageLimit int64) (int64, bool, error)
+	GetSpentCredits(id string, usageCount int64) (int64, error)
+	TakeUnflushedCredits() (map[string]int64, error)
+	RestoreUnflushedCredits(id string, credits int64) error
+	SetLicenseID(keyHash, id string, ttl time.Duration) error
+	GetLicenseID(keyHash string) (string, error)
+	SetLicense(id string, license []byte, ttl time.Duration) error
+	GetLicense(id string) ([]byte, error)
+	DeleteLicense(id string) error
+	PublishLicenseInvalidation(id string) error
+	SubscribeLicenseInvalidations(handler func(id string))
 	SetWallet(wallet, balance string) error
 	GetWallet(wallet string) (string, error)
+	SetWalletWithTTL(wallet, balance string, ttl time.Duration) error
+	DeleteWallet(wallet string) error
+	SetPriorityFees(accounts, estimate string) error
+	GetPriorityFees(accounts string) (string, error)
+	SetWalletGroup(id, balance string, ttl time.Duration) error
+	GetWalletGroup(id string) (string, error)
+	DeleteWalletGroup(id string) error
 }
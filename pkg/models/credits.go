@@ -0,0 +1,32 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Operations that cost credits. Routes charged per wallet, token lookup or transaction
+// fetch pay for those instead of OperationRequest.
+const (
+	OperationRequest          = "request"
+	OperationWallet           = "wallet"
+	OperationTokenLookup      = "token_lookup"
+	OperationTransactionFetch = "transaction_fetch"
+)
+
+// ErrInsufficientCredits is returned when a charge would take a license past its usage limit.
+var ErrInsufficientCredits = errors.New("insufficient credits")
+
+// CreditError reports a refused charge: what the request costs and what the license has left.
+type CreditError struct {
+	Cost      int64 `json:"cost"`
+	Remaining int64 `json:"remaining"`
+}
+
+func (e *CreditError) Error() string {
+	return fmt.Sprintf("request costs %d credits, %d remaining", e.Cost, e.Remaining)
+}
+
+func (e *CreditError) Unwrap() error {
+	return ErrInsufficientCredits
+}
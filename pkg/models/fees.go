@@ -0,0 +1,12 @@
+package models
+
+// PriorityFeeEstimate holds compute unit price suggestions in micro-lamports per CU.
+type PriorityFeeEstimate struct {
+	Low      uint64   `json:"low"`
+	Medium   uint64   `json:"medium"`
+	High     uint64   `json:"high"`
+	VeryHigh uint64   `json:"very_high"`
+	Slot     uint64   `json:"slot"`
+	Samples  int      `json:"samples"`
+	Accounts []string `json:"accounts,omitempty"`
+}
@@ -0,0 +1,70 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type WalletGroups struct {
+	Collection *mongo.Collection
+}
+
+type WalletGroup struct {
+	ID        primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	LicenseID primitive.ObjectID  `bson:"license_id" json:"-"`
+	Name      string              `bson:"name" json:"name"`
+	Members   []WalletGroupMember `bson:"members" json:"members"`
+	CreatedAt time.Time           `bson:"created_at" json:"created_at"`
+	UpdatedAt time.Time           `bson:"updated_at" json:"updated_at"`
+}
+
+type WalletGroupMember struct {
+	Address string `bson:"address" json:"address"`
+	Label   string `bson:"label,omitempty" json:"label,omitempty"`
+}
+
+type WalletGroupRequest struct {
+	Name    string              `json:"name"`
+	Members []WalletGroupMember `json:"members"`
+}
+
+// TokenTotal is an exact token amount. Amount is the integer amount in base units,
+// UiAmount the same value as a decimal string.
+type TokenTotal struct {
+	Mint     string `json:"mint"`
+	Symbol   string `json:"symbol,omitempty"`
+	Amount   string `json:"amount"`
+	Decimals uint8  `json:"decimals"`
+	UiAmount string `json:"ui_amount"`
+}
+
+type WalletGroupMemberBalance struct {
+	Address  string       `json:"address"`
+	Label    string       `json:"label,omitempty"`
+	Lamports uint64       `json:"lamports"`
+	Sol      string       `json:"sol"`
+	Tokens   []TokenTotal `json:"tokens"`
+}
+
+type WalletGroupBalance struct {
+	GroupID   string                     `json:"group_id"`
+	Name      string                     `json:"name"`
+	Lamports  string                     `json:"lamports"`
+	Sol       string                     `json:"sol"`
+	Tokens    []TokenTotal               `json:"tokens"`
+	Members   []WalletGroupMemberBalance `json:"members"`
+	Slot      uint64                     `json:"slot"`
+	UpdatedAt time.Time                  `json:"updated_at"`
+	Cache     string                     `json:"cache"`
+}
+
+// WalletGroupService methods are scoped to the owning license.
+type WalletGroupService interface {
+	CreateGroup(group *WalletGroup) error
+	ListGroups(licenseID primitive.ObjectID) ([]WalletGroup, error)
+	GetGroup(licenseID, id primitive.ObjectID) (*WalletGroup, error)
+	UpdateGroup(group *WalletGroup) error
+	DeleteGroup(licenseID, id primitive.ObjectID) error
+}
@@ -0,0 +1,45 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// BalanceHistory is a time-series collection with the wallet as meta field.
+type BalanceHistory struct {
+	Collection *mongo.Collection
+}
+
+type BalancePoint struct {
+	Wallet     string    `bson:"wallet" json:"-"`
+	Lamports   uint64    `bson:"lamports" json:"lamports"`
+	Slot       uint64    `bson:"slot" json:"slot"`
+	ObservedAt time.Time `bson:"observed_at" json:"observed_at"`
+}
+
+// BalanceBucket summarizes the observations of one interval. Last is the balance
+// observed latest within the interval.
+type BalanceBucket struct {
+	Time    time.Time `bson:"_id" json:"time"`
+	Min     uint64    `bson:"min" json:"min"`
+	Max     uint64    `bson:"max" json:"max"`
+	Last    uint64    `bson:"last" json:"last"`
+	Samples int64     `bson:"samples" json:"samples"`
+}
+
+type BalanceHistoryResponse struct {
+	Wallet   string          `json:"wallet"`
+	From     time.Time       `json:"from"`
+	To       time.Time       `json:"to"`
+	Interval string          `json:"interval,omitempty"`
+	Points   []BalancePoint  `json:"points,omitempty"`
+	Buckets  []BalanceBucket `json:"buckets,omitempty"`
+}
+
+type BalanceHistoryService interface {
+	EnsureCollection(retention time.Duration) error
+	InsertPoints(points []BalancePoint) error
+	ListPoints(wallet string, from, to time.Time, limit int64) ([]BalancePoint, error)
+	ListBuckets(wallet string, from, to time.Time, interval time.Duration) ([]BalanceBucket, error)
+}
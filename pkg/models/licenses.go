@@ -1,34 +1,204 @@
 package models
 
 import (
+	"errors"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 )
 
+// ErrLicenseKeyIssued is returned when a key is requested for a license that already has one.
+var ErrLicenseKeyIssued = errors.New("license key already issued")
+
 type LicenseKey struct {
 	Collection *mongo.Collection
+	// Migrations records the one-time license migrations that were applied.
+	Migrations *mongo.Collection
 }
 
+// License is an API key with its limits. UsageCount and UsageLimit are in credits, which
+// every request spends according to CREDIT_COSTS.
 type License struct {
-	ID         primitive.ObjectID `bson:"_id,omitempty" json:"id"`
-	Key        string             `bson:"key" json:"key"`
-	Name       string             `bson:"name" json:"name"`
-	CreatedAt  time.Time          `bson:"created_at" json:"created_at"`
-	ExpiresAt  *time.Time         `bson:"expires_at,omitempty" json:"expires_at,omitempty"`
-	UsageCount int64              `bson:"usage_count" json:"usage_count"`
-	UsageLimit *int64             `bson:"usage_limit,omitempty" json:"usage_limit,omitempty"`
+	ID primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	// Key is only set on the response that issues it, the database holds the digest.
+	Key        string     `bson:"-" json:"key,omitempty"`
+	KeyHash    string     `bson:"key_hash,omitempty" json:"-"`
+	KeyPrefix  string     `bson:"key_prefix,omitempty" json:"key_prefix,omitempty"`
+	Name       string     `bson:"name" json:"name"`
+	CreatedAt  time.Time  `bson:"created_at" json:"created_at"`
+	ExpiresAt  *time.Time `bson:"expires_at,omitempty" json:"expires_at,omitempty"`
+	UsageCount int64      `bson:"usage_count" json:"usage_count"`
+	UsageLimit *int64     `bson:"usage_limit,omitempty" json:"usage_limit,omitempty"`
+	IsActive   bool       `bson:"is_active" json:"is_active"`
+	Scopes     []string   `bson:"scopes" json:"scopes"`
+	// Plan is the ID of the license's plan, empty means DEFAULT_PLAN.
+	Plan string `bson:"plan,omitempty" json:"plan,omitempty"`
+	// RetiredKeys are keys replaced by a rotation that are accepted until they expire.
+	RetiredKeys []RetiredKey `bson:"retired_keys,omitempty" json:"retired_keys,omitempty"`
+	// RevokedAt is set once a license is revoked, a revoked license cannot be reactivated.
+	RevokedAt *time.Time `bson:"revoked_at,omitempty" json:"revoked_at,omitempty"`
+	// TopUps are the payment signatures the license was topped up with, so a retried
+	// top-up is credited once.
+	TopUps []string `bson:"top_ups,omitempty" json:"-"`
+}
+
+// HasScope reports whether the license may call routes that require scope.
+func (l *License) HasScope(scope string) bool {
+	for _, granted := range l.Scopes {
+		if granted == scope {
+			return true
+		}
+	}
+	return false
 }
 
 type CreateLicenseRequest struct {
 	Name       string    `json:"name"`
 	Expiry     time.Time `json:"expiry,omitempty"`
 	UsageLimit int64     `json:"usage_limit,omitempty"`
+	// Scopes defaults to every endpoint scope on the configured network.
+	Scopes []string `json:"scopes,omitempty"`
+	Plan   string   `json:"plan,omitempty"`
+}
+
+// RetiredKey is a key replaced by a rotation. It shares the license's usage counters and
+// limits and keeps working until ExpiresAt.
+type RetiredKey struct {
+	KeyHash   string    `bson:"key_hash" json:"-"`
+	KeyPrefix string    `bson:"key_prefix" json:"key_prefix"`
+	RetiredAt time.Time `bson:"retired_at" json:"retired_at"`
+	ExpiresAt time.Time `bson:"expires_at" json:"expires_at"`
+}
+
+type LicenseKeyRotations struct {
+	Collection *mongo.Collection
+}
+
+const (
+	KeyRotatedByAdmin   = "admin"
+	KeyRotatedByLicense = "license"
+)
+
+// KeyRotation records one key rotation. Key is only set on the response to the rotation.
+type KeyRotation struct {
+	ID           primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	LicenseID    primitive.ObjectID `bson:"license_id" json:"license_id"`
+	Key          string             `bson:"-" json:"key,omitempty"`
+	KeyPrefix    string             `bson:"key_prefix" json:"key_prefix"`
+	OldKeyPrefix string             `bson:"old_key_prefix,omitempty" json:"old_key_prefix,omitempty"`
+	// OldKeyExpiresAt is the end of the grace period, unset when the old key stopped working at once.
+	OldKeyExpiresAt *time.Time `bson:"old_key_expires_at,omitempty" json:"old_key_expires_at,omitempty"`
+	RotatedBy       string     `bson:"rotated_by" json:"rotated_by"`
+	RotatedAt       time.Time  `bson:"rotated_at" json:"rotated_at"`
+}
+
+type RotateLicenseKeyRequest struct {
+	// GracePeriod is how long the old key keeps working as a Go duration, e.g. "48h".
+	// Empty uses LICENSE_KEY_ROTATION_GRACE, "0s" rejects the old key at once.
+	GracePeriod string `json:"grace_period,omitempty"`
+}
+
+type LicenseKeyRotationService interface {
+	RecordRotation(rotation *KeyRotation) error
+	ListRotations(licenseID primitive.ObjectID) ([]KeyRotation, error)
+}
+
+// UpdateLicenseRequest patches a license. Absent fields are left as they are, a null
+// expires_at or usage_limit removes the expiry or the limit.
+type UpdateLicenseRequest struct {
+	Name       *string             `json:"name,omitempty"`
+	ExpiresAt  Nullable[time.Time] `json:"expires_at"`
+	UsageLimit Nullable[int64]     `json:"usage_limit"`
+	IsActive   *bool               `json:"is_active,omitempty"`
+	// Scopes replaces the license's scopes when set.
+	Scopes *[]string `json:"scopes,omitempty"`
+	Plan   *string   `json:"plan,omitempty"`
+}
+
+type LicenseFilter struct {
+	Active *bool `form:"active"`
+	// Name matches licenses whose name contains it, case insensitively.
+	Name    string `form:"name"`
+	Expired *bool  `form:"expired"`
+	Page    int    `form:"page"`
+	Limit   int    `form:"limit"`
+}
+
+type LicenseList struct {
+	Licenses []License `json:"licenses"`
+	Total    int64     `json:"total"`
+	Page     int       `json:"page"`
+	Limit    int       `json:"limit"`
 }
 
 type LicenseKeyService interface {
-	CreateLicense(name string, expiry *time.Time, limit *int64) (*License, error)
-	ValidateLicense(key string) (*License, error)
-	IncrementUsage(key string) error
+	CreateLicense(license *License, issueKey bool) (*License, error)
+	// GrantMissingScopes sets scopes on every license stored before licenses had scopes.
+	GrantMissingScopes(scopes []string) (int64, error)
+	// GrantScopesOnce adds scopes to every license that has scopes, unless the migration
+	// called name was already applied.
+	GrantScopesOnce(name string, scopes []string) (int64, error)
+	IssueLicenseKey(id primitive.ObjectID) (string, error)
+	// RotateLicenseKey replaces the key and keeps the old one working for grace. It returns
+	// the new plaintext key and the license as it was before the rotation.
+	RotateLicenseKey(id primitive.ObjectID, grace time.Duration) (string, *License, error)
+	// ChargeUsage adds credits to the usage count unless that would exceed the usage limit,
+	// in which case it fails with ErrInsufficientCredits.
+	ChargeUsage(id primitive.ObjectID, credits int64) (*License, error)
+	// AddUsage adds credits to the usage count without checking the usage limit.
+	AddUsage(id primitive.ObjectID, credits int64) error
+	GetLicenseByKey(key string) (*License, error)
+	GetLicenseByID(id primitive.ObjectID) (*License, error)
+	GetLicensesByID(ids []primitive.ObjectID) ([]License, error)
+	// TopUpLicense extends the license for the payment signature, unless it already was.
+	TopUpLicense(id primitive.ObjectID, signature string, usage int64, extension time.Duration) (*License, error)
+	ListLicenses(filter LicenseFilter) ([]License, int64, error)
+	UpdateLicense(id primitive.ObjectID, request UpdateLicenseRequest) (*License, error)
+	RevokeLicense(id primitive.ObjectID) (*License, error)
+}
+
+// LicensePayments records every payment signature that has been credited to a license.
+type LicensePayments struct {
+	Collection *mongo.Collection
+}
+
+// AppliedPayment is recorded before the signature is credited and marked applied after.
+// A signature recorded but not applied is credited again on the next poll.
+type AppliedPayment struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Signature string             `bson:"signature" json:"signature"`
+	Reference string             `bson:"reference" json:"reference"`
+	// LicenseID is the license credited. The ID of a purchased license is chosen when the
+	// signature is recorded, so a retry does not create a second license.
+	LicenseID  primitive.ObjectID `bson:"license_id,omitempty" json:"license_id,omitempty"`
+	RecordedAt time.Time          `bson:"recorded_at,omitempty" json:"recorded_at,omitempty"`
+	AppliedAt  *time.Time         `bson:"applied_at,omitempty" json:"applied_at,omitempty"`
+}
+
+type PurchaseLicenseRequest struct {
+	Name string `json:"name"`
+	// Currency is SOL or USDC.
+	Currency    string `json:"currency"`
+	CallbackURL string `json:"callback_url,omitempty"`
+}
+
+type TopUpLicenseRequest struct {
+	LicenseKey  string `json:"license_key"`
+	Currency    string `json:"currency"`
+	CallbackURL string `json:"callback_url,omitempty"`
+}
+
+type LicensePurchase struct {
+	Payment *PaymentRequest `json:"payment"`
+	// ClaimToken is only returned once and is required to read the purchased license.
+	ClaimToken string   `json:"claim_token,omitempty"`
+	License    *License `json:"license,omitempty"`
+}
+
+type LicensePaymentService interface {
+	// RecordAppliedPayment stores the payment unless its signature is recorded already and
+	// returns the stored record, which can belong to another reference.
+	RecordAppliedPayment(payment *AppliedPayment) (*AppliedPayment, error)
+	MarkPaymentApplied(signature string) error
 }
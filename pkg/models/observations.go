@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// BalanceObservation is a balance seen by the queue, the watchlist refresher or an
+// account subscription.
+type BalanceObservation struct {
+	Wallet   string
+	Lamports uint64
+	// Previous is the last balance observed for the wallet, nil the first time it is seen.
+	Previous   *uint64
+	Slot       uint64
+	ObservedAt time.Time
+}
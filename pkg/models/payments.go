@@ -0,0 +1,75 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type PaymentStatus string
+
+const (
+	PaymentStatusPending PaymentStatus = "pending"
+	PaymentStatusPaid    PaymentStatus = "paid"
+	PaymentStatusExpired PaymentStatus = "expired"
+)
+
+// PaymentPurpose marks payment requests whose settlement triggers an action beyond the callback.
+type PaymentPurpose string
+
+const (
+	PaymentPurposeLicensePurchase PaymentPurpose = "license_purchase"
+	PaymentPurposeLicenseTopUp    PaymentPurpose = "license_top_up"
+)
+
+type Payments struct {
+	Collection *mongo.Collection
+}
+
+type PaymentRequest struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Reference string             `bson:"reference" json:"reference"`
+	URL       string             `bson:"url" json:"url"`
+	Recipient string             `bson:"recipient" json:"recipient"`
+	// Amount is the decimal amount shown to the payer, AmountBaseUnits is what is verified on-chain.
+	Amount          string        `bson:"amount" json:"amount"`
+	AmountBaseUnits uint64        `bson:"amount_base_units" json:"amount_base_units"`
+	Mint            string        `bson:"mint,omitempty" json:"mint,omitempty"`
+	Label           string        `bson:"label,omitempty" json:"label,omitempty"`
+	Message         string        `bson:"message,omitempty" json:"message,omitempty"`
+	Memo            string        `bson:"memo,omitempty" json:"memo,omitempty"`
+	CallbackURL     string        `bson:"callback_url,omitempty" json:"callback_url,omitempty"`
+	Status          PaymentStatus `bson:"status" json:"status"`
+	Signature       string        `bson:"signature,omitempty" json:"signature,omitempty"`
+	Slot            uint64        `bson:"slot,omitempty" json:"slot,omitempty"`
+	CreatedAt       time.Time     `bson:"created_at" json:"created_at"`
+	ExpiresAt       time.Time     `bson:"expires_at" json:"expires_at"`
+	PaidAt          *time.Time    `bson:"paid_at,omitempty" json:"paid_at,omitempty"`
+	// OwnerID is the license that created the request, license payments have none.
+	OwnerID primitive.ObjectID `bson:"owner_id,omitempty" json:"-"`
+
+	Purpose PaymentPurpose `bson:"purpose,omitempty" json:"purpose,omitempty"`
+	// LicenseID is the license being topped up, or the one issued once a purchase is paid.
+	LicenseID      *primitive.ObjectID `bson:"license_id,omitempty" json:"-"`
+	LicenseName    string              `bson:"license_name,omitempty" json:"-"`
+	ClaimTokenHash string              `bson:"claim_token_hash,omitempty" json:"-"`
+}
+
+type CreatePaymentRequest struct {
+	Recipient        string `json:"recipient"`
+	Amount           string `json:"amount"`
+	Mint             string `json:"mint,omitempty"`
+	Label            string `json:"label,omitempty"`
+	Message          string `json:"message,omitempty"`
+	Memo             string `json:"memo,omitempty"`
+	CallbackURL      string `json:"callback_url,omitempty"`
+	ExpiresInSeconds int64  `json:"expires_in_seconds,omitempty"`
+}
+
+type PaymentService interface {
+	CreatePayment(payment *PaymentRequest) error
+	GetPayment(reference string) (*PaymentRequest, error)
+	UpdatePayment(payment *PaymentRequest) error
+	ListPendingPayments() ([]PaymentRequest, error)
+}
@@ -0,0 +1,42 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type Plans struct {
+	Collection *mongo.Collection
+}
+
+// Plan is the tier a license is on. Zero limits are unlimited.
+type Plan struct {
+	ID                string `bson:"_id" json:"id"`
+	Name              string `bson:"name" json:"name"`
+	RequestsPerSecond int64  `bson:"requests_per_second" json:"requests_per_second"`
+	RequestsPerMinute int64  `bson:"requests_per_minute" json:"requests_per_minute"`
+	// RequestsPerDay is a token bucket refilled over 24 hours rather than a calendar-day
+	// quota, a drained quota comes back gradually.
+	RequestsPerDay int64 `bson:"requests_per_day" json:"requests_per_day"`
+	// Burst is how many requests a license may make within a single second, it defaults
+	// to RequestsPerSecond.
+	Burst                int64 `bson:"burst" json:"burst"`
+	MaxWalletsPerRequest int   `bson:"max_wallets_per_request" json:"max_wallets_per_request"`
+	MaxConcurrency       int64 `bson:"max_concurrency" json:"max_concurrency"`
+	// MaxWatchedWallets bounds the distinct wallets across the license's watchlists and
+	// alert rules, which are all refreshed in the background.
+	MaxWatchedWallets int       `bson:"max_watched_wallets" json:"max_watched_wallets"`
+	UpdatedAt         time.Time `bson:"updated_at" json:"updated_at"`
+}
+
+type PlanService interface {
+	GetPlan(id string) (*Plan, error)
+	ListPlans() ([]Plan, error)
+	SavePlan(plan *Plan) error
+	// CreatePlanIfMissing stores the plan unless a plan with its ID exists.
+	CreatePlanIfMissing(plan *Plan) error
+	// SetMissingWatchedWalletLimit sets max_watched_wallets on plans stored before it existed.
+	SetMissingWatchedWalletLimit(limit int) (int64, error)
+	DeletePlan(id string) error
+}
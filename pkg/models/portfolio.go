@@ -0,0 +1,34 @@
+package models
+
+import "time"
+
+const (
+	PortfolioAssetSol    = "SOL"
+	PortfolioAssetStaked = "staked SOL"
+	PortfolioAssetToken  = "token"
+)
+
+// PortfolioAsset is one holding of a wallet. Amount is in base units, prices and values
+// are USD decimal strings. PriceError explains why an asset has no value.
+type PortfolioAsset struct {
+	Asset            string     `json:"asset"`
+	Mint             string     `json:"mint"`
+	Symbol           string     `json:"symbol,omitempty"`
+	Amount           string     `json:"amount"`
+	Decimals         uint8      `json:"decimals"`
+	UiAmount         string     `json:"ui_amount"`
+	PriceUsd         string     `json:"price_usd,omitempty"`
+	ConfidenceUsd    string     `json:"confidence_usd,omitempty"`
+	PricePublishedAt *time.Time `json:"price_published_at,omitempty"`
+	ValueUsd         string     `json:"value_usd,omitempty"`
+	PriceError       string     `json:"price_error,omitempty"`
+}
+
+// Portfolio totals the valued assets. Complete is false when any asset could not be priced.
+type Portfolio struct {
+	Wallet    string           `json:"wallet"`
+	Assets    []PortfolioAsset `json:"assets"`
+	TotalUsd  string           `json:"total_usd"`
+	Complete  bool             `json:"complete"`
+	PriceSlot uint64           `json:"price_slot"`
+}
@@ -1,7 +1,31 @@
 package models
 
+import "encoding/json"
+
 type GenericResponse[T any] struct {
 	Object  T      `json:"object"`
 	Error   string `json:"error"`
 	Success bool   `json:"success"`
 }
+
+// Nullable tells an absent JSON field apart from an explicit null, which PATCH requests
+// use to clear a value.
+type Nullable[T any] struct {
+	Set   bool
+	Value *T
+}
+
+func (n *Nullable[T]) UnmarshalJSON(data []byte) error {
+	n.Set = true
+	if string(data) == "null" {
+		n.Value = nil
+		return nil
+	}
+
+	var value T
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	n.Value = &value
+	return nil
+}
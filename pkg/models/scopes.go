@@ -0,0 +1,81 @@
+package models
+
+import "strings"
+
+// License scopes. A route requires one scope and a license may only call routes whose
+// scope it carries.
+const (
+	ScopeBalanceRead    = "balance:read"
+	ScopeTokensRead     = "tokens:read"
+	ScopeSnapshotsWrite = "snapshots:write"
+	ScopeTxRead         = "tx:read"
+	ScopeTxSend         = "tx:send"
+	ScopePaymentsRead   = "payments:read"
+	ScopePaymentsWrite  = "payments:write"
+	// The watchlist scopes also cover wallet groups, which are managed like watchlists.
+	ScopeWatchlistsRead  = "watchlists:read"
+	ScopeWatchlistsWrite = "watchlists:write"
+	ScopeWebhooksRead    = "webhooks:read"
+	ScopeWebhooksWrite   = "webhooks:write"
+	ScopeAlertsRead      = "alerts:read"
+	ScopeAlertsWrite     = "alerts:write"
+	// The license scopes cover the calling license's own key rotation and its history.
+	ScopeLicenseRead  = "license:read"
+	ScopeLicenseWrite = "license:write"
+
+	// NetworkScopePrefix prefixes the scope that grants access to a cluster, e.g. network:devnet.
+	NetworkScopePrefix = "network:"
+)
+
+// ApiScopes are all endpoint scopes, the read and write access a full license gets.
+var ApiScopes = []string{
+	ScopeBalanceRead,
+	ScopeTokensRead,
+	ScopeSnapshotsWrite,
+	ScopeTxRead,
+	ScopeTxSend,
+	ScopePaymentsRead,
+	ScopePaymentsWrite,
+	ScopeWatchlistsRead,
+	ScopeWatchlistsWrite,
+	ScopeWebhooksRead,
+	ScopeWebhooksWrite,
+	ScopeAlertsRead,
+	ScopeAlertsWrite,
+	ScopeLicenseRead,
+	ScopeLicenseWrite,
+}
+
+// LicenseScopes were added after licenses got scopes. Licenses stored before then are
+// granted them once, as they could already rotate their key.
+var LicenseScopes = []string{ScopeLicenseRead, ScopeLicenseWrite}
+
+// Networks are the clusters a network scope can name.
+var Networks = []string{"mainnet", "devnet", "testnet"}
+
+func NetworkScope(network string) string {
+	return NetworkScopePrefix + network
+}
+
+// IsKnownScope reports whether scope is an endpoint scope or names a known network.
+func IsKnownScope(scope string) bool {
+	if network, ok := strings.CutPrefix(scope, NetworkScopePrefix); ok {
+		for _, known := range Networks {
+			if network == known {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, known := range ApiScopes {
+		if scope == known {
+			return true
+		}
+	}
+	return false
+}
+
+type ScopeError struct {
+	MissingScope string `json:"missing_scope"`
+}
@@ -0,0 +1,75 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type SnapshotStatus string
+
+const (
+	SnapshotStatusPending   SnapshotStatus = "pending"
+	SnapshotStatusRunning   SnapshotStatus = "running"
+	SnapshotStatusCompleted SnapshotStatus = "completed"
+	SnapshotStatusFailed    SnapshotStatus = "failed"
+)
+
+// Snapshots stores the jobs in Collection and one document per holder in Holders.
+type Snapshots struct {
+	Collection *mongo.Collection
+	Holders    *mongo.Collection
+}
+
+// Snapshot is a holder snapshot job. Amounts are base-unit strings. Digest is the
+// SHA-256 of the "owner,amount\n" lines of every holder ordered by owner, so an export
+// can be checked against the snapshot it came from.
+type Snapshot struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	LicenseID   primitive.ObjectID `bson:"license_id" json:"-"`
+	Mint        string             `bson:"mint" json:"mint"`
+	Program     string             `bson:"program,omitempty" json:"program,omitempty"`
+	Decimals    uint8              `bson:"decimals" json:"decimals"`
+	MinAmount   string             `bson:"min_amount" json:"min_amount"`
+	Status      SnapshotStatus     `bson:"status" json:"status"`
+	Error       string             `bson:"error,omitempty" json:"error,omitempty"`
+	Slot        uint64             `bson:"slot,omitempty" json:"slot,omitempty"`
+	Accounts    int64              `bson:"accounts" json:"accounts"`
+	Holders     int64              `bson:"holders" json:"holders"`
+	TotalAmount string             `bson:"total_amount,omitempty" json:"total_amount,omitempty"`
+	Digest      string             `bson:"digest,omitempty" json:"digest,omitempty"`
+	CreatedAt   time.Time          `bson:"created_at" json:"created_at"`
+	StartedAt   *time.Time         `bson:"started_at,omitempty" json:"started_at,omitempty"`
+	CompletedAt *time.Time         `bson:"completed_at,omitempty" json:"completed_at,omitempty"`
+}
+
+type SnapshotHolder struct {
+	SnapshotID primitive.ObjectID `bson:"snapshot_id" json:"-"`
+	Owner      string             `bson:"owner" json:"owner"`
+	Amount     string             `bson:"amount" json:"amount"`
+	UiAmount   string             `bson:"ui_amount" json:"ui_amount"`
+	Accounts   int                `bson:"accounts" json:"accounts"`
+	CreatedAt  time.Time          `bson:"created_at" json:"-"`
+}
+
+type CreateSnapshotRequest struct {
+	Mint string `json:"mint"`
+	// MinAmount is a decimal token amount, holders below it are left out.
+	MinAmount string `json:"min_amount,omitempty"`
+}
+
+type SnapshotService interface {
+	EnsureIndexes(retention time.Duration) error
+	// CreateSnapshot fails with a duplicate key error while the license has a pending job.
+	CreateSnapshot(snapshot *Snapshot) error
+	GetSnapshot(licenseID, id primitive.ObjectID) (*Snapshot, error)
+	ListSnapshots(licenseID primitive.ObjectID) ([]Snapshot, error)
+	UpdateSnapshot(snapshot *Snapshot) error
+	// ListUnfinishedSnapshots returns pending and running jobs across licenses, to resume them.
+	ListUnfinishedSnapshots() ([]Snapshot, error)
+	DeleteHolders(snapshotID primitive.ObjectID) error
+	InsertHolders(holders []SnapshotHolder) error
+	// EachHolder calls fn for every holder of the snapshot in owner order.
+	EachHolder(snapshotID primitive.ObjectID, fn func(holder SnapshotHolder) error) error
+}
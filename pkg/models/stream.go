@@ -0,0 +1,18 @@
+package models
+
+type StreamRequest struct {
+	// Action is subscribe or unsubscribe.
+	Action  string   `json:"action"`
+	Wallets []string `json:"wallets"`
+}
+
+type StreamMessage struct {
+	// Type is balance, subscribed, unsubscribed or error.
+	Type     string   `json:"type"`
+	Wallet   string   `json:"wallet,omitempty"`
+	Lamports uint64   `json:"lamports,omitempty"`
+	Balance  string   `json:"balance,omitempty"`
+	Slot     uint64   `json:"slot,omitempty"`
+	Wallets  []string `json:"wallets,omitempty"`
+	Error    string   `json:"error,omitempty"`
+}
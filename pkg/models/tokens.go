@@ -0,0 +1,41 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type Tokens struct {
+	Collection *mongo.Collection
+}
+
+// TokenInfo is a decoded mint with its metadata. Supply is a base-unit string because a
+// u64 supply does not fit BSON's signed integers.
+type TokenInfo struct {
+	Mint            string           `bson:"_id" json:"mint"`
+	Program         string           `bson:"program" json:"program"`
+	Supply          string           `bson:"supply" json:"supply"`
+	Decimals        uint8            `bson:"decimals" json:"decimals"`
+	MintAuthority   string           `bson:"mint_authority,omitempty" json:"mint_authority,omitempty"`
+	FreezeAuthority string           `bson:"freeze_authority,omitempty" json:"freeze_authority,omitempty"`
+	Extensions      []TokenExtension `bson:"extensions,omitempty" json:"extensions"`
+	Name            string           `bson:"name,omitempty" json:"name,omitempty"`
+	Symbol          string           `bson:"symbol,omitempty" json:"symbol,omitempty"`
+	MetadataUri     string           `bson:"metadata_uri,omitempty" json:"metadata_uri,omitempty"`
+	LogoUri         string           `bson:"logo_uri,omitempty" json:"logo_uri,omitempty"`
+	MetadataSource  string           `bson:"metadata_source,omitempty" json:"metadata_source,omitempty"`
+	Slot            uint64           `bson:"slot" json:"slot"`
+	RefreshedAt     time.Time        `bson:"refreshed_at" json:"refreshed_at"`
+}
+
+type TokenExtension struct {
+	Name   string            `bson:"name" json:"name"`
+	Fields map[string]string `bson:"fields,omitempty" json:"fields,omitempty"`
+}
+
+type TokenService interface {
+	GetToken(mint string) (*TokenInfo, error)
+	GetTokens(mints []string) ([]TokenInfo, error)
+	SaveToken(token *TokenInfo) error
+}
@@ -0,0 +1,65 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type TransactionStatus string
+
+const (
+	TransactionStatusPending   TransactionStatus = "pending"
+	TransactionStatusProcessed TransactionStatus = "processed"
+	TransactionStatusConfirmed TransactionStatus = "confirmed"
+	TransactionStatusFinalized TransactionStatus = "finalized"
+	TransactionStatusFailed    TransactionStatus = "failed"
+	TransactionStatusExpired   TransactionStatus = "expired"
+)
+
+// Done reports whether the status is terminal and the transaction no longer needs tracking.
+func (s TransactionStatus) Done() bool {
+	return s == TransactionStatusFinalized || s == TransactionStatusFailed || s == TransactionStatusExpired
+}
+
+type Transactions struct {
+	Collection *mongo.Collection
+}
+
+type TransactionRecord struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Signature  string             `bson:"signature" json:"signature"`
+	RawTx      string             `bson:"raw_tx" json:"-"`
+	Blockhash  string             `bson:"blockhash" json:"blockhash"`
+	Status     TransactionStatus  `bson:"status" json:"status"`
+	Slot       uint64             `bson:"slot,omitempty" json:"slot,omitempty"`
+	Error      string             `bson:"error,omitempty" json:"error,omitempty"`
+	Broadcasts int                `bson:"broadcasts" json:"broadcasts"`
+	WebhookURL string             `bson:"webhook_url,omitempty" json:"webhook_url,omitempty"`
+	// OwnerID is the license that sent the transaction.
+	OwnerID     primitive.ObjectID `bson:"owner_id,omitempty" json:"-"`
+	CreatedAt   time.Time          `bson:"created_at" json:"created_at"`
+	UpdatedAt   time.Time          `bson:"updated_at" json:"updated_at"`
+	ProcessedAt *time.Time         `bson:"processed_at,omitempty" json:"processed_at,omitempty"`
+	ConfirmedAt *time.Time         `bson:"confirmed_at,omitempty" json:"confirmed_at,omitempty"`
+	FinalizedAt *time.Time         `bson:"finalized_at,omitempty" json:"finalized_at,omitempty"`
+}
+
+type SendTransactionRequest struct {
+	// Transaction is the fully signed transaction, base64 encoded.
+	Transaction   string `json:"transaction"`
+	SkipPreflight bool   `json:"skip_preflight,omitempty"`
+	WebhookURL    string `json:"webhook_url,omitempty"`
+}
+
+type TransactionService interface {
+	CreateTransaction(record *TransactionRecord) error
+	// GetTransaction returns the transaction only when it was sent by the owner.
+	GetTransaction(ownerID primitive.ObjectID, signature string) (*TransactionRecord, error)
+	UpdateTransaction(record *TransactionRecord) error
+	// ExpireTransaction marks the transaction expired unless it was finalized or failed
+	// in the meantime, and reports whether it did.
+	ExpireTransaction(record *TransactionRecord) (bool, error)
+	ListPendingTransactions() ([]TransactionRecord, error)
+}
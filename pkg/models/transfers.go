@@ -0,0 +1,24 @@
+package models
+
+type BuildTransferRequest struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	// Amount is a decimal string in SOL or whole tokens, e.g. "0.5".
+	Amount string `json:"amount"`
+	// Mint selects an SPL token transfer, SOL is transferred when empty.
+	Mint string `json:"mint,omitempty"`
+	Memo string `json:"memo,omitempty"`
+	// Priority is one of low, medium, high or very_high. Defaults to medium.
+	Priority         string `json:"priority,omitempty"`
+	ComputeUnitLimit uint32 `json:"compute_unit_limit,omitempty"`
+}
+
+type BuildTransferResponse struct {
+	Transaction          string `json:"transaction"`
+	Blockhash            string `json:"blockhash"`
+	LastValidBlockHeight uint64 `json:"last_valid_block_height"`
+	Amount               uint64 `json:"amount"`
+	ComputeUnitLimit     uint32 `json:"compute_unit_limit"`
+	ComputeUnitPrice     uint64 `json:"compute_unit_price"`
+	CreatesTokenAccount  bool   `json:"creates_token_account"`
+}
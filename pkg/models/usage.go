@@ -0,0 +1,108 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type LicenseUsage struct {
+	Collection *mongo.Collection
+}
+
+// UsageBucket counts the requests of one license to one endpoint on one UTC day.
+// Endpoint is the method and route, e.g. "POST /api/get-balance". Errors counts the
+// requests answered with a 4xx or 5xx status.
+type UsageBucket struct {
+	LicenseID primitive.ObjectID `bson:"license_id" json:"-"`
+	Day       time.Time          `bson:"day" json:"day"`
+	Endpoint  string             `bson:"endpoint" json:"endpoint"`
+	Requests  int64              `bson:"requests" json:"requests"`
+	Errors    int64              `bson:"errors" json:"errors"`
+	Credits   int64              `bson:"credits" json:"credits"`
+}
+
+// DailyUsage sums a license's buckets of one day, Date is formatted as 2006-01-02.
+type DailyUsage struct {
+	Date     string `bson:"_id" json:"date"`
+	Requests int64  `bson:"requests" json:"requests"`
+	Errors   int64  `bson:"errors" json:"errors"`
+	Credits  int64  `bson:"credits" json:"credits"`
+}
+
+type EndpointUsage struct {
+	Endpoint string `bson:"_id" json:"endpoint"`
+	Requests int64  `bson:"requests" json:"requests"`
+	Errors   int64  `bson:"errors" json:"errors"`
+	Credits  int64  `bson:"credits" json:"credits"`
+}
+
+// UsageReport is a license's usage from From to To, both inclusive, by endpoint.
+type UsageReport struct {
+	From      string          `json:"from"`
+	To        string          `json:"to"`
+	Requests  int64           `json:"requests"`
+	Errors    int64           `json:"errors"`
+	Credits   int64           `json:"credits"`
+	Endpoints []EndpointUsage `json:"endpoints"`
+}
+
+// LicenseUsageTotal sums a license's usage over a period.
+type LicenseUsageTotal struct {
+	LicenseID primitive.ObjectID `bson:"_id"`
+	Requests  int64              `bson:"requests"`
+	Errors    int64              `bson:"errors"`
+	Credits   int64              `bson:"credits"`
+}
+
+// UsageStatement is a license's usage in one calendar month. With BILLING_CREDIT_PRICE
+// configured it is an invoice and carries the amount due.
+type UsageStatement struct {
+	LicenseID   primitive.ObjectID `json:"license_id"`
+	LicenseName string             `json:"license_name"`
+	Plan        string             `json:"plan"`
+	Month       string             `json:"month"`
+	Requests    int64              `json:"requests"`
+	Errors      int64              `json:"errors"`
+	Credits     int64              `json:"credits"`
+	Currency    string             `json:"currency,omitempty"`
+	UnitPrice   string             `json:"unit_price,omitempty"`
+	Amount      string             `json:"amount,omitempty"`
+	// Endpoints breaks the statement of a single license down, it is left out of the
+	// statements of every license.
+	Endpoints []EndpointUsage `json:"endpoints,omitempty"`
+}
+
+// RateLimitBudget is what is left of one of the plan's rate limits. Reset is the number
+// of seconds until the full limit is available again.
+type RateLimitBudget struct {
+	Window    string `json:"window"`
+	Limit     int64  `json:"limit"`
+	Remaining int64  `json:"remaining"`
+	Reset     int64  `json:"reset"`
+}
+
+// Account is what GET /api/me tells a license holder about their license.
+type Account struct {
+	Name       string     `json:"name"`
+	KeyPrefix  string     `json:"key_prefix,omitempty"`
+	Plan       *Plan      `json:"plan"`
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	UsageCount int64      `json:"usage_count"`
+	UsageLimit *int64     `json:"usage_limit,omitempty"`
+	// RemainingCredits is unset when usage is unlimited.
+	RemainingCredits *int64            `json:"remaining_credits,omitempty"`
+	RateLimits       []RateLimitBudget `json:"rate_limits"`
+	DailyUsage       []DailyUsage      `json:"daily_usage"`
+}
+
+type LicenseUsageService interface {
+	// AddUsage adds the requests and credits of every bucket to the stored bucket.
+	AddUsage(buckets []UsageBucket) error
+	DailyUsage(licenseID primitive.ObjectID, from, to time.Time) ([]DailyUsage, error)
+	EndpointUsage(licenseID primitive.ObjectID, from, to time.Time) ([]EndpointUsage, error)
+	// LicenseTotals sums the usage of every license with usage in the period.
+	LicenseTotals(from, to time.Time) ([]LicenseUsageTotal, error)
+}
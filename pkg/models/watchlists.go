@@ -0,0 +1,55 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type Watchlists struct {
+	Collection *mongo.Collection
+	// Locks holds a lease per license while its watched wallets are being changed.
+	Locks *mongo.Collection
+}
+
+type Watchlist struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	LicenseID primitive.ObjectID `bson:"license_id" json:"-"`
+	Name      string             `bson:"name" json:"name"`
+	Wallets   []WatchlistWallet  `bson:"wallets" json:"wallets"`
+	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
+	UpdatedAt time.Time          `bson:"updated_at" json:"updated_at"`
+}
+
+type WatchlistWallet struct {
+	Address string `bson:"address" json:"address"`
+	Label   string `bson:"label,omitempty" json:"label,omitempty"`
+}
+
+type WatchlistRequest struct {
+	Name    string            `json:"name"`
+	Wallets []WatchlistWallet `json:"wallets"`
+}
+
+type WatchlistBalance struct {
+	WalletBalance
+	Label string `json:"label,omitempty"`
+}
+
+// WatchlistService methods are scoped to the owning license, so one customer can
+// never read or change another customer's watchlists.
+type WatchlistService interface {
+	CreateWatchlist(watchlist *Watchlist) error
+	ListWatchlists(licenseID primitive.ObjectID) ([]Watchlist, error)
+	GetWatchlist(licenseID, id primitive.ObjectID) (*Watchlist, error)
+	UpdateWatchlist(watchlist *Watchlist) error
+	DeleteWatchlist(licenseID, id primitive.ObjectID) error
+	ListWatchedWallets() ([]string, error)
+	// LockWatchedWallets takes the license's lease for the given duration, it returns false
+	// while another unexpired lease is held.
+	LockWatchedWallets(licenseID, token primitive.ObjectID, lease time.Duration) (bool, error)
+	UnlockWatchedWallets(licenseID, token primitive.ObjectID) error
+	// FindWatchlistsWithWallet is used internally to route balance events and is not license scoped.
+	FindWatchlistsWithWallet(wallet string) ([]Watchlist, error)
+}
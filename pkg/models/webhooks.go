@@ -0,0 +1,99 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type WebhookEvent string
+
+const (
+	// WebhookEventBalanceChange fires on every balance change of a watched wallet.
+	WebhookEventBalanceChange WebhookEvent = "balance_change"
+	// WebhookEventThreshold fires when a balance crosses the webhook's threshold in either direction.
+	WebhookEventThreshold WebhookEvent = "threshold"
+)
+
+type DeliveryStatus string
+
+const (
+	DeliveryStatusPending   DeliveryStatus = "pending"
+	DeliveryStatusDelivered DeliveryStatus = "delivered"
+	DeliveryStatusDead      DeliveryStatus = "dead"
+)
+
+type Webhooks struct {
+	Collection *mongo.Collection
+}
+
+type Webhook struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	LicenseID   primitive.ObjectID `bson:"license_id" json:"-"`
+	WatchlistID primitive.ObjectID `bson:"watchlist_id" json:"watchlist_id"`
+	URL         string             `bson:"url" json:"url"`
+	// Secret signs every payload. It is only returned when the webhook is created.
+	Secret            string       `bson:"secret" json:"secret,omitempty"`
+	Event             WebhookEvent `bson:"event" json:"event"`
+	ThresholdLamports uint64       `bson:"threshold_lamports,omitempty" json:"threshold_lamports,omitempty"`
+	CreatedAt         time.Time    `bson:"created_at" json:"created_at"`
+}
+
+type CreateWebhookRequest struct {
+	WatchlistID string       `json:"watchlist_id"`
+	URL         string       `json:"url"`
+	Event       WebhookEvent `json:"event"`
+	// Threshold is a SOL amount, required for threshold webhooks.
+	Threshold string `json:"threshold,omitempty"`
+}
+
+type WebhookPayload struct {
+	Event            WebhookEvent `json:"event"`
+	WebhookID        string       `json:"webhook_id"`
+	Wallet           string       `json:"wallet"`
+	Label            string       `json:"label,omitempty"`
+	PreviousLamports uint64       `json:"previous_lamports"`
+	Lamports         uint64       `json:"lamports"`
+	Balance          string       `json:"balance"`
+	Threshold        uint64       `json:"threshold_lamports,omitempty"`
+	Slot             uint64       `json:"slot"`
+	ObservedAt       time.Time    `json:"observed_at"`
+}
+
+// WebhookDeliveries keeps the delivery log, exhausted deliveries are copied to DeadLetters.
+type WebhookDeliveries struct {
+	Collection  *mongo.Collection
+	DeadLetters *mongo.Collection
+}
+
+type WebhookDelivery struct {
+	ID             primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	WebhookID      primitive.ObjectID `bson:"webhook_id" json:"webhook_id"`
+	LicenseID      primitive.ObjectID `bson:"license_id" json:"-"`
+	URL            string             `bson:"url" json:"url"`
+	Payload        string             `bson:"payload" json:"payload"`
+	Status         DeliveryStatus     `bson:"status" json:"status"`
+	Attempts       int                `bson:"attempts" json:"attempts"`
+	ResponseStatus int                `bson:"response_status,omitempty" json:"response_status,omitempty"`
+	LastError      string             `bson:"last_error,omitempty" json:"last_error,omitempty"`
+	NextAttemptAt  *time.Time         `bson:"next_attempt_at,omitempty" json:"next_attempt_at,omitempty"`
+	CreatedAt      time.Time          `bson:"created_at" json:"created_at"`
+	DeliveredAt    *time.Time         `bson:"delivered_at,omitempty" json:"delivered_at,omitempty"`
+}
+
+type WebhookService interface {
+	CreateWebhook(webhook *Webhook) error
+	ListWebhooks(licenseID primitive.ObjectID) ([]Webhook, error)
+	GetWebhook(licenseID, id primitive.ObjectID) (*Webhook, error)
+	DeleteWebhook(licenseID, id primitive.ObjectID) error
+	ListWebhooksForWatchlists(watchlistIDs []primitive.ObjectID) ([]Webhook, error)
+}
+
+type WebhookDeliveryService interface {
+	CreateDelivery(delivery *WebhookDelivery) error
+	UpdateDelivery(delivery *WebhookDelivery) error
+	ListDueDeliveries(now time.Time) ([]WebhookDelivery, error)
+	ListDeliveries(webhookID primitive.ObjectID, limit int64) ([]WebhookDelivery, error)
+	AddDeadLetter(delivery *WebhookDelivery) error
+}
@@ -0,0 +1,120 @@
+package prices
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"log"
+	"main/pkg/solana"
+	"time"
+)
+
+const (
+	pythMagic          = 0xa1b2c3d4
+	pythPriceAccount   = 3
+	pythStatusTrading  = 1
+	pythLegacyMinSize  = 240
+	pythVerifiedFull   = 1
+	pythUpdateMinSize  = 8 + 32 + 1 + 32 + 8 + 8 + 4 + 8
+	pythVerifiedOffset = 8 + 32
+)
+
+var (
+	ErrUnknownPriceAccount = errors.New("not a Pyth price account")
+	ErrUnverifiedPrice     = errors.New("price update is not fully verified")
+
+	priceUpdateDiscriminator = anchorDiscriminator("PriceUpdateV2")
+)
+
+// PythSource reads Pyth price accounts through the RPC client. Feeds maps token mints to
+// the address of their USD price account.
+type PythSource struct {
+	Client *solana.SolClient
+	Feeds  map[string]string
+}
+
+func (p *PythSource) GetPrices(mints []string) (map[string]Price, uint64, error) {
+	accounts := make([]string, 0, len(mints))
+	for _, mint := range mints {
+		if account, ok := p.Feeds[mint]; ok {
+			accounts = append(accounts, account)
+		}
+	}
+	if len(accounts) == 0 {
+		return map[string]Price{}, 0, nil
+	}
+
+	data, slot, err := p.Client.GetAccountsData(accounts)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	result := make(map[string]Price, len(accounts))
+	for _, mint := range mints {
+		raw, ok := data[p.Feeds[mint]]
+		if !ok {
+			continue
+		}
+		price, err := ParsePythPrice(raw)
+		if err != nil {
+			log.Println("Error reading Pyth price for " + mint + ": " + err.Error())
+			continue
+		}
+		result[mint] = price
+	}
+
+	return result, slot, nil
+}
+
+// ParsePythPrice decodes the aggregate price of a legacy Pyth v2 price account or of a
+// PriceUpdateV2 account posted by the Pyth receiver program.
+func ParsePythPrice(data []byte) (Price, error) {
+	if len(data) >= 8 && [8]byte(data[:8]) == priceUpdateDiscriminator {
+		return parsePriceUpdate(data)
+	}
+	if len(data) >= pythLegacyMinSize && binary.LittleEndian.Uint32(data[0:]) == pythMagic {
+		return parseLegacyPrice(data)
+	}
+	return Price{}, ErrUnknownPriceAccount
+}
+
+func parseLegacyPrice(data []byte) (Price, error) {
+	if binary.LittleEndian.Uint32(data[8:]) != pythPriceAccount {
+		return Price{}, ErrUnknownPriceAccount
+	}
+	if binary.LittleEndian.Uint32(data[224:]) != pythStatusTrading {
+		return Price{}, ErrNotTrading
+	}
+
+	return Price{
+		Price:       int64(binary.LittleEndian.Uint64(data[208:])),
+		Conf:        binary.LittleEndian.Uint64(data[216:]),
+		Expo:        int32(binary.LittleEndian.Uint32(data[20:])),
+		PublishTime: time.Unix(int64(binary.LittleEndian.Uint64(data[96:])), 0),
+	}, nil
+}
+
+func parsePriceUpdate(data []byte) (Price, error) {
+	if len(data) < pythUpdateMinSize {
+		return Price{}, ErrUnknownPriceAccount
+	}
+
+	// VerificationLevel is Partial { num_signatures: u8 } or Full
+	offset := pythVerifiedOffset
+	if data[offset] != pythVerifiedFull {
+		return Price{}, ErrUnverifiedPrice
+	}
+	offset += 1 + 32 // feed id
+
+	return Price{
+		Price:       int64(binary.LittleEndian.Uint64(data[offset:])),
+		Conf:        binary.LittleEndian.Uint64(data[offset+8:]),
+		Expo:        int32(binary.LittleEndian.Uint32(data[offset+16:])),
+		PublishTime: time.Unix(int64(binary.LittleEndian.Uint64(data[offset+20:])), 0),
+	}, nil
+}
+
+func anchorDiscriminator(account string) [8]byte {
+	sum := sha256.Sum256([]byte("account:" + account))
+	return [8]byte(sum[:8])
+}
@@ -0,0 +1,69 @@
+package prices
+
+import (
+	"encoding/binary"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func legacyPriceAccount(price int64, conf uint64, expo int32, status uint32, published int64) []byte {
+	data := make([]byte, 3312)
+	binary.LittleEndian.PutUint32(data[0:], pythMagic)
+	binary.LittleEndian.PutUint32(data[4:], 2)
+	binary.LittleEndian.PutUint32(data[8:], pythPriceAccount)
+	binary.LittleEndian.PutUint32(data[20:], uint32(expo))
+	binary.LittleEndian.PutUint64(data[96:], uint64(published))
+	binary.LittleEndian.PutUint64(data[208:], uint64(price))
+	binary.LittleEndian.PutUint64(data[216:], conf)
+	binary.LittleEndian.PutUint32(data[224:], status)
+	return data
+}
+
+func TestParseLegacyPythPrice(t *testing.T) {
+	price, err := ParsePythPrice(legacyPriceAccount(14_523_000_000, 7_000_000, -8, pythStatusTrading, 1_700_000_000))
+	require.NoError(t, err)
+	assert.Equal(t, "145.23000000", price.Value().FloatString(8))
+	assert.Equal(t, "0.07", price.Confidence().FloatString(2))
+	assert.Equal(t, time.Unix(1_700_000_000, 0), price.PublishTime)
+
+	_, err = ParsePythPrice(legacyPriceAccount(14_523_000_000, 7_000_000, -8, 0, 1_700_000_000))
+	assert.ErrorIs(t, err, ErrNotTrading)
+}
+
+func TestParsePriceUpdate(t *testing.T) {
+	data := make([]byte, 134)
+	copy(data, priceUpdateDiscriminator[:])
+	data[40] = pythVerifiedFull
+	offset := 41 + 32
+	expo := int32(-8)
+	binary.LittleEndian.PutUint64(data[offset:], 99_990_000)
+	binary.LittleEndian.PutUint64(data[offset+8:], 10_000)
+	binary.LittleEndian.PutUint32(data[offset+16:], uint32(expo))
+	binary.LittleEndian.PutUint64(data[offset+20:], 1_700_000_000)
+
+	price, err := ParsePythPrice(data)
+	require.NoError(t, err)
+	assert.Equal(t, "0.9999", price.Value().FloatString(4))
+
+	data[40] = 0
+	_, err = ParsePythPrice(data)
+	assert.ErrorIs(t, err, ErrUnverifiedPrice)
+
+	_, err = ParsePythPrice(make([]byte, 300))
+	assert.ErrorIs(t, err, ErrUnknownPriceAccount)
+}
+
+func TestValidatePrice(t *testing.T) {
+	now := time.Unix(1_700_000_060, 0)
+	price := Price{Price: 10_000, Conf: 100, Expo: -2, PublishTime: time.Unix(1_700_000_000, 0)}
+
+	assert.NoError(t, price.Validate(now, time.Minute, 0.02))
+	assert.ErrorIs(t, price.Validate(now, 30*time.Second, 0.02), ErrStalePrice)
+	assert.ErrorIs(t, price.Validate(now, time.Minute, 0.005), ErrWideConfidence)
+
+	price.Price = 0
+	assert.ErrorIs(t, price.Validate(now, time.Minute, 0.02), ErrNotTrading)
+}
@@ -0,0 +1,87 @@
+package prices
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+)
+
+var (
+	ErrStalePrice     = errors.New("price is stale")
+	ErrWideConfidence = errors.New("price confidence interval is too wide")
+	ErrNotTrading     = errors.New("price feed is not trading")
+)
+
+// Price is an oracle price in USD: Price and Conf are scaled by 10^Expo.
+type Price struct {
+	Price       int64
+	Conf        uint64
+	Expo        int32
+	PublishTime time.Time
+}
+
+// Source provides USD prices keyed by token mint. Mints without a price are left out of
+// the result. The returned slot is the one the prices were read at.
+type Source interface {
+	GetPrices(mints []string) (map[string]Price, uint64, error)
+}
+
+// Value returns the price as an exact rational number.
+func (p Price) Value() *big.Rat {
+	return scale(new(big.Int).SetInt64(p.Price), p.Expo)
+}
+
+// Confidence returns the confidence interval as an exact rational number.
+func (p Price) Confidence() *big.Rat {
+	return scale(new(big.Int).SetUint64(p.Conf), p.Expo)
+}
+
+// Validate rejects non-positive prices, prices older than maxAge and prices whose
+// confidence interval exceeds maxConfidence as a fraction of the price.
+func (p Price) Validate(now time.Time, maxAge time.Duration, maxConfidence float64) error {
+	if p.Price <= 0 {
+		return ErrNotTrading
+	}
+	if age := now.Sub(p.PublishTime); age > maxAge {
+		return fmt.Errorf("%w: published %s ago", ErrStalePrice, age.Truncate(time.Second))
+	}
+
+	ratio, _ := new(big.Rat).SetFrac(new(big.Int).SetUint64(p.Conf), big.NewInt(p.Price)).Float64()
+	if ratio > maxConfidence {
+		return fmt.Errorf("%w: %.4f of the price", ErrWideConfidence, ratio)
+	}
+
+	return nil
+}
+
+func scale(value *big.Int, expo int32) *big.Rat {
+	factor := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(abs(expo))), nil)
+	if expo < 0 {
+		return new(big.Rat).SetFrac(value, factor)
+	}
+	return new(big.Rat).SetInt(new(big.Int).Mul(value, factor))
+}
+
+func abs(value int32) int32 {
+	if value < 0 {
+		return -value
+	}
+	return value
+}
+
+// FixtureSource serves fixed prices, for tests and local development without an oracle.
+type FixtureSource struct {
+	Prices map[string]Price
+	Slot   uint64
+}
+
+func (f *FixtureSource) GetPrices(mints []string) (map[string]Price, uint64, error) {
+	result := make(map[string]Price, len(mints))
+	for _, mint := range mints {
+		if price, ok := f.Prices[mint]; ok {
+			result[mint] = price
+		}
+	}
+	return result, f.Slot, nil
+}
@@ -64,13 +64,14 @@ func runWalletQueue(walletAddress string) {
 		val, exists := queueMap[walletAddress]
 		queueMapMutex.RUnlock()
 		if exists && len(val) > 0 {
+			service.RecordWalletFetch(walletAddress)
 			if amount, err := service.GetWallet(walletAddress); err == nil {
 				*val[0] <- Result{Result: amount, Error: nil, Cache: true}
 				popJobFromWalletQueue(walletAddress)
 				continue
 			}
 
-			amount, err := solana.NewSolClient().GetBalance(walletAddress)
+			lamports, slot, err := solana.NewSolClient().GetLamports(walletAddress)
 			if err != nil {
 				// sending errors to all channels in the queue
 				for {
@@ -80,8 +81,11 @@ func runWalletQueue(walletAddress string) {
 					}
 					*ch <- Result{Result: "", Error: err, Cache: false}
 				}
+				continue
 			}
+			amount := solana.LamportsToSol(lamports)
 			*val[0] <- Result{Result: amount, Error: nil, Cache: false}
+			service.ObserveBalance(walletAddress, lamports, slot)
 			err = service.SetWallet(walletAddress, amount)
 			if err != nil {
 				log.Println("Error setting wallet to cache:", err)
@@ -0,0 +1,127 @@
+package ratelimit
+
+import (
+	"errors"
+	"log"
+	"sync/atomic"
+	"time"
+)
+
+type Algorithm string
+
+const (
+	// SlidingWindow keeps a log of request times and admits Limit requests in any Window.
+	SlidingWindow Algorithm = "sliding_window"
+	// TokenBucket holds up to Burst tokens and refills Limit tokens per Window, one
+	// token is taken per request.
+	TokenBucket Algorithm = "token_bucket"
+)
+
+// Policy decides what happens to requests while the shared store is unreachable.
+type Policy string
+
+const (
+	// PolicyLocal limits with the in-process store, so each instance enforces the limits on its own.
+	PolicyLocal Policy = "local"
+	// PolicyOpen admits every request.
+	PolicyOpen Policy = "open"
+	// PolicyClosed rejects every request.
+	PolicyClosed Policy = "closed"
+)
+
+// fallbackLogInterval keeps a store outage from flooding the log.
+const fallbackLogInterval = 10 * time.Second
+
+var ErrUnavailable = errors.New("rate limiter unavailable")
+
+type Limit struct {
+	Algorithm Algorithm
+	Limit     int64
+	Window    time.Duration
+	// Burst is the bucket size of a token bucket, it defaults to Limit.
+	Burst int64
+}
+
+// Capacity is the most requests admitted at once: the bucket size of a token bucket or
+// the limit of a sliding window.
+func (l Limit) Capacity() int64 {
+	if l.Algorithm == TokenBucket && l.Burst > 0 {
+		return l.Burst
+	}
+	return l.Limit
+}
+
+type Result struct {
+	Allowed bool
+	// Limit is the window's limit or the bucket size.
+	Limit     int64
+	Remaining int64
+	// Reset is the time until the full limit is available again.
+	Reset time.Duration
+	// RetryAfter is the time until the next request would be admitted, zero when allowed.
+	RetryAfter time.Duration
+}
+
+// Store applies limits to the requests counted under keys. Keys name the dimension
+// being limited, e.g. "ip:1.2.3.4" or "license:<id>:minute". A request is only counted
+// against the limits when every one of them admits it, so a rejected request does not
+// use up the limits that had room.
+type Store interface {
+	AllowAll(keys []string, limits []Limit) ([]Result, error)
+}
+
+// StoreFunc adapts a function to a Store.
+type StoreFunc func(keys []string, limits []Limit) ([]Result, error)
+
+func (f StoreFunc) AllowAll(keys []string, limits []Limit) ([]Result, error) {
+	return f(keys, limits)
+}
+
+type Limiter struct {
+	Store    Store
+	Fallback Store
+	Policy   Policy
+
+	lastFallbackLog atomic.Int64
+}
+
+// Allow applies a single limit, see AllowAll.
+func (l *Limiter) Allow(key string, limit Limit) (Result, error) {
+	results, err := l.AllowAll([]string{key}, []Limit{limit})
+	if err != nil {
+		return Result{}, err
+	}
+	return results[0], nil
+}
+
+// AllowAll applies the limits in the shared store. When the store fails the policy
+// decides: the local fallback store, admitting the request or ErrUnavailable.
+func (l *Limiter) AllowAll(keys []string, limits []Limit) ([]Result, error) {
+	results, err := l.Store.AllowAll(keys, limits)
+	if err == nil {
+		return results, nil
+	}
+	l.logFallback(err)
+
+	switch l.Policy {
+	case PolicyOpen:
+		results = make([]Result, len(limits))
+		for i, limit := range limits {
+			results[i] = Result{Allowed: true, Limit: limit.Capacity(), Remaining: limit.Capacity()}
+		}
+		return results, nil
+	case PolicyClosed:
+		return nil, ErrUnavailable
+	default:
+		return l.Fallback.AllowAll(keys, limits)
+	}
+}
+
+func (l *Limiter) logFallback(err error) {
+	now := time.Now().UnixNano()
+	last := l.lastFallbackLog.Load()
+	if now-last < int64(fallbackLogInterval) || !l.lastFallbackLog.CompareAndSwap(last, now) {
+		return
+	}
+	log.Println("Rate limit store unreachable, applying the " + string(l.Policy) + " policy: " + err.Error())
+}
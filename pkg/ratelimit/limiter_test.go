@@ -0,0 +1,56 @@
+package ratelimit
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLimiterFallback(t *testing.T) {
+	failing := StoreFunc(func([]string, []Limit) ([]Result, error) {
+		return nil, errors.New("connection refused")
+	})
+	limit := Limit{Algorithm: SlidingWindow, Limit: 1, Window: time.Minute}
+
+	t.Run("local", func(t *testing.T) {
+		limiter := &Limiter{Store: failing, Fallback: NewMemoryStore(), Policy: PolicyLocal}
+
+		result, err := limiter.Allow("key", limit)
+		assert.NoError(t, err)
+		assert.True(t, result.Allowed)
+
+		result, err = limiter.Allow("key", limit)
+		assert.NoError(t, err)
+		assert.False(t, result.Allowed, "the fallback store still limits")
+	})
+
+	t.Run("open", func(t *testing.T) {
+		limiter := &Limiter{Store: failing, Fallback: NewMemoryStore(), Policy: PolicyOpen}
+
+		for i := 0; i < 3; i++ {
+			result, err := limiter.Allow("key", limit)
+			assert.NoError(t, err)
+			assert.True(t, result.Allowed)
+		}
+	})
+
+	t.Run("closed", func(t *testing.T) {
+		limiter := &Limiter{Store: failing, Fallback: NewMemoryStore(), Policy: PolicyClosed}
+
+		_, err := limiter.Allow("key", limit)
+		assert.ErrorIs(t, err, ErrUnavailable)
+	})
+
+	t.Run("store reachable", func(t *testing.T) {
+		store := StoreFunc(func([]string, []Limit) ([]Result, error) {
+			return []Result{{Allowed: false, Limit: 1}}, nil
+		})
+		limiter := &Limiter{Store: store, Fallback: NewMemoryStore(), Policy: PolicyOpen}
+
+		result, err := limiter.Allow("key", limit)
+		assert.NoError(t, err)
+		assert.False(t, result.Allowed)
+	})
+}
@@ -0,0 +1,151 @@
+package ratelimit
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// sweepInterval is how often state of keys that are idle long enough to be back at
+// their full limit is dropped.
+const sweepInterval = time.Minute
+
+// MemoryStore implements both algorithms in process. It mirrors the Redis scripts and
+// serves as the local fallback.
+type MemoryStore struct {
+	mutex     sync.Mutex
+	now       func() time.Time
+	windows   map[string][]time.Time
+	buckets   map[string]*bucket
+	expiries  map[string]time.Time
+	lastSweep time.Time
+}
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		now:      time.Now,
+		windows:  make(map[string][]time.Time),
+		buckets:  make(map[string]*bucket),
+		expiries: make(map[string]time.Time),
+	}
+}
+
+// Allow applies a single limit, see AllowAll.
+func (m *MemoryStore) Allow(key string, limit Limit) (Result, error) {
+	results, err := m.AllowAll([]string{key}, []Limit{limit})
+	if err != nil {
+		return Result{}, err
+	}
+	return results[0], nil
+}
+
+// AllowAll checks every limit first and only counts the request when all of them admit it.
+func (m *MemoryStore) AllowAll(keys []string, limits []Limit) ([]Result, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	now := m.now()
+	m.sweep(now)
+
+	admitted := true
+	for i, limit := range limits {
+		if !m.hasRoom(keys[i], limit, now) {
+			admitted = false
+		}
+	}
+
+	results := make([]Result, len(limits))
+	for i, limit := range limits {
+		if limit.Algorithm == TokenBucket {
+			results[i] = m.takeToken(keys[i], limit, now, admitted)
+		} else {
+			results[i] = m.recordRequest(keys[i], limit, now, admitted)
+		}
+	}
+	return results, nil
+}
+
+// hasRoom drops requests that left the window or refills the bucket, and reports
+// whether the limit admits another request.
+func (m *MemoryStore) hasRoom(key string, limit Limit, now time.Time) bool {
+	if limit.Algorithm == TokenBucket {
+		capacity := float64(limit.Capacity())
+		rate := float64(limit.Limit) / float64(limit.Window)
+		state, ok := m.buckets[key]
+		if !ok {
+			state = &bucket{tokens: capacity, updated: now}
+			m.buckets[key] = state
+		}
+		state.tokens = min(capacity, state.tokens+float64(now.Sub(state.updated))*rate)
+		state.updated = now
+		return state.tokens >= 1
+	}
+
+	requests := m.windows[key]
+	start := 0
+	for start < len(requests) && !requests[start].After(now.Add(-limit.Window)) {
+		start++
+	}
+	m.windows[key] = requests[start:]
+	return int64(len(m.windows[key])) < limit.Limit
+}
+
+func (m *MemoryStore) recordRequest(key string, limit Limit, now time.Time, admitted bool) Result {
+	requests := m.windows[key]
+	result := Result{Limit: limit.Limit, Allowed: int64(len(requests)) < limit.Limit}
+	if admitted {
+		requests = append(requests, now)
+	}
+	result.Remaining = limit.Limit - int64(len(requests))
+	if len(requests) > 0 {
+		result.Reset = requests[len(requests)-1].Add(limit.Window).Sub(now)
+		if !result.Allowed {
+			result.RetryAfter = requests[0].Add(limit.Window).Sub(now)
+		}
+	}
+	m.windows[key] = requests
+	m.expiries[key] = now.Add(limit.Window)
+	return result
+}
+
+func (m *MemoryStore) takeToken(key string, limit Limit, now time.Time, admitted bool) Result {
+	capacity := float64(limit.Capacity())
+	state := m.buckets[key]
+
+	result := Result{Limit: limit.Capacity(), Allowed: state.tokens >= 1}
+	if admitted {
+		state.tokens--
+	} else if !result.Allowed {
+		result.RetryAfter = refillTime(1-state.tokens, limit)
+	}
+	result.Remaining = int64(state.tokens)
+	result.Reset = refillTime(capacity-state.tokens, limit)
+	m.expiries[key] = now.Add(result.Reset)
+	return result
+}
+
+// refillTime is how long the bucket takes to gain tokens, rounded up so callers never
+// retry too early.
+func refillTime(tokens float64, limit Limit) time.Duration {
+	return time.Duration(math.Ceil(tokens * float64(limit.Window) / float64(limit.Limit)))
+}
+
+func (m *MemoryStore) sweep(now time.Time) {
+	if now.Sub(m.lastSweep) < sweepInterval {
+		return
+	}
+	m.lastSweep = now
+
+	for key, expiry := range m.expiries {
+		if now.After(expiry) {
+			delete(m.windows, key)
+			delete(m.buckets, key)
+			delete(m.expiries, key)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestStore(now *time.Time) *MemoryStore {
+	store := NewMemoryStore()
+	store.now = func() time.Time { return *now }
+	return store
+}
+
+func TestMemoryStoreSlidingWindow(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	store := newTestStore(&now)
+	limit := Limit{Algorithm: SlidingWindow, Limit: 3, Window: time.Minute}
+
+	for i := 0; i < 3; i++ {
+		result, err := store.Allow("key", limit)
+		assert.NoError(t, err)
+		assert.True(t, result.Allowed)
+		assert.Equal(t, int64(2-i), result.Remaining)
+		now = now.Add(10 * time.Second)
+	}
+
+	result, _ := store.Allow("key", limit)
+	assert.False(t, result.Allowed)
+	assert.Equal(t, int64(0), result.Remaining)
+	assert.Equal(t, 30*time.Second, result.RetryAfter, "the oldest request leaves the window first")
+
+	// The window slides instead of resetting at a fixed boundary.
+	now = now.Add(30 * time.Second)
+	result, _ = store.Allow("key", limit)
+	assert.True(t, result.Allowed)
+	assert.Equal(t, int64(0), result.Remaining)
+
+	result, _ = store.Allow("other", limit)
+	assert.True(t, result.Allowed, "keys are limited independently")
+}
+
+func TestMemoryStoreTokenBucket(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	store := newTestStore(&now)
+	limit := Limit{Algorithm: TokenBucket, Limit: 2, Window: time.Second, Burst: 4}
+
+	for i := 0; i < 4; i++ {
+		result, _ := store.Allow("key", limit)
+		assert.True(t, result.Allowed, "the burst is admitted at once")
+	}
+
+	result, _ := store.Allow("key", limit)
+	assert.False(t, result.Allowed)
+	assert.Equal(t, int64(4), result.Limit)
+	assert.Equal(t, 500*time.Millisecond, result.RetryAfter)
+	assert.Equal(t, 2*time.Second, result.Reset)
+
+	now = now.Add(500 * time.Millisecond)
+	result, _ = store.Allow("key", limit)
+	assert.True(t, result.Allowed)
+	result, _ = store.Allow("key", limit)
+	assert.False(t, result.Allowed)
+
+	now = now.Add(time.Hour)
+	result, _ = store.Allow("key", limit)
+	assert.True(t, result.Allowed)
+	assert.Equal(t, int64(3), result.Remaining, "refills stop at the bucket size")
+}
+
+func TestMemoryStoreSweep(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	store := newTestStore(&now)
+
+	_, _ = store.Allow("window", Limit{Algorithm: SlidingWindow, Limit: 1, Window: time.Second})
+	_, _ = store.Allow("bucket", Limit{Algorithm: TokenBucket, Limit: 1, Window: time.Second})
+
+	now = now.Add(2 * sweepInterval)
+	_, _ = store.Allow("new", Limit{Algorithm: SlidingWindow, Limit: 1, Window: time.Hour})
+
+	assert.NotContains(t, store.windows, "window")
+	assert.NotContains(t, store.buckets, "bucket")
+	assert.Contains(t, store.windows, "new")
+}
+
+func TestMemoryStoreAllowAll(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	store := newTestStore(&now)
+	keys := []string{"second", "minute"}
+	limits := []Limit{
+		{Algorithm: TokenBucket, Limit: 10, Window: time.Second},
+		{Algorithm: SlidingWindow, Limit: 2, Window: time.Minute},
+	}
+
+	for i := 0; i < 2; i++ {
+		results, err := store.AllowAll(keys, limits)
+		assert.NoError(t, err)
+		assert.True(t, results[0].Allowed)
+		assert.True(t, results[1].Allowed)
+	}
+
+	results, _ := store.AllowAll(keys, limits)
+	assert.True(t, results[0].Allowed, "the bucket had room")
+	assert.False(t, results[1].Allowed)
+	assert.Equal(t, int64(8), results[0].Remaining, "a rejected request takes no token")
+
+	for i := 0; i < 5; i++ {
+		_, _ = store.AllowAll(keys, limits)
+	}
+	result, _ := store.Allow("second", limits[0])
+	assert.Equal(t, int64(7), result.Remaining, "rejections never drain the other limits")
+}
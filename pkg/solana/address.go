@@ -0,0 +1,8 @@
+package solana
+
+import "github.com/gagliardetto/solana-go"
+
+func IsValidAddress(address string) bool {
+	_, err := solana.PublicKeyFromBase58(address)
+	return err == nil
+}
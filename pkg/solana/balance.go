@@ -4,31 +4,83 @@ import (
 	"context"
 	"math/big"
 
-	"github.com/davecgh/go-spew/spew"
 	"github.com/gagliardetto/solana-go"
 	"github.com/gagliardetto/solana-go/rpc"
 )
 
 func (s *SolClient) GetBalance(address string) (string, error) {
-	pubKey, err := solana.PublicKeyFromBase58(address)
+	lamports, _, err := s.GetLamports(address)
 	if err != nil {
 		return "", err
 	}
 
+	return LamportsToSol(lamports), nil
+}
+
+// GetLamports returns the balance in lamports along with the slot it was read at.
+func (s *SolClient) GetLamports(address string) (uint64, uint64, error) {
+	pubKey, err := solana.PublicKeyFromBase58(address)
+	if err != nil {
+		return 0, 0, err
+	}
+
 	out, err := s.Client.GetBalance(
 		context.TODO(),
 		pubKey,
 		rpc.CommitmentFinalized,
 	)
 	if err != nil {
-		return "", err
+		return 0, 0, err
 	}
 
-	spew.Dump(out)
-	spew.Dump(out.Value)
+	return out.Value, out.Context.Slot, nil
+}
 
-	lamportsOnAccount := new(big.Float).SetUint64(out.Value)
+func LamportsToSol(lamports uint64) string {
+	lamportsOnAccount := new(big.Float).SetUint64(lamports)
 	solBalance := new(big.Float).Quo(lamportsOnAccount, new(big.Float).SetUint64(solana.LAMPORTS_PER_SOL))
 
-	return solBalance.Text('f', 9), nil
+	return solBalance.Text('f', 9)
+}
+
+// maxAccountsPerRequest is the getMultipleAccounts limit of the Solana RPC API.
+const maxAccountsPerRequest = 100
+
+// GetBalances returns the lamports of each address, batching getMultipleAccounts calls,
+// and the slot each balance was read at, which differs between batches. Accounts that do
+// not exist are reported with a zero balance.
+func (s *SolClient) GetBalances(addresses []string) (map[string]uint64, map[string]uint64, error) {
+	keys := make([]solana.PublicKey, 0, len(addresses))
+	for _, address := range addresses {
+		pubKey, err := solana.PublicKeyFromBase58(address)
+		if err != nil {
+			return nil, nil, err
+		}
+		keys = append(keys, pubKey)
+	}
+
+	zero := uint64(0)
+	balances := make(map[string]uint64, len(keys))
+	slots := make(map[string]uint64, len(keys))
+	for start := 0; start < len(keys); start += maxAccountsPerRequest {
+		batch := keys[start:min(start+maxAccountsPerRequest, len(keys))]
+		out, err := s.Client.GetMultipleAccountsWithOpts(context.TODO(), batch, &rpc.GetMultipleAccountsOpts{
+			Commitment: rpc.CommitmentFinalized,
+			DataSlice:  &rpc.DataSlice{Offset: &zero, Length: &zero},
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+
+		for i, account := range out.Value {
+			if account != nil {
+				balances[batch[i].String()] = account.Lamports
+			} else {
+				balances[batch[i].String()] = 0
+			}
+			slots[batch[i].String()] = out.Context.Slot
+		}
+	}
+
+	return balances, slots, nil
 }
@@ -0,0 +1,42 @@
+package solana
+
+import (
+	"context"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// MaxPriorityFeeAccounts is the most accounts getRecentPrioritizationFees accepts.
+const MaxPriorityFeeAccounts = 128
+
+func (s *SolClient) GetRecentPrioritizationFees(accounts []string) ([]rpc.PriorizationFeeResult, error) {
+	keys := make(solana.PublicKeySlice, 0, len(accounts))
+	for _, account := range accounts {
+		key, err := solana.PublicKeyFromBase58(account)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+
+	return s.Client.GetRecentPrioritizationFees(context.TODO(), keys)
+}
+
+// WritableAccounts returns the statically declared writable accounts of a base64 encoded
+// transaction. Accounts loaded through address lookup tables are not resolved.
+func WritableAccounts(txBase64 string) ([]string, error) {
+	tx, err := solana.TransactionFromBase64(txBase64)
+	if err != nil {
+		return nil, err
+	}
+
+	var accounts []string
+	for _, key := range tx.Message.AccountKeys {
+		if tx.Message.IsWritableStatic(key) {
+			accounts = append(accounts, key.String())
+		}
+	}
+
+	return accounts, nil
+}
@@ -0,0 +1,93 @@
+package solana
+
+import (
+	"context"
+	"encoding/binary"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+const (
+	tokenAccountSize = 165
+	// only the owner and amount of each token account are downloaded
+	tokenAccountOwnerOffset = 32
+	tokenAccountSliceLength = 32 + 8
+	// Token-2022 accounts are read up to their account type, which follows the padding
+	// when the account carries extensions
+	token2022AccountSliceLength = token2022AccountTypeOffset + 1 - tokenAccountOwnerOffset
+)
+
+type TokenAccountBalance struct {
+	Owner  string
+	Amount uint64
+}
+
+type programAccountsWithContext struct {
+	rpc.RPCContext
+	Value []*rpc.KeyedAccount `json:"value"`
+}
+
+// GetMintTokenAccounts lists the owner and amount of every token account of mint, along
+// with the slot the accounts were read at. Token program accounts are matched on size
+// and mint. Token-2022 accounts vary in size with their extensions and filters cannot
+// express "165 bytes or account type Account", so they are matched on the mint and the
+// account type is checked on the slice that is read.
+func (s *SolClient) GetMintTokenAccounts(mint, program string) ([]TokenAccountBalance, uint64, error) {
+	mintKey, err := solana.PublicKeyFromBase58(mint)
+	if err != nil {
+		return nil, 0, err
+	}
+	programKey, err := solana.PublicKeyFromBase58(program)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	filters := []rpc.RPCFilter{{Memcmp: &rpc.RPCFilterMemcmp{Offset: 0, Bytes: mintKey.Bytes()}}}
+	sliceLength := tokenAccountSliceLength
+	if programKey.Equals(solana.TokenProgramID) {
+		filters = append(filters, rpc.RPCFilter{DataSize: tokenAccountSize})
+	} else {
+		sliceLength = token2022AccountSliceLength
+	}
+
+	var out programAccountsWithContext
+	err = s.Client.RPCCallForInto(context.TODO(), &out, "getProgramAccounts", []interface{}{
+		programKey,
+		rpc.M{
+			"commitment":  rpc.CommitmentFinalized,
+			"encoding":    solana.EncodingBase64,
+			"withContext": true,
+			"filters":     filters,
+			"dataSlice":   rpc.M{"offset": tokenAccountOwnerOffset, "length": sliceLength},
+		},
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	accounts := make([]TokenAccountBalance, 0, len(out.Value))
+	for _, account := range out.Value {
+		data := account.Account.Data.GetBinary()
+		if !isTokenAccountSlice(data, sliceLength) {
+			continue
+		}
+		accounts = append(accounts, TokenAccountBalance{
+			Owner:  solana.PublicKeyFromBytes(data[:32]).String(),
+			Amount: binary.LittleEndian.Uint64(data[32:]),
+		})
+	}
+
+	return accounts, out.Context.Slot, nil
+}
+
+// isTokenAccountSlice reports whether the slice read from an account holds a token
+// account. A Token-2022 slice is one byte shorter when the account is exactly 165 bytes
+// and otherwise ends with the account type.
+func isTokenAccountSlice(data []byte, sliceLength int) bool {
+	if sliceLength == tokenAccountSliceLength {
+		return len(data) == tokenAccountSliceLength
+	}
+	return len(data) == sliceLength-1 ||
+		(len(data) == sliceLength && data[sliceLength-1] == token2022AccountTypeAccount)
+}
@@ -0,0 +1,26 @@
+package solana
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsTokenAccountSlice(t *testing.T) {
+	assert.True(t, isTokenAccountSlice(make([]byte, tokenAccountSliceLength), tokenAccountSliceLength))
+	assert.False(t, isTokenAccountSlice(make([]byte, tokenAccountSliceLength-1), tokenAccountSliceLength))
+
+	// a Token-2022 account without extensions ends right before the account type
+	plain := make([]byte, token2022AccountSliceLength-1)
+	assert.True(t, isTokenAccountSlice(plain, token2022AccountSliceLength))
+
+	account := make([]byte, token2022AccountSliceLength)
+	account[token2022AccountSliceLength-1] = token2022AccountTypeAccount
+	assert.True(t, isTokenAccountSlice(account, token2022AccountSliceLength))
+
+	mint := make([]byte, token2022AccountSliceLength)
+	mint[token2022AccountSliceLength-1] = token2022AccountTypeMint
+	assert.False(t, isTokenAccountSlice(mint, token2022AccountSliceLength), "mints with extensions share the data size filter")
+
+	assert.False(t, isTokenAccountSlice(make([]byte, tokenAccountSliceLength), token2022AccountSliceLength))
+}
@@ -0,0 +1,292 @@
+package solana
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+const (
+	mintSize = 82
+	// Token-2022 pads mints to the size of a token account before the account type and
+	// the extension TLV entries.
+	token2022AccountTypeOffset  = 165
+	token2022AccountTypeMint    = 1
+	token2022AccountTypeAccount = 2
+)
+
+var (
+	ErrNotAMint = errors.New("account is not a token mint")
+
+	MetaplexMetadataProgramID = solana.MustPublicKeyFromBase58("metaqbxxUerdq28cj1RbAWkYQm3ybzjb6a8bt518x1s")
+)
+
+var mintExtensionNames = map[uint16]string{
+	1:  "transfer_fee_config",
+	3:  "mint_close_authority",
+	4:  "confidential_transfer_mint",
+	6:  "default_account_state",
+	9:  "non_transferable",
+	10: "interest_bearing_config",
+	12: "permanent_delegate",
+	14: "transfer_hook",
+	16: "confidential_transfer_fee_config",
+	18: "metadata_pointer",
+	19: "token_metadata",
+	20: "group_pointer",
+	21: "token_group",
+	22: "group_member_pointer",
+	23: "token_group_member",
+}
+
+const (
+	extensionTransferFeeConfig  = 1
+	extensionMintCloseAuthority = 3
+	extensionPermanentDelegate  = 12
+	extensionTransferHook       = 14
+	extensionMetadataPointer    = 18
+	extensionTokenMetadata      = 19
+)
+
+type MintInfo struct {
+	Mint            string
+	Program         string
+	Supply          uint64
+	Decimals        uint8
+	MintAuthority   string
+	FreezeAuthority string
+	Extensions      []MintExtension
+	Metadata        *TokenMetadata
+	// MetadataPointer is the account holding the metadata when a Token-2022 mint points elsewhere.
+	MetadataPointer string
+	Slot            uint64
+}
+
+// MintExtension is a Token-2022 extension. Fields holds the decoded values of the
+// extensions that are decoded, other extensions are only named.
+type MintExtension struct {
+	Name   string
+	Fields map[string]string
+}
+
+type TokenMetadata struct {
+	Source string
+	Name   string
+	Symbol string
+	Uri    string
+}
+
+// GetMintInfo reads and decodes a Token or Token-2022 mint, including Token-2022
+// metadata or, failing that, its Metaplex metadata account.
+func (s *SolClient) GetMintInfo(mint string) (*MintInfo, error) {
+	mintKey, err := solana.PublicKeyFromBase58(mint)
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := s.Client.GetAccountInfoWithOpts(context.TODO(), mintKey, &rpc.GetAccountInfoOpts{
+		Commitment: rpc.CommitmentFinalized,
+	})
+	if err != nil {
+		return nil, err
+	}
+	owner := out.Value.Owner
+	if !owner.Equals(solana.TokenProgramID) && !owner.Equals(solana.Token2022ProgramID) {
+		return nil, ErrNotAMint
+	}
+
+	info, err := DecodeMint(owner, out.GetBinary())
+	if err != nil {
+		return nil, err
+	}
+	info.Mint = mint
+	info.Program = owner.String()
+	info.Slot = out.Context.Slot
+
+	// mints without the TokenMetadata extension, including Token-2022 mints whose metadata
+	// pointer names another account, usually have a Metaplex metadata account
+	if info.Metadata == nil {
+		info.Metadata, err = s.getMetaplexMetadata(mintKey)
+		if err != nil && !errors.Is(err, rpc.ErrNotFound) {
+			return nil, err
+		}
+	}
+
+	return info, nil
+}
+
+func (s *SolClient) getMetaplexMetadata(mint solana.PublicKey) (*TokenMetadata, error) {
+	address, _, err := solana.FindProgramAddress(
+		[][]byte{[]byte("metadata"), MetaplexMetadataProgramID.Bytes(), mint.Bytes()},
+		MetaplexMetadataProgramID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := s.Client.GetAccountInfo(context.TODO(), address)
+	if err != nil {
+		return nil, err
+	}
+
+	return DecodeMetaplexMetadata(out.GetBinary())
+}
+
+// DecodeMint decodes the base mint layout and any Token-2022 extensions of an account
+// owned by program. Token accounts of either program are rejected: Token mints are
+// exactly 82 bytes, Token-2022 mints are too unless they carry extensions, in which case
+// the account type after the padding says whether the account is a mint.
+func DecodeMint(program solana.PublicKey, data []byte) (*MintInfo, error) {
+	switch {
+	case len(data) == mintSize:
+	case program.Equals(solana.Token2022ProgramID) && len(data) > token2022AccountTypeOffset &&
+		data[token2022AccountTypeOffset] == token2022AccountTypeMint:
+	default:
+		return nil, ErrNotAMint
+	}
+	if data[45] == 0 {
+		return nil, ErrNotAMint
+	}
+
+	info := &MintInfo{
+		MintAuthority:   optionalKey(data[0:36]),
+		Supply:          binary.LittleEndian.Uint64(data[36:]),
+		Decimals:        data[44],
+		FreezeAuthority: optionalKey(data[46:82]),
+	}
+	if len(data) == mintSize {
+		return info, nil
+	}
+
+	for offset := token2022AccountTypeOffset + 1; offset+4 <= len(data); {
+		extensionType := binary.LittleEndian.Uint16(data[offset:])
+		length := int(binary.LittleEndian.Uint16(data[offset+2:]))
+		offset += 4
+		if extensionType == 0 || offset+length > len(data) {
+			break
+		}
+		value := data[offset : offset+length]
+		offset += length
+
+		name, ok := mintExtensionNames[extensionType]
+		if !ok {
+			continue
+		}
+		extension := MintExtension{Name: name}
+
+		switch extensionType {
+		case extensionTransferFeeConfig:
+			if len(value) >= 108 {
+				extension.Fields = map[string]string{
+					"transfer_fee_config_authority": nonZeroKey(value[0:32]),
+					"withdraw_withheld_authority":   nonZeroKey(value[32:64]),
+					"epoch":                         uintString(binary.LittleEndian.Uint64(value[90:])),
+					"maximum_fee":                   uintString(binary.LittleEndian.Uint64(value[98:])),
+					"transfer_fee_basis_points":     uintString(uint64(binary.LittleEndian.Uint16(value[106:]))),
+				}
+			}
+		case extensionMintCloseAuthority:
+			extension.Fields = keyFields(value, "close_authority")
+		case extensionPermanentDelegate:
+			extension.Fields = keyFields(value, "delegate")
+		case extensionTransferHook:
+			extension.Fields = keyFields(value, "authority", "program_id")
+		case extensionMetadataPointer:
+			extension.Fields = keyFields(value, "authority", "metadata_address")
+			info.MetadataPointer = extension.Fields["metadata_address"]
+		case extensionTokenMetadata:
+			info.Metadata = decodeTokenMetadata(value)
+		}
+		info.Extensions = append(info.Extensions, extension)
+	}
+
+	return info, nil
+}
+
+// decodeTokenMetadata reads the Token-2022 TokenMetadata extension: update authority and
+// mint, followed by the borsh encoded name, symbol and uri.
+func decodeTokenMetadata(value []byte) *TokenMetadata {
+	reader := borshReader{data: value, offset: 64}
+	metadata := &TokenMetadata{Source: "token-2022"}
+	metadata.Name = reader.string()
+	metadata.Symbol = reader.string()
+	metadata.Uri = reader.string()
+	if reader.failed {
+		return nil
+	}
+	return metadata
+}
+
+// DecodeMetaplexMetadata reads name, symbol and uri from a Metaplex metadata account,
+// which follow the key byte, update authority and mint.
+func DecodeMetaplexMetadata(data []byte) (*TokenMetadata, error) {
+	reader := borshReader{data: data, offset: 1 + 32 + 32}
+	metadata := &TokenMetadata{Source: "metaplex"}
+	metadata.Name = reader.string()
+	metadata.Symbol = reader.string()
+	metadata.Uri = reader.string()
+	if reader.failed {
+		return nil, errors.New("invalid metaplex metadata account")
+	}
+	return metadata, nil
+}
+
+type borshReader struct {
+	data   []byte
+	offset int
+	failed bool
+}
+
+// string reads a u32 length prefixed string. Metaplex pads its strings with zero bytes.
+func (r *borshReader) string() string {
+	if r.failed || r.offset+4 > len(r.data) {
+		r.failed = true
+		return ""
+	}
+	length := int(binary.LittleEndian.Uint32(r.data[r.offset:]))
+	r.offset += 4
+	if r.offset+length > len(r.data) {
+		r.failed = true
+		return ""
+	}
+	value := string(r.data[r.offset : r.offset+length])
+	r.offset += length
+	return strings.TrimRight(value, "\x00")
+}
+
+// optionalKey decodes a COption<Pubkey>: a u32 tag followed by the key.
+func optionalKey(data []byte) string {
+	if binary.LittleEndian.Uint32(data) == 0 {
+		return ""
+	}
+	return solana.PublicKeyFromBytes(data[4:36]).String()
+}
+
+// nonZeroKey decodes an OptionalNonZeroPubkey, where all zeroes means none.
+func nonZeroKey(data []byte) string {
+	key := solana.PublicKeyFromBytes(data)
+	if key.IsZero() {
+		return ""
+	}
+	return key.String()
+}
+
+func keyFields(value []byte, names ...string) map[string]string {
+	if len(value) < 32*len(names) {
+		return nil
+	}
+	fields := make(map[string]string, len(names))
+	for i, name := range names {
+		fields[name] = nonZeroKey(value[i*32 : (i+1)*32])
+	}
+	return fields
+}
+
+func uintString(value uint64) string {
+	return strconv.FormatUint(value, 10)
+}
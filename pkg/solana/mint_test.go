@@ -0,0 +1,113 @@
+package solana
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func baseMint(authority solana.PublicKey, supply uint64, decimals uint8) []byte {
+	data := make([]byte, mintSize)
+	binary.LittleEndian.PutUint32(data[0:], 1)
+	copy(data[4:36], authority.Bytes())
+	binary.LittleEndian.PutUint64(data[36:], supply)
+	data[44] = decimals
+	data[45] = 1
+	return data
+}
+
+func borshString(value string) []byte {
+	data := binary.LittleEndian.AppendUint32(nil, uint32(len(value)))
+	return append(data, value...)
+}
+
+func tlv(extensionType uint16, value []byte) []byte {
+	data := binary.LittleEndian.AppendUint16(nil, extensionType)
+	data = binary.LittleEndian.AppendUint16(data, uint16(len(value)))
+	return append(data, value...)
+}
+
+func TestDecodeMint(t *testing.T) {
+	authority := solana.NewWallet().PublicKey()
+
+	info, err := DecodeMint(solana.TokenProgramID, baseMint(authority, 1_000_000, 6))
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1_000_000), info.Supply)
+	assert.Equal(t, uint8(6), info.Decimals)
+	assert.Equal(t, authority.String(), info.MintAuthority)
+	assert.Empty(t, info.FreezeAuthority)
+	assert.Empty(t, info.Extensions)
+}
+
+func TestDecodeToken2022Mint(t *testing.T) {
+	mint := solana.NewWallet().PublicKey()
+	delegate := solana.NewWallet().PublicKey()
+
+	data := append(baseMint(solana.PublicKey{}, 5, 9), make([]byte, token2022AccountTypeOffset-mintSize)...)
+	data[0] = 0
+	data = append(data, token2022AccountTypeMint)
+	data = append(data, tlv(extensionPermanentDelegate, delegate.Bytes())...)
+	data = append(data, tlv(extensionMetadataPointer, append(make([]byte, 32), mint.Bytes()...))...)
+
+	metadata := append(make([]byte, 32), mint.Bytes()...)
+	metadata = append(metadata, borshString("Example")...)
+	metadata = append(metadata, borshString("EXM")...)
+	metadata = append(metadata, borshString("https://example.com/exm.json")...)
+	metadata = append(metadata, 0, 0, 0, 0)
+	data = append(data, tlv(extensionTokenMetadata, metadata)...)
+
+	info, err := DecodeMint(solana.Token2022ProgramID, data)
+	require.NoError(t, err)
+	assert.Empty(t, info.MintAuthority)
+	assert.Equal(t, mint.String(), info.MetadataPointer)
+
+	require.Len(t, info.Extensions, 3)
+	assert.Equal(t, "permanent_delegate", info.Extensions[0].Name)
+	assert.Equal(t, delegate.String(), info.Extensions[0].Fields["delegate"])
+	assert.Equal(t, "", info.Extensions[1].Fields["authority"])
+
+	require.NotNil(t, info.Metadata)
+	assert.Equal(t, TokenMetadata{Source: "token-2022", Name: "Example", Symbol: "EXM", Uri: "https://example.com/exm.json"}, *info.Metadata)
+}
+
+func TestDecodeMint_RejectsTokenAccounts(t *testing.T) {
+	// a token account starts with its mint and owner, so byte 45 is rarely zero
+	account := make([]byte, token2022AccountTypeOffset)
+	copy(account[0:32], solana.NewWallet().PublicKey().Bytes())
+	copy(account[32:64], solana.NewWallet().PublicKey().Bytes())
+	account[45] = 1
+
+	_, err := DecodeMint(solana.TokenProgramID, account)
+	assert.ErrorIs(t, err, ErrNotAMint)
+	_, err = DecodeMint(solana.Token2022ProgramID, account)
+	assert.ErrorIs(t, err, ErrNotAMint)
+
+	withExtensions := append(account, token2022AccountTypeAccount)
+	withExtensions = append(withExtensions, tlv(extensionPermanentDelegate, make([]byte, 32))...)
+	_, err = DecodeMint(solana.Token2022ProgramID, withExtensions)
+	assert.ErrorIs(t, err, ErrNotAMint)
+
+	mint := append(baseMint(solana.PublicKey{}, 5, 9), make([]byte, token2022AccountTypeOffset-mintSize)...)
+	mint = append(mint, token2022AccountTypeMint)
+	_, err = DecodeMint(solana.TokenProgramID, mint)
+	assert.ErrorIs(t, err, ErrNotAMint, "only Token-2022 mints carry extensions")
+}
+
+func TestDecodeMetaplexMetadata(t *testing.T) {
+	data := make([]byte, 1+32+32)
+	data = append(data, borshString("Padded\x00\x00\x00")...)
+	data = append(data, borshString("PAD\x00")...)
+	data = append(data, borshString("ipfs://x\x00\x00")...)
+
+	metadata, err := DecodeMetaplexMetadata(data)
+	require.NoError(t, err)
+	assert.Equal(t, "Padded", metadata.Name)
+	assert.Equal(t, "PAD", metadata.Symbol)
+	assert.Equal(t, "ipfs://x", metadata.Uri)
+
+	_, err = DecodeMetaplexMetadata(data[:70])
+	assert.Error(t, err)
+}
@@ -0,0 +1,149 @@
+package solana
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"net/url"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// NewReference returns a random public key used to tag a Solana Pay transfer so the
+// payment can be found on-chain. It has no private key anyone needs to keep.
+func NewReference() string {
+	return solana.NewWallet().PublicKey().String()
+}
+
+// PaymentURL encodes a Solana Pay transfer request. Empty optional fields are omitted.
+func PaymentURL(recipient, amount, mint, reference, label, message, memo string) string {
+	query := url.Values{}
+	query.Set("amount", amount)
+	if mint != "" {
+		query.Set("spl-token", mint)
+	}
+	query.Set("reference", reference)
+	if label != "" {
+		query.Set("label", label)
+	}
+	if message != "" {
+		query.Set("message", message)
+	}
+	if memo != "" {
+		query.Set("memo", memo)
+	}
+
+	return "solana:" + recipient + "?" + query.Encode()
+}
+
+// GetReferenceSignatures returns the confirmed, successful transactions that include reference.
+func (s *SolClient) GetReferenceSignatures(reference string) ([]string, error) {
+	pubKey, err := solana.PublicKeyFromBase58(reference)
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := s.Client.GetSignaturesForAddressWithOpts(
+		context.TODO(),
+		pubKey,
+		&rpc.GetSignaturesForAddressOpts{Commitment: rpc.CommitmentConfirmed},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var signatures []string
+	for _, sig := range out {
+		if sig.Err == nil {
+			signatures = append(signatures, sig.Signature.String())
+		}
+	}
+
+	return signatures, nil
+}
+
+// GetReceivedAmount returns how much recipient gained in the transaction, in lamports
+// when mint is empty and in token base units otherwise, along with the slot.
+func (s *SolClient) GetReceivedAmount(signature, recipient, mint string) (uint64, uint64, error) {
+	sig, err := solana.SignatureFromBase58(signature)
+	if err != nil {
+		return 0, 0, err
+	}
+	recipientKey, err := solana.PublicKeyFromBase58(recipient)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	maxVersion := uint64(0)
+	out, err := s.Client.GetTransaction(context.TODO(), sig, &rpc.GetTransactionOpts{
+		Encoding:                       solana.EncodingBase64,
+		Commitment:                     rpc.CommitmentConfirmed,
+		MaxSupportedTransactionVersion: &maxVersion,
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+	if out.Meta == nil || out.Meta.Err != nil {
+		return 0, out.Slot, errors.New("transaction failed")
+	}
+
+	if mint != "" {
+		mintKey, err := solana.PublicKeyFromBase58(mint)
+		if err != nil {
+			return 0, 0, err
+		}
+		return tokenReceived(out.Meta, recipientKey, mintKey), out.Slot, nil
+	}
+
+	tx, err := out.Transaction.GetTransaction()
+	if err != nil {
+		return 0, 0, err
+	}
+	keys := append(solana.PublicKeySlice{}, tx.Message.AccountKeys...)
+	keys = append(keys, out.Meta.LoadedAddresses.Writable...)
+	keys = append(keys, out.Meta.LoadedAddresses.ReadOnly...)
+
+	return lamportsReceived(keys, out.Meta, recipientKey), out.Slot, nil
+}
+
+func lamportsReceived(keys solana.PublicKeySlice, meta *rpc.TransactionMeta, recipient solana.PublicKey) uint64 {
+	for i, key := range keys {
+		if !key.Equals(recipient) || i >= len(meta.PreBalances) || i >= len(meta.PostBalances) {
+			continue
+		}
+		if meta.PostBalances[i] > meta.PreBalances[i] {
+			return meta.PostBalances[i] - meta.PreBalances[i]
+		}
+		return 0
+	}
+	return 0
+}
+
+// tokenReceived sums the balance change over every token account of mint owned by recipient.
+func tokenReceived(meta *rpc.TransactionMeta, recipient, mint solana.PublicKey) uint64 {
+	total := new(big.Int)
+	sum := func(balances []rpc.TokenBalance, sign int) {
+		for _, balance := range balances {
+			if balance.Owner == nil || !balance.Owner.Equals(recipient) || !balance.Mint.Equals(mint) || balance.UiTokenAmount == nil {
+				continue
+			}
+			amount, ok := new(big.Int).SetString(balance.UiTokenAmount.Amount, 10)
+			if !ok {
+				continue
+			}
+			if sign < 0 {
+				total.Sub(total, amount)
+			} else {
+				total.Add(total, amount)
+			}
+		}
+	}
+	sum(meta.PostTokenBalances, 1)
+	sum(meta.PreTokenBalances, -1)
+
+	if total.Sign() <= 0 || !total.IsUint64() {
+		return 0
+	}
+	return total.Uint64()
+}
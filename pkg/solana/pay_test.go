@@ -0,0 +1,54 @@
+package solana
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPaymentURL(t *testing.T) {
+	link := PaymentURL("recipient", "1.5", "mint", "ref", "Shop", "Thanks!", "")
+
+	parsed, err := url.Parse(link)
+	require.NoError(t, err)
+	assert.Equal(t, "solana", parsed.Scheme)
+	assert.Equal(t, "recipient", parsed.Opaque)
+	assert.Equal(t, "1.5", parsed.Query().Get("amount"))
+	assert.Equal(t, "mint", parsed.Query().Get("spl-token"))
+	assert.Equal(t, "ref", parsed.Query().Get("reference"))
+	assert.Equal(t, "Thanks!", parsed.Query().Get("message"))
+	assert.False(t, parsed.Query().Has("memo"))
+}
+
+func TestLamportsReceived(t *testing.T) {
+	payer := solana.NewWallet().PublicKey()
+	recipient := solana.NewWallet().PublicKey()
+	meta := &rpc.TransactionMeta{
+		PreBalances:  []uint64{10_000, 500},
+		PostBalances: []uint64{4_000, 6_000},
+	}
+
+	assert.Equal(t, uint64(5_500), lamportsReceived(solana.PublicKeySlice{payer, recipient}, meta, recipient))
+	assert.Equal(t, uint64(0), lamportsReceived(solana.PublicKeySlice{payer, recipient}, meta, payer))
+}
+
+func TestTokenReceived(t *testing.T) {
+	recipient := solana.NewWallet().PublicKey()
+	other := solana.NewWallet().PublicKey()
+	mint := solana.NewWallet().PublicKey()
+	balance := func(owner solana.PublicKey, amount string) rpc.TokenBalance {
+		return rpc.TokenBalance{Owner: &owner, Mint: mint, UiTokenAmount: &rpc.UiTokenAmount{Amount: amount}}
+	}
+
+	meta := &rpc.TransactionMeta{
+		PreTokenBalances:  []rpc.TokenBalance{balance(other, "1000"), balance(recipient, "10")},
+		PostTokenBalances: []rpc.TokenBalance{balance(other, "750"), balance(recipient, "260")},
+	}
+
+	assert.Equal(t, uint64(250), tokenReceived(meta, recipient, mint))
+	assert.Equal(t, uint64(0), tokenReceived(meta, recipient, solana.NewWallet().PublicKey()))
+}
@@ -0,0 +1,74 @@
+package solana
+
+import (
+	"context"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// stakeWithdrawerOffset is the position of Meta.authorized.withdrawer in a stake account:
+// a u32 state tag, the u64 rent exempt reserve and the 32 byte staker precede it.
+const stakeWithdrawerOffset = 4 + 8 + 32
+
+// GetStakedLamports sums the lamports of every stake account whose withdraw authority is
+// owner, which is who the staked SOL belongs to. Rent reserves are included.
+func (s *SolClient) GetStakedLamports(owner string) (uint64, error) {
+	ownerKey, err := solana.PublicKeyFromBase58(owner)
+	if err != nil {
+		return 0, err
+	}
+
+	zero := uint64(0)
+	out, err := s.Client.GetProgramAccountsWithOpts(context.TODO(), solana.StakeProgramID, &rpc.GetProgramAccountsOpts{
+		Commitment: rpc.CommitmentFinalized,
+		DataSlice:  &rpc.DataSlice{Offset: &zero, Length: &zero},
+		Filters: []rpc.RPCFilter{{
+			Memcmp: &rpc.RPCFilterMemcmp{Offset: stakeWithdrawerOffset, Bytes: ownerKey.Bytes()},
+		}},
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	total := uint64(0)
+	for _, account := range out {
+		total += account.Account.Lamports
+	}
+
+	return total, nil
+}
+
+// GetAccountsData returns the raw data of each existing account, batching
+// getMultipleAccounts calls, and the slot the last batch was read at.
+func (s *SolClient) GetAccountsData(addresses []string) (map[string][]byte, uint64, error) {
+	keys := make([]solana.PublicKey, 0, len(addresses))
+	for _, address := range addresses {
+		pubKey, err := solana.PublicKeyFromBase58(address)
+		if err != nil {
+			return nil, 0, err
+		}
+		keys = append(keys, pubKey)
+	}
+
+	slot := uint64(0)
+	data := make(map[string][]byte, len(keys))
+	for start := 0; start < len(keys); start += maxAccountsPerRequest {
+		batch := keys[start:min(start+maxAccountsPerRequest, len(keys))]
+		out, err := s.Client.GetMultipleAccountsWithOpts(context.TODO(), batch, &rpc.GetMultipleAccountsOpts{
+			Commitment: rpc.CommitmentFinalized,
+		})
+		if err != nil {
+			return nil, 0, err
+		}
+		slot = out.Context.Slot
+
+		for i, account := range out.Value {
+			if account != nil {
+				data[batch[i].String()] = account.Data.GetBinary()
+			}
+		}
+	}
+
+	return data, slot, nil
+}
@@ -0,0 +1,91 @@
+package solana
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"strings"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// TokenHolding is the balance of one token account. Amount is the raw integer amount in base units.
+type TokenHolding struct {
+	Mint     string
+	Amount   *big.Int
+	Decimals uint8
+}
+
+type parsedTokenAccount struct {
+	Parsed struct {
+		Info struct {
+			Mint        string `json:"mint"`
+			TokenAmount struct {
+				Amount   string `json:"amount"`
+				Decimals uint8  `json:"decimals"`
+			} `json:"tokenAmount"`
+		} `json:"info"`
+	} `json:"parsed"`
+}
+
+// GetTokenHoldings returns every non-empty token account of owner, for both the Token and
+// Token-2022 programs.
+func (s *SolClient) GetTokenHoldings(owner string) ([]TokenHolding, error) {
+	ownerKey, err := solana.PublicKeyFromBase58(owner)
+	if err != nil {
+		return nil, err
+	}
+
+	var holdings []TokenHolding
+	for _, program := range []solana.PublicKey{solana.TokenProgramID, solana.Token2022ProgramID} {
+		out, err := s.Client.GetTokenAccountsByOwner(
+			context.TODO(),
+			ownerKey,
+			&rpc.GetTokenAccountsConfig{ProgramId: program.ToPointer()},
+			&rpc.GetTokenAccountsOpts{Commitment: rpc.CommitmentFinalized, Encoding: solana.EncodingJSONParsed},
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, account := range out.Value {
+			var parsed parsedTokenAccount
+			if err = json.Unmarshal(account.Account.Data.GetRawJSON(), &parsed); err != nil {
+				return nil, err
+			}
+
+			info := parsed.Parsed.Info
+			amount, ok := new(big.Int).SetString(info.TokenAmount.Amount, 10)
+			if !ok || amount.Sign() == 0 {
+				continue
+			}
+			holdings = append(holdings, TokenHolding{
+				Mint:     info.Mint,
+				Amount:   amount,
+				Decimals: info.TokenAmount.Decimals,
+			})
+		}
+	}
+
+	return holdings, nil
+}
+
+// FormatAmount renders an integer amount of base units as an exact decimal string.
+func FormatAmount(amount *big.Int, decimals uint8) string {
+	digits := new(big.Int).Abs(amount).String()
+	if decimals > 0 {
+		if len(digits) <= int(decimals) {
+			digits = strings.Repeat("0", int(decimals)-len(digits)+1) + digits
+		}
+		point := len(digits) - int(decimals)
+		digits = digits[:point] + "." + digits[point:]
+	}
+	if amount.Sign() < 0 {
+		return "-" + digits
+	}
+	return digits
+}
+
+// WrappedSolMint is the native mint, used as the price key for SOL.
+const WrappedSolMint = "So11111111111111111111111111111111111111112"
@@ -0,0 +1,17 @@
+package solana
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatAmount(t *testing.T) {
+	assert.Equal(t, "1.250000000", FormatAmount(big.NewInt(1_250_000_000), 9))
+	assert.Equal(t, "0.000001", FormatAmount(big.NewInt(1), 6))
+	assert.Equal(t, "42", FormatAmount(big.NewInt(42), 0))
+
+	huge, _ := new(big.Int).SetString("36893488147419103231", 10)
+	assert.Equal(t, "36893488147.419103231", FormatAmount(huge, 9))
+}
@@ -0,0 +1,61 @@
+package solana
+
+import (
+	"context"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// SendRawTransaction submits an already signed transaction. Retries are left to the
+// caller, so the node is told not to rebroadcast on its own.
+func (s *SolClient) SendRawTransaction(rawTx []byte, skipPreflight bool) (string, error) {
+	maxRetries := uint(0)
+	sig, err := s.Client.SendRawTransactionWithOpts(
+		context.TODO(),
+		rawTx,
+		rpc.TransactionOpts{
+			SkipPreflight:       skipPreflight,
+			PreflightCommitment: rpc.CommitmentProcessed,
+			MaxRetries:          &maxRetries,
+		},
+	)
+	if err != nil {
+		return "", err
+	}
+
+	return sig.String(), nil
+}
+
+// GetSignatureStatus returns nil without an error when the cluster has not seen the signature yet.
+// Without searchHistory only the recent status cache of roughly the last 150 slots is searched.
+func (s *SolClient) GetSignatureStatus(signature string, searchHistory bool) (*rpc.SignatureStatusesResult, error) {
+	sig, err := solana.SignatureFromBase58(signature)
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := s.Client.GetSignatureStatuses(context.TODO(), searchHistory, sig)
+	if err != nil {
+		return nil, err
+	}
+	if len(out.Value) == 0 {
+		return nil, nil
+	}
+
+	return out.Value[0], nil
+}
+
+func (s *SolClient) IsBlockhashValid(blockhash string) (bool, error) {
+	hash, err := solana.HashFromBase58(blockhash)
+	if err != nil {
+		return false, err
+	}
+
+	out, err := s.Client.IsBlockhashValid(context.TODO(), hash, rpc.CommitmentProcessed)
+	if err != nil {
+		return false, err
+	}
+
+	return out.Value, nil
+}
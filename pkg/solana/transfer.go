@@ -0,0 +1,219 @@
+package solana
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	bin "github.com/gagliardetto/binary"
+	"github.com/gagliardetto/solana-go"
+	associatedtokenaccount "github.com/gagliardetto/solana-go/programs/associated-token-account"
+	computebudget "github.com/gagliardetto/solana-go/programs/compute-budget"
+	"github.com/gagliardetto/solana-go/programs/memo"
+	"github.com/gagliardetto/solana-go/programs/system"
+	"github.com/gagliardetto/solana-go/programs/token"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+var ErrUnsupportedMint = errors.New("only SPL Token program mints are supported")
+
+type TransferParams struct {
+	From string
+	To   string
+	// Mint is empty for native SOL transfers.
+	Mint string
+	// Amount is in base units (lamports or the token's smallest unit).
+	Amount   uint64
+	Decimals uint8
+	// CreateRecipientAccount prepends creation of the recipient's associated token account, paid by From.
+	CreateRecipientAccount bool
+	Memo                   string
+	ComputeUnitLimit       uint32
+	ComputeUnitPrice       uint64
+	Blockhash              string
+}
+
+// BuildTransferTransaction assembles an unsigned transfer with From as fee payer and
+// returns it base64 encoded, ready to be signed by the client.
+func BuildTransferTransaction(params TransferParams) (string, error) {
+	from, err := solana.PublicKeyFromBase58(params.From)
+	if err != nil {
+		return "", fmt.Errorf("invalid sender: %w", err)
+	}
+	to, err := solana.PublicKeyFromBase58(params.To)
+	if err != nil {
+		return "", fmt.Errorf("invalid recipient: %w", err)
+	}
+	blockhash, err := solana.HashFromBase58(params.Blockhash)
+	if err != nil {
+		return "", err
+	}
+
+	instructions := []solana.Instruction{
+		computebudget.NewSetComputeUnitLimitInstruction(params.ComputeUnitLimit).Build(),
+		computebudget.NewSetComputeUnitPriceInstruction(params.ComputeUnitPrice).Build(),
+	}
+
+	if params.Mint == "" {
+		instructions = append(instructions,
+			system.NewTransferInstruction(params.Amount, from, to).Build(),
+		)
+	} else {
+		mint, err := solana.PublicKeyFromBase58(params.Mint)
+		if err != nil {
+			return "", fmt.Errorf("invalid mint: %w", err)
+		}
+		source, _, err := solana.FindAssociatedTokenAddress(from, mint)
+		if err != nil {
+			return "", err
+		}
+		destination, _, err := solana.FindAssociatedTokenAddress(to, mint)
+		if err != nil {
+			return "", err
+		}
+
+		if params.CreateRecipientAccount {
+			instructions = append(instructions,
+				associatedtokenaccount.NewCreateInstruction(from, to, mint).Build(),
+			)
+		}
+		instructions = append(instructions,
+			token.NewTransferCheckedInstruction(
+				params.Amount,
+				params.Decimals,
+				source,
+				mint,
+				destination,
+				from,
+				nil,
+			).Build(),
+		)
+	}
+
+	if params.Memo != "" {
+		instructions = append(instructions,
+			memo.NewMemoInstruction([]byte(params.Memo), from).Build(),
+		)
+	}
+
+	tx, err := solana.NewTransaction(instructions, blockhash, solana.TransactionPayer(from))
+	if err != nil {
+		return "", err
+	}
+
+	// wallets expect a zeroed slot for every required signature
+	tx.Signatures = make([]solana.Signature, tx.Message.Header.NumRequiredSignatures)
+
+	raw, err := tx.MarshalBinary()
+	if err != nil {
+		return "", err
+	}
+
+	return base64.StdEncoding.EncodeToString(raw), nil
+}
+
+// ParseAmount converts a plain decimal amount such as "1.25" into base units without
+// going through floating point. Signs, exponents, fractions such as "1/2" and more
+// decimal places than the mint has are rejected.
+func ParseAmount(amount string, decimals uint8) (uint64, error) {
+	amount = strings.TrimSpace(amount)
+	whole, fraction, hasPoint := strings.Cut(amount, ".")
+	if !isDigits(whole) || (hasPoint && !isDigits(fraction)) {
+		return 0, fmt.Errorf("invalid amount %q", amount)
+	}
+	if len(fraction) > int(decimals) {
+		return 0, fmt.Errorf("amount %q has more than %d decimal places", amount, decimals)
+	}
+
+	digits := whole + fraction + strings.Repeat("0", int(decimals)-len(fraction))
+	value, _ := new(big.Int).SetString(digits, 10)
+	if value.Sign() <= 0 {
+		return 0, fmt.Errorf("invalid amount %q", amount)
+	}
+	if !value.IsUint64() {
+		return 0, fmt.Errorf("amount %q is too large", amount)
+	}
+
+	return value.Uint64(), nil
+}
+
+func isDigits(value string) bool {
+	if value == "" {
+		return false
+	}
+	for _, r := range value {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *SolClient) GetLatestBlockhash() (*rpc.LatestBlockhashResult, error) {
+	out, err := s.Client.GetLatestBlockhash(context.TODO(), rpc.CommitmentFinalized)
+	if err != nil {
+		return nil, err
+	}
+
+	return out.Value, nil
+}
+
+func (s *SolClient) GetMintDecimals(mint string) (uint8, error) {
+	pubKey, err := solana.PublicKeyFromBase58(mint)
+	if err != nil {
+		return 0, err
+	}
+
+	out, err := s.Client.GetAccountInfo(context.TODO(), pubKey)
+	if err != nil {
+		return 0, err
+	}
+	if !out.Value.Owner.Equals(solana.TokenProgramID) {
+		return 0, ErrUnsupportedMint
+	}
+
+	var decoded token.Mint
+	if err = bin.NewBinDecoder(out.GetBinary()).Decode(&decoded); err != nil {
+		return 0, err
+	}
+
+	return decoded.Decimals, nil
+}
+
+func (s *SolClient) AccountExists(address string) (bool, error) {
+	pubKey, err := solana.PublicKeyFromBase58(address)
+	if err != nil {
+		return false, err
+	}
+
+	_, err = s.Client.GetAccountInfo(context.TODO(), pubKey)
+	if errors.Is(err, rpc.ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func AssociatedTokenAddress(owner, mint string) (string, error) {
+	ownerKey, err := solana.PublicKeyFromBase58(owner)
+	if err != nil {
+		return "", err
+	}
+	mintKey, err := solana.PublicKeyFromBase58(mint)
+	if err != nil {
+		return "", err
+	}
+
+	address, _, err := solana.FindAssociatedTokenAddress(ownerKey, mintKey)
+	if err != nil {
+		return "", err
+	}
+
+	return address.String(), nil
+}
@@ -0,0 +1,62 @@
+package solana
+
+import (
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAmount(t *testing.T) {
+	amount, err := ParseAmount("1.25", 9)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1_250_000_000), amount)
+
+	amount, err = ParseAmount("0.000001", 6)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), amount)
+
+	_, err = ParseAmount("0.0000001", 6)
+	assert.Error(t, err, "more precision than the mint supports")
+
+	_, err = ParseAmount("-1", 9)
+	assert.Error(t, err)
+
+	_, err = ParseAmount("abc", 9)
+	assert.Error(t, err)
+
+	for _, amount := range []string{"1/2", "1e3", "+1", "0x10", ".5", "1.", "0", "0.000"} {
+		_, err = ParseAmount(amount, 9)
+		assert.Error(t, err, amount)
+	}
+
+	_, err = ParseAmount("18446744073709551616", 0)
+	assert.Error(t, err, "overflows uint64")
+}
+
+func TestBuildTransferTransaction_SPLWithAccountCreation(t *testing.T) {
+	from := solana.NewWallet().PublicKey()
+	to := solana.NewWallet().PublicKey()
+	mint := solana.NewWallet().PublicKey()
+
+	encoded, err := BuildTransferTransaction(TransferParams{
+		From:                   from.String(),
+		To:                     to.String(),
+		Mint:                   mint.String(),
+		Amount:                 5,
+		Decimals:               6,
+		CreateRecipientAccount: true,
+		Memo:                   "invoice 42",
+		ComputeUnitLimit:       80_000,
+		ComputeUnitPrice:       1_000,
+		Blockhash:              solana.Hash{1}.String(),
+	})
+	require.NoError(t, err)
+
+	tx, err := solana.TransactionFromBase64(encoded)
+	require.NoError(t, err)
+	assert.Len(t, tx.Message.Instructions, 5)
+	assert.Equal(t, from, tx.Message.AccountKeys[0], "sender pays the fee")
+	assert.True(t, tx.Signatures[0].IsZero(), "transaction must be left unsigned")
+}
@@ -0,0 +1,295 @@
+package stream
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"main/pkg/config"
+	"main/pkg/solana"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	pingInterval = 30 * time.Second
+	readTimeout  = 60 * time.Second
+	maxBackoff   = 30 * time.Second
+)
+
+// Subscribe registers listener for balance changes of wallet. The first listener of a
+// wallet opens the upstream accountSubscribe, later ones share it. Updates are dropped
+// for listeners whose channel is full, so a slow consumer cannot stall the others.
+func Subscribe(wallet string, listener chan BalanceUpdate) error {
+	if !solana.IsValidAddress(wallet) {
+		return errors.New("invalid wallet address: " + wallet)
+	}
+	startOnce.Do(func() {
+		go maintainConnection()
+	})
+
+	walletsMutex.Lock()
+	defer walletsMutex.Unlock()
+
+	sub, exists := wallets[wallet]
+	if !exists {
+		sub = &walletSubscription{listeners: make(map[chan BalanceUpdate]struct{})}
+		wallets[wallet] = sub
+		sendSubscribe(wallet)
+	}
+	sub.listeners[listener] = struct{}{}
+
+	return nil
+}
+
+// Unsubscribe removes listener and closes the upstream subscription once nobody listens.
+func Unsubscribe(wallet string, listener chan BalanceUpdate) {
+	walletsMutex.Lock()
+	defer walletsMutex.Unlock()
+
+	sub, exists := wallets[wallet]
+	if !exists {
+		return
+	}
+	delete(sub.listeners, listener)
+	if len(sub.listeners) > 0 {
+		return
+	}
+
+	delete(wallets, wallet)
+	if sub.subscriptionID != 0 {
+		delete(subscriptionIDs, sub.subscriptionID)
+		sendUnsubscribe(sub.subscriptionID)
+	}
+}
+
+// OnDisconnect registers handler to run whenever the upstream connection drops, as
+// notifications sent while reconnecting are lost.
+func OnDisconnect(handler func()) {
+	walletsMutex.Lock()
+	defer walletsMutex.Unlock()
+
+	disconnectHandlers = append(disconnectHandlers, handler)
+}
+
+// maintainConnection keeps the PubSub websocket open, resubscribing every wallet
+// after each reconnect.
+func maintainConnection() {
+	backoff := time.Second
+	for {
+		c, _, err := websocket.DefaultDialer.Dial(config.Config.RpcWsUri, nil)
+		if err != nil {
+			log.Println("Error connecting to Solana websocket:", err)
+			time.Sleep(backoff)
+			backoff = min(backoff*2, maxBackoff)
+			continue
+		}
+		backoff = time.Second
+
+		walletsMutex.Lock()
+		conn = c
+		pendingRequests = make(map[uint64]string)
+		subscriptionIDs = make(map[uint64]string)
+		outgoing = nil
+		for wallet, sub := range wallets {
+			sub.subscriptionID = 0
+			sendSubscribe(wallet)
+		}
+		walletsMutex.Unlock()
+
+		stop := make(chan struct{})
+		go keepAlive(c, stop)
+		go writeMessages(c, stop)
+		err = readMessages(c)
+		close(stop)
+		log.Println("Solana websocket disconnected, reconnecting:", err)
+
+		walletsMutex.Lock()
+		conn = nil
+		for _, sub := range wallets {
+			sub.subscriptionID = 0
+		}
+		handlers := append([]func(){}, disconnectHandlers...)
+		walletsMutex.Unlock()
+		_ = c.Close()
+
+		for _, handler := range handlers {
+			handler()
+		}
+	}
+}
+
+func keepAlive(c *websocket.Conn, stop chan struct{}) {
+	ticker := time.NewTicker(pingInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			if err := c.WriteControl(websocket.PingMessage, nil, time.Now().Add(5*time.Second)); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// writeMessages writes the queued requests. A write that fails or stalls past its
+// deadline closes the connection, which makes maintainConnection reconnect.
+func writeMessages(c *websocket.Conn, stop chan struct{}) {
+	for {
+		select {
+		case <-stop:
+			return
+		case <-outgoingReady:
+		}
+
+		walletsMutex.Lock()
+		requests := outgoing
+		outgoing = nil
+		walletsMutex.Unlock()
+
+		for _, request := range requests {
+			_ = c.SetWriteDeadline(time.Now().Add(5 * time.Second))
+			if err := c.WriteJSON(request); err != nil {
+				log.Println("Error writing to Solana websocket:", err)
+				_ = c.Close()
+				return
+			}
+		}
+	}
+}
+
+func readMessages(c *websocket.Conn) error {
+	_ = c.SetReadDeadline(time.Now().Add(readTimeout))
+	c.SetPongHandler(func(string) error {
+		return c.SetReadDeadline(time.Now().Add(readTimeout))
+	})
+
+	for {
+		_, data, err := c.ReadMessage()
+		if err != nil {
+			return err
+		}
+		_ = c.SetReadDeadline(time.Now().Add(readTimeout))
+
+		var msg rpcMessage
+		if err = json.Unmarshal(data, &msg); err != nil {
+			log.Println("Error decoding Solana websocket message:", err)
+			continue
+		}
+		handleMessage(&msg)
+	}
+}
+
+func handleMessage(msg *rpcMessage) {
+	walletsMutex.Lock()
+	defer walletsMutex.Unlock()
+
+	if msg.ID != nil {
+		wallet, pending := pendingRequests[*msg.ID]
+		if !pending {
+			return
+		}
+		delete(pendingRequests, *msg.ID)
+		if msg.Error != nil {
+			log.Println("Error subscribing to " + wallet + ": " + msg.Error.Message)
+			rejectSubscription(wallet, msg.Error.Message)
+			return
+		}
+		id, ok := msg.Result.(float64)
+		if !ok {
+			return
+		}
+		subscriptionID := uint64(id)
+
+		sub, exists := wallets[wallet]
+		if !exists {
+			// everyone left before the subscription was confirmed
+			sendUnsubscribe(subscriptionID)
+			return
+		}
+		sub.subscriptionID = subscriptionID
+		subscriptionIDs[subscriptionID] = wallet
+		return
+	}
+
+	if msg.Method != "accountNotification" || msg.Params == nil || msg.Params.Result.Value == nil {
+		return
+	}
+	wallet, exists := subscriptionIDs[msg.Params.Subscription]
+	if !exists {
+		return
+	}
+	update := BalanceUpdate{
+		Wallet:   wallet,
+		Lamports: msg.Params.Result.Value.Lamports,
+		Slot:     msg.Params.Result.Context.Slot,
+	}
+	for listener := range wallets[wallet].listeners {
+		select {
+		case listener <- update:
+		default:
+		}
+	}
+}
+
+// rejectSubscription drops a wallet whose subscription the upstream refused and tells
+// its listeners, which would otherwise wait for updates that never come. It must be
+// called with walletsMutex held.
+func rejectSubscription(wallet, reason string) {
+	sub, exists := wallets[wallet]
+	if !exists {
+		return
+	}
+	delete(wallets, wallet)
+
+	update := BalanceUpdate{Wallet: wallet, Error: "subscription rejected: " + reason}
+	for listener := range sub.listeners {
+		select {
+		case listener <- update:
+		default:
+		}
+	}
+}
+
+// sendSubscribe and sendUnsubscribe must be called with walletsMutex held. They are
+// no-ops while disconnected, maintainConnection resubscribes once the socket is back.
+func sendSubscribe(wallet string) {
+	if conn == nil {
+		return
+	}
+	nextRequestID++
+	pendingRequests[nextRequestID] = wallet
+	send(rpcRequest{
+		JsonRpc: "2.0",
+		ID:      nextRequestID,
+		Method:  "accountSubscribe",
+		Params: []any{wallet, map[string]string{
+			"encoding":   "base64",
+			"commitment": "confirmed",
+		}},
+	})
+}
+
+func sendUnsubscribe(subscriptionID uint64) {
+	if conn == nil {
+		return
+	}
+	nextRequestID++
+	send(rpcRequest{
+		JsonRpc: "2.0",
+		ID:      nextRequestID,
+		Method:  "accountUnsubscribe",
+		Params:  []any{subscriptionID},
+	})
+}
+
+// send queues the request for writeMessages, it must be called with walletsMutex held.
+func send(request rpcRequest) {
+	outgoing = append(outgoing, request)
+	select {
+	case outgoingReady <- struct{}{}:
+	default:
+	}
+}
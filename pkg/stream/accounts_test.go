@@ -0,0 +1,96 @@
+package stream
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testWallet = "11111111111111111111111111111111"
+
+func init() {
+	// keep the tests offline, maintainConnection is never started
+	startOnce.Do(func() {})
+}
+
+func confirmSubscription(requestID, subscriptionID uint64) {
+	handleMessage(&rpcMessage{ID: &requestID, Result: float64(subscriptionID)})
+}
+
+func notify(subscriptionID, lamports, slot uint64) {
+	msg := &rpcMessage{
+		Method: "accountNotification",
+		Params: &accountNotification{
+			Subscription: subscriptionID,
+			Result:       accountResult{Value: &accountValue{Lamports: lamports}},
+		},
+	}
+	msg.Params.Result.Context.Slot = slot
+	handleMessage(msg)
+}
+
+func TestSubscribe_SharesUpstreamAndFansOut(t *testing.T) {
+	first := make(chan BalanceUpdate, 1)
+	second := make(chan BalanceUpdate, 1)
+
+	require.NoError(t, Subscribe(testWallet, first))
+	require.NoError(t, Subscribe(testWallet, second))
+	assert.Len(t, wallets, 1)
+	assert.Len(t, wallets[testWallet].listeners, 2)
+
+	// pretend the upstream confirmed the subscription
+	walletsMutex.Lock()
+	pendingRequests[7] = testWallet
+	walletsMutex.Unlock()
+	confirmSubscription(7, 99)
+
+	notify(99, 5_000, 1234)
+	assert.Equal(t, BalanceUpdate{Wallet: testWallet, Lamports: 5_000, Slot: 1234}, <-first)
+	assert.Equal(t, BalanceUpdate{Wallet: testWallet, Lamports: 5_000, Slot: 1234}, <-second)
+
+	Unsubscribe(testWallet, first)
+	assert.Len(t, wallets, 1, "wallet stays subscribed while a listener remains")
+
+	Unsubscribe(testWallet, second)
+	assert.Empty(t, wallets)
+	assert.Empty(t, subscriptionIDs)
+}
+
+func TestSubscribe_RejectsInvalidAddress(t *testing.T) {
+	assert.Error(t, Subscribe("not-a-wallet", make(chan BalanceUpdate)))
+}
+
+func TestHandleMessage_DropsUpdatesForFullListeners(t *testing.T) {
+	listener := make(chan BalanceUpdate)
+	require.NoError(t, Subscribe(testWallet, listener))
+	defer Unsubscribe(testWallet, listener)
+
+	walletsMutex.Lock()
+	pendingRequests[8] = testWallet
+	walletsMutex.Unlock()
+	confirmSubscription(8, 100)
+
+	// would block forever if updates were not dropped
+	notify(100, 1, 1)
+}
+
+func TestHandleMessage_RejectedSubscriptionNotifiesListeners(t *testing.T) {
+	listener := make(chan BalanceUpdate, 1)
+	require.NoError(t, Subscribe(testWallet, listener))
+
+	walletsMutex.Lock()
+	pendingRequests[9] = testWallet
+	walletsMutex.Unlock()
+	requestID := uint64(9)
+	msg := &rpcMessage{ID: &requestID}
+	msg.Error = &struct {
+		Message string `json:"message"`
+	}{Message: "Invalid param"}
+	handleMessage(msg)
+
+	update := <-listener
+	assert.Equal(t, testWallet, update.Wallet)
+	assert.Contains(t, update.Error, "Invalid param")
+	assert.Empty(t, wallets, "a rejected wallet is dropped so it can be subscribed again")
+}
@@ -0,0 +1,74 @@
+package stream
+
+import (
+	"sync"
+
+	"github.com/gorilla/websocket"
+)
+
+var (
+	wallets      = make(map[string]*walletSubscription)
+	walletsMutex = sync.Mutex{}
+
+	// connection state, only touched while holding walletsMutex
+	conn            *websocket.Conn
+	nextRequestID   uint64
+	pendingRequests = make(map[uint64]string)
+	subscriptionIDs = make(map[uint64]string)
+	// outgoing queues requests for writeMessages, so nothing blocks on the socket while
+	// holding walletsMutex
+	outgoing      []rpcRequest
+	outgoingReady = make(chan struct{}, 1)
+
+	disconnectHandlers []func()
+
+	startOnce sync.Once
+)
+
+type BalanceUpdate struct {
+	Wallet   string
+	Lamports uint64
+	Slot     uint64
+	// Error is set when the upstream rejected the subscription. The wallet is no longer
+	// subscribed and no updates follow.
+	Error string
+}
+
+// walletSubscription is one upstream accountSubscribe shared by every listener of a wallet.
+type walletSubscription struct {
+	listeners      map[chan BalanceUpdate]struct{}
+	subscriptionID uint64
+}
+
+type rpcRequest struct {
+	JsonRpc string `json:"jsonrpc"`
+	ID      uint64 `json:"id"`
+	Method  string `json:"method"`
+	Params  []any  `json:"params"`
+}
+
+type rpcMessage struct {
+	ID     *uint64 `json:"id"`
+	Result any     `json:"result"`
+	Error  *struct {
+		Message string `json:"message"`
+	} `json:"error"`
+	Method string               `json:"method"`
+	Params *accountNotification `json:"params"`
+}
+
+type accountNotification struct {
+	Subscription uint64        `json:"subscription"`
+	Result       accountResult `json:"result"`
+}
+
+type accountResult struct {
+	Context struct {
+		Slot uint64 `json:"slot"`
+	} `json:"context"`
+	Value *accountValue `json:"value"`
+}
+
+type accountValue struct {
+	Lamports uint64 `json:"lamports"`
+}